@@ -2,50 +2,131 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"flight-event-throttler/internal/alerts"
 	"flight-event-throttler/internal/api"
 	"flight-event-throttler/internal/buffer"
 	"flight-event-throttler/internal/config"
+	"flight-event-throttler/internal/enrichment"
 	"flight-event-throttler/internal/fetcher"
 	"flight-event-throttler/internal/metrics"
 	"flight-event-throttler/internal/model"
 	"flight-event-throttler/internal/processor"
+	"flight-event-throttler/internal/redaction"
+	"flight-event-throttler/internal/sink"
 	"flight-event-throttler/pkg/logger"
 )
 
 func main() {
-	// Load configuration
-	cfg, err := config.Load("configs/config.yaml")
+	// Load configuration, layering any files listed in CONFIG_FILES in order
+	configPaths := []string{"configs/config.yaml"}
+	if files := os.Getenv("CONFIG_FILES"); files != "" {
+		configPaths = strings.Split(files, ",")
+	}
+	cfg, err := config.LoadLayered(configPaths...)
 	if err != nil {
 		fmt.Printf("Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Initialize logger
-	log := logger.New(cfg.Logging.Level)
+	var logOut io.Writer = os.Stdout
+	var logFile *os.File
+	if cfg.Logging.File != "" {
+		logFile, err = os.OpenFile(cfg.Logging.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			fmt.Printf("Failed to open log file: %v\n", err)
+			os.Exit(1)
+		}
+		logOut = logFile
+	}
+
+	log := logger.NewWithWriter(cfg.Logging.Level, logOut)
+	if cfg.Logging.Format == "json" {
+		log = logger.NewJSONWithWriter(cfg.Logging.Level, logOut)
+	}
+	if cfg.Logging.File == "" {
+		log.SetErrorWriter(os.Stderr)
+	}
 	log.Info("Starting Flight Event Throttler...")
 	log.Info("Configuration loaded successfully")
 
 	// Initialize metrics collector
 	metricsCollector := metrics.NewMetrics()
+	if err := metricsCollector.SetAPILatencyBuckets(cfg.Metrics.APILatencyBuckets); err != nil {
+		log.Error("Invalid API latency buckets: %v", err)
+		os.Exit(1)
+	}
+	if err := metricsCollector.SetRateWindow(cfg.Metrics.RateWindow); err != nil {
+		log.Error("Invalid metrics rate window: %v", err)
+		os.Exit(1)
+	}
 	log.Info("Metrics collector initialized")
 
+	// Restore cumulative counters from the previous run
+	if cfg.Metrics.Persist {
+		if err := metricsCollector.Load(cfg.Metrics.PersistPath); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				log.Info("No persisted metrics found at %s, starting from zero", cfg.Metrics.PersistPath)
+			} else {
+				log.Error("Failed to restore metrics: %v", err)
+			}
+		} else {
+			log.Info("Metrics restored from %s", cfg.Metrics.PersistPath)
+		}
+	}
+
 	// Initialize buffer based on configuration
-	var ringBuf *buffer.RingBuffer
+	var ringBuf *buffer.FlightEventRingBuffer
 	var slidingWin *buffer.SlidingWindowBuffer
 
 	if cfg.Buffer.Type == "ring" {
-		ringBuf = buffer.NewRingBuffer(cfg.Buffer.Size)
-		log.Info("Ring buffer initialized with size %d", cfg.Buffer.Size)
+		if cfg.Buffer.OverflowPath != "" {
+			ringBuf, err = buffer.NewRingBufferWithOverflow[*model.FlightEvent](cfg.Buffer.Size, cfg.Buffer.OverflowPath)
+			if err != nil {
+				log.Error("Failed to create ring buffer: %v", err)
+				os.Exit(1)
+			}
+			log.Info("Ring buffer initialized with size %d, spilling overflow to %s", cfg.Buffer.Size, cfg.Buffer.OverflowPath)
+		} else {
+			ringBuf = buffer.NewFlightEventRingBuffer(cfg.Buffer.Size)
+			log.Info("Ring buffer initialized with size %d", cfg.Buffer.Size)
+		}
+
+		// Only pay for the ICAO24 index when deduplication is on
+		if cfg.Buffer.Dedup {
+			ringBuf.SetKey(buffer.FlightEventKey)
+		}
 	} else {
 		slidingWin = buffer.NewSlidingWindowBuffer(cfg.RateLimit.WindowDuration, cfg.Buffer.Size)
 		log.Info("Sliding window buffer initialized with size %d and window %v", cfg.Buffer.Size, cfg.RateLimit.WindowDuration)
+		if cfg.Buffer.MaxBytes > 0 {
+			slidingWin.SetMaxBytes(cfg.Buffer.MaxBytes)
+			log.Info("Sliding window buffer memory capped at %d bytes", cfg.Buffer.MaxBytes)
+		}
+
+		// Restore the window saved by the previous run, dropping expired events
+		if cfg.Buffer.SnapshotPath != "" {
+			if err := slidingWin.LoadSnapshotFile(cfg.Buffer.SnapshotPath); err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					log.Info("No sliding window snapshot found at %s, starting empty", cfg.Buffer.SnapshotPath)
+				} else {
+					log.Error("Failed to restore sliding window snapshot: %v", err)
+				}
+			} else {
+				log.Info("Restored %d events from sliding window snapshot %s", slidingWin.Count(), cfg.Buffer.SnapshotPath)
+			}
+		}
 	}
 
 	// Update buffer metrics
@@ -53,62 +134,366 @@ func main() {
 
 	// Initialize rate limiter
 	rateLimiter := processor.NewRateLimiter(cfg.RateLimit.EventsPerSecond, cfg.RateLimit.BurstSize)
-	log.Info("Rate limiter initialized: %d events/sec, burst size %d", cfg.RateLimit.EventsPerSecond, cfg.RateLimit.BurstSize)
+	var limiter processor.Limiter = rateLimiter
+	if cfg.RateLimit.Algorithm == processor.AlgorithmLeakyBucket {
+		limiter = processor.NewLeakyBucketLimiter(cfg.RateLimit.EventsPerSecond)
+		log.Info("Leaky bucket rate limiter initialized: %d events/sec, no bursts", cfg.RateLimit.EventsPerSecond)
+	} else {
+		metricsCollector.SetRateLimiterTokensFunc(rateLimiter.Tokens)
+		log.Info("Rate limiter initialized: %d events/sec, burst size %d", cfg.RateLimit.EventsPerSecond, cfg.RateLimit.BurstSize)
+	}
+
+	// Optionally lower the rate limit as the buffer fills and raise it as it drains
+	var adaptive *processor.AdaptiveController
+	if cfg.RateLimit.Adaptive.Enabled {
+		adaptive = processor.NewAdaptiveController(rateLimiter, metricsCollector,
+			cfg.RateLimit.Adaptive.MinRate, cfg.RateLimit.Adaptive.MaxRate, cfg.RateLimit.Adaptive.Interval, log)
+		adaptive.Start()
+		log.Info("Adaptive rate limiting enabled: %d-%d events/sec", cfg.RateLimit.Adaptive.MinRate, cfg.RateLimit.Adaptive.MaxRate)
+	}
+
+	// Initialize optional per-aircraft throttle, separate from the global limiter
+	var aircraftThrottle *processor.AircraftThrottle
+	if cfg.RateLimit.PerAircraftInterval > 0 {
+		aircraftThrottle = processor.NewAircraftThrottle(cfg.RateLimit.PerAircraftInterval, cfg.RateLimit.PerAircraftTracked)
+		log.Info("Per-aircraft throttle initialized: one update per %v", cfg.RateLimit.PerAircraftInterval)
+	}
+
+	// Summarize dropped events periodically instead of logging each one
+	dropReporter := processor.NewDropReporter(log, cfg.Logging.DropSummaryInterval)
 
 	// Initialize event processor
-	eventProcessor := processor.NewEventProcessor(rateLimiter, cfg.Buffer.Size)
+	eventProcessor := processor.NewEventProcessor(limiter, cfg.Buffer.Size)
+	eventProcessor.OnDropped(func(event *model.FlightEvent) {
+		// Queue full, event dropped
+		metricsCollector.IncrementEventsDropped()
+		dropReporter.Record()
+	})
+	eventProcessor.SetEmergencySquawks(cfg.RateLimit.EmergencySquawks)
+	if cfg.RateLimit.PerAircraftRate > 0 {
+		eventProcessor.SetKeyedLimiter(processor.NewKeyedRateLimiter(cfg.RateLimit.PerAircraftRate, cfg.RateLimit.PerAircraftBurst))
+		log.Info("Per-aircraft rate limiter initialized: %d events/sec per aircraft, burst size %d",
+			cfg.RateLimit.PerAircraftRate, cfg.RateLimit.PerAircraftBurst)
+	}
 	eventProcessor.Start()
 	log.Info("Event processor started")
 
+	// Build redaction policy for served and forwarded events
+	redactionRules := make([]redaction.Rule, 0, len(cfg.Redaction.Rules))
+	for _, rule := range cfg.Redaction.Rules {
+		redactionRules = append(redactionRules, redaction.Rule{
+			ICAO24Ranges: rule.ICAO24Ranges,
+			Countries:    rule.Countries,
+			Fields:       rule.Fields,
+		})
+	}
+	redactionPolicy, err := redaction.NewPolicy(redactionRules)
+	if err != nil {
+		log.Error("Invalid redaction policy: %v", err)
+		os.Exit(1)
+	}
+	if len(redactionRules) > 0 {
+		log.Info("Redaction policy loaded with %d rules", len(redactionRules))
+	}
+
+	// Forward processed events to the configured sinks. Each sink has its
+	// own queue, retries and dead-letter file, so one failing destination
+	// never holds up the pipeline or the others.
+	var sinks *sink.Multiplexer
+	if len(cfg.Sink.Outputs) > 0 {
+		outputs, err := buildSinkOutputs(cfg.Sink)
+		if err != nil {
+			log.Error("Failed to open sinks: %v", err)
+			os.Exit(1)
+		}
+		keyFunc, err := sink.KeyFuncFor(cfg.Sink.PartitionKey)
+		if err != nil {
+			log.Error("Invalid sink partition key: %v", err)
+			os.Exit(1)
+		}
+		sinks = sink.NewMultiplexer(outputs, cfg.Sink.BatchSize, cfg.Sink.FlushInterval, cfg.Sink.QueueSize,
+			log.With(map[string]any{"component": "sink"}))
+		sinks.SetKeyFunc(keyFunc)
+		sinks.OnRetry(metricsCollector.IncrementSinkRetries)
+		sinks.OnDeadLetter(metricsCollector.AddSinkDeadLettered)
+		sinks.OnDropped(metricsCollector.AddSinkDropped)
+		sinks.Start()
+		log.Info("Sinks started: %d outputs, partitioned by %s", len(outputs), cfg.Sink.PartitionKey)
+	}
+
+	// Buffer events as they leave the processor, so the buffer only holds
+	// what the rate limiter has released, then fan them out to streaming
+	// clients. The hub never blocks, so the processor's output never backs
+	// up; both stop when the processor does.
+	bufferEvent := func(event *model.FlightEvent) {
+		metricsCollector.IncrementEventsProcessed()
+
+		if cfg.Buffer.Type == "ring" && ringBuf != nil {
+			if cfg.Buffer.Dedup {
+				// Replace the aircraft's previous state instead of appending
+				ringBuf.PushDedup(event)
+			} else {
+				ringBuf.Push(event)
+			}
+			metricsCollector.SetBufferSize(int64(ringBuf.Count()))
+		} else if cfg.Buffer.Type == "sliding_window" && slidingWin != nil {
+			slidingWin.Push(event)
+			metricsCollector.SetBufferSize(int64(slidingWin.Count()))
+		}
+	}
+	eventHub := api.NewEventHub()
+	eventHub.OnDropped(metricsCollector.IncrementStreamEventsDropped)
+	processed := make(chan *model.FlightEvent)
+	bufferDone := make(chan struct{})
+	go func() {
+		defer close(bufferDone)
+		defer close(processed)
+		for event := range eventProcessor.GetOutputChannel() {
+			bufferEvent(event)
+			if sinks != nil {
+				sinks.Publish(redactionPolicy.Apply(event))
+			}
+			processed <- event
+		}
+	}()
+	go eventHub.Run(processed)
+
 	// Initialize OpenSky API client
 	openSkyClient := fetcher.NewOpenSkyClient(
 		cfg.OpenSky.BaseURL,
 		cfg.OpenSky.RequestTimeout,
 		cfg.OpenSky.Username,
 		cfg.OpenSky.Password,
-		log,
+		log.With(map[string]any{"component": "fetcher"}),
 		metricsCollector,
 	)
+	openSkyClient.SetMaxConcurrentRequests(cfg.OpenSky.MaxConcurrentRequests)
+	openSkyClient.SetSkipIfBusy(cfg.OpenSky.SkipIfBusy)
+	openSkyClient.SetDropNullIsland(cfg.OpenSky.DropNullIsland)
+	openSkyClient.SetRetry(cfg.OpenSky.RetryMaxAttempts, cfg.OpenSky.RetryBaseDelay)
+	openSkyClient.SetCacheTTL(cfg.OpenSky.CacheTTL)
+
+	// The pipeline only depends on StateSource, so other feeds can replace OpenSky
+	var source fetcher.StateSource = openSkyClient
 	log.Info("OpenSky API client initialized: %s", cfg.OpenSky.BaseURL)
 
+	// Load optional aircraft database for event enrichment
+	var aircraftDB *enrichment.AircraftDB
+	if cfg.Enrichment.AircraftDB != "" {
+		aircraftDB, err = enrichment.LoadAircraftDB(cfg.Enrichment.AircraftDB)
+		if err != nil {
+			log.Error("Failed to load aircraft database: %v", err)
+			os.Exit(1)
+		}
+		log.Info("Aircraft database loaded: %d aircraft", aircraftDB.Count())
+	}
+
 	// Create context for managing goroutines
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Start OpenSky polling in background
-	go func() {
-		openSkyClient.PollContinuously(ctx, cfg.OpenSky.PollInterval, func(events []*model.FlightEvent) {
-			log.Debug("Received %d flight events from OpenSky API", len(events))
-			metricsCollector.IncrementEventsReceived()
-
-			for _, event := range events {
-				// Add timestamp to event
-				event.Timestamp = time.Now()
-
-				// Try to submit event to processor
-				if eventProcessor.Submit(event) {
-					// Successfully queued
-					metricsCollector.IncrementEventsProcessed()
-
-					// Add to buffer
-					if cfg.Buffer.Type == "ring" && ringBuf != nil {
-						ringBuf.Push(event)
-						metricsCollector.SetBufferSize(int64(ringBuf.Count()))
-					} else if cfg.Buffer.Type == "sliding_window" && slidingWin != nil {
-						slidingWin.Push(event)
-						metricsCollector.SetBufferSize(int64(slidingWin.Count()))
-					}
-				} else {
-					// Queue full, event dropped
-					metricsCollector.IncrementEventsDropped()
-					log.Debug("Event dropped: queue full")
-				}
+	// Push metrics to a Prometheus Pushgateway if configured
+	pushDone := make(chan struct{})
+	var pushExporter *metrics.PushgatewayExporter
+	if cfg.Metrics.Pushgateway.URL != "" {
+		pushExporter = metrics.NewPushgatewayExporter(
+			metricsCollector,
+			cfg.Metrics.Pushgateway.URL,
+			cfg.Metrics.Pushgateway.Job,
+			cfg.Metrics.Pushgateway.Labels,
+			cfg.Metrics.Pushgateway.Interval,
+			log,
+		)
+		go func() {
+			defer close(pushDone)
+			pushExporter.Run(ctx)
+		}()
+	} else {
+		close(pushDone)
+	}
+
+	// Reject events whose timestamps are implausibly far from now
+	timestampWindow := processor.TimestampWindow{
+		MaxPast:   cfg.Event.MaxPast,
+		MaxFuture: cfg.Event.MaxFuture,
+	}
+
+	// Start alert rule evaluation if rules are configured
+	if len(cfg.Alerts.Rules) > 0 {
+		alertRules := make([]alerts.Rule, 0, len(cfg.Alerts.Rules))
+		for _, rule := range cfg.Alerts.Rules {
+			alertRules = append(alertRules, alerts.Rule{
+				Name:      rule.Name,
+				Metric:    rule.Metric,
+				Operator:  rule.Operator,
+				Threshold: rule.Threshold,
+				Clear:     rule.Clear,
+				For:       rule.For,
+			})
+		}
+
+		var notifier alerts.Notifier = alerts.NewLogNotifier(log)
+		if cfg.Alerts.WebhookURL != "" {
+			notifier = alerts.NewWebhookNotifier(cfg.Alerts.WebhookURL)
+		}
+
+		evaluator, err := alerts.NewEvaluator(alertRules, metricsCollector, notifier, cfg.Alerts.Interval, log)
+		if err != nil {
+			log.Error("Invalid alert rules: %v", err)
+			os.Exit(1)
+		}
+		go evaluator.Run(ctx)
+		log.Info("Alert evaluator started with %d rules", len(alertRules))
+	}
+
+	// Start dropped-event summary reporter
+	go dropReporter.Run(ctx)
+
+	// Buffer snapshot as of the last successful poll, served by /events/lastgood
+	lastGood := buffer.NewLastGood()
+
+	// Initialize HTTP API server
+	apiServer := api.NewServer(log, metricsCollector, ringBuf, slidingWin, cfg.Buffer.Type, cfg.Server.AdminToken)
+	apiServer.SetBasePath(cfg.Server.BasePath)
+	apiServer.SetMaxEventsLimit(cfg.Server.MaxEventsLimit)
+	apiServer.SetMaxRequestBytes(cfg.Server.MaxRequestBytes)
+	apiServer.SetPollInterval(cfg.OpenSky.PollInterval)
+	apiServer.SetClientRateLimit(cfg.Server.ClientRateLimit.RequestsPerSecond, cfg.Server.ClientRateLimit.Burst,
+		cfg.Server.ClientRateLimit.TrustForwarded)
+	if err := apiServer.SetCORS(api.CORSPolicy{
+		AllowedOrigins:   cfg.Server.CORS.AllowedOrigins,
+		AllowCredentials: cfg.Server.CORS.AllowCredentials,
+		MaxAge:           cfg.Server.CORS.MaxAge,
+	}); err != nil {
+		log.Error("Invalid CORS policy: %v", err)
+		os.Exit(1)
+	}
+	apiServer.SetAdminEnabled(cfg.Server.EnableAdmin)
+	apiServer.SetRedactionPolicy(redactionPolicy)
+	apiServer.SetLastGood(lastGood)
+	apiServer.SetEventHub(eventHub)
+	apiServer.SetAutoscale(api.AutoscaleWeights{
+		Buffer:     cfg.Autoscale.BufferWeight,
+		Throughput: cfg.Autoscale.ThroughputWeight,
+		Drops:      cfg.Autoscale.DropWeight,
+	}, func() int {
+		eventsPerSec, _ := rateLimiter.GetLimit()
+		return eventsPerSec
+	})
+
+	// Run a batch of events through the pipeline: timestamp checks, the
+	// per-aircraft throttle, enrichment and the rate limits. Accepted events
+	// are returned and buffered once the processor releases them. Only trusted
+	// events, those polled from OpenSky, get the emergency bypass.
+	ingestEvents := func(events []*model.FlightEvent, trusted bool) []*model.FlightEvent {
+		metricsCollector.AddEventsReceived(int64(len(events)))
+
+		candidates := make([]*model.FlightEvent, 0, len(events))
+		for _, event := range events {
+			// Add timestamp to event from the configured source
+			now := time.Now()
+			event.StampTimestamp(cfg.Event.TimestampSource, now)
+			if !timestampWindow.Accept(event, now) {
+				metricsCollector.IncrementEventsRejected()
+				continue
 			}
+
+			// Drop updates arriving too soon for the same aircraft, except emergencies
+			emergency := trusted && eventProcessor.IsEmergency(event)
+			if aircraftThrottle != nil && !emergency && !aircraftThrottle.Allow(event.ICAO24, now) {
+				metricsCollector.IncrementEventsThrottled()
+				continue
+			}
+
+			// Attach registration and aircraft type when known
+			aircraftDB.Enrich(event)
+
+			candidates = append(candidates, event)
+		}
+
+		// Submit the batch: the token bucket releases as much as its tokens
+		// allow at once and paces the rest, the leaky bucket paces all of it.
+		// Events that don't fit in the queue are reported through the dropped
+		// hook.
+		if !trusted {
+			return eventProcessor.SubmitBatchLimited(candidates)
+		}
+		return eventProcessor.SubmitBatch(candidates)
+	}
+
+	// Handle each batch of events fetched from OpenSky
+	handleEvents := func(events []*model.FlightEvent) {
+		log.Debug("Received %d flight events from OpenSky API", len(events))
+		ingestEvents(events, true)
+
+		// Snapshot the buffer after the poll; events still queued behind the
+		// rate limiter are picked up by the next snapshot
+		if cfg.Buffer.Type == "ring" && ringBuf != nil {
+			lastGood.Store(ringBuf.GetAll(), time.Now())
+		} else if cfg.Buffer.Type == "sliding_window" && slidingWin != nil {
+			lastGood.Store(slidingWin.GetAll(), time.Now())
+		}
+
+		metricsCollector.SetLastSuccessfulPoll(time.Now())
+
+		// The first successful poll makes the service ready to serve traffic
+		apiServer.SetReady(true)
+	}
+
+	// Let external producers push events through the same pipeline. Their
+	// squawks can't be trusted, so pushed emergencies are rate limited too.
+	if cfg.Server.Ingest.Enabled {
+		apiServer.SetIngest(func(events []*model.FlightEvent) []*model.FlightEvent {
+			return ingestEvents(events, false)
 		})
-	}()
+	}
 
-	// Initialize HTTP API server
-	apiServer := api.NewServer(log, metricsCollector, ringBuf, slidingWin, cfg.Buffer.Type)
+	// Start polling in background, warming up the buffer first if configured.
+	// The poller is relaunched whenever the watchdog requests a restart.
+	restartPoller := make(chan struct{}, 1)
+	go runPoller(ctx, source, cfg.OpenSky, func() bool {
+		return metricsCollector.GetBufferUtilization() >= cfg.OpenSky.WarmupTargetFill
+	}, handleEvents, restartPoller, log)
+
+	// Reload the rate limit and log level on SIGHUP
+	configWatcher := config.NewWatcher(cfg, log, configPaths...)
+	configWatcher.OnReload(func(previous, next *config.Config) {
+		log.SetLevel(next.Logging.Level)
+
+		if next.RateLimit.EventsPerSecond == previous.RateLimit.EventsPerSecond &&
+			next.RateLimit.BurstSize == previous.RateLimit.BurstSize {
+			return
+		}
+		switch {
+		case cfg.RateLimit.Algorithm == processor.AlgorithmLeakyBucket:
+			log.Warn("Rate limit changes require a restart with the leaky bucket limiter")
+		case adaptive != nil:
+			log.Warn("Rate limit is managed by adaptive rate limiting; change rate_limit.adaptive and restart instead")
+		default:
+			rateLimiter.UpdateLimit(next.RateLimit.EventsPerSecond, next.RateLimit.BurstSize)
+		}
+	})
+	go configWatcher.Run(ctx)
+	apiServer.SetConfigSource(configWatcher.Current)
+
+	// Start pipeline stall watchdog
+	if cfg.Watchdog.StallThreshold > 0 {
+		watchdog := processor.NewWatchdog(metricsCollector, cfg.Watchdog.StallThreshold, log)
+		switch cfg.Watchdog.Action {
+		case processor.WatchdogActionRestartPoller:
+			watchdog.OnStall(func() {
+				select {
+				case restartPoller <- struct{}{}:
+				default:
+				}
+			})
+		case processor.WatchdogActionMarkUnready:
+			watchdog.OnStall(func() { apiServer.SetStalled(true) })
+			watchdog.OnRecover(func() { apiServer.SetStalled(false) })
+		}
+		go watchdog.Run(ctx)
+		log.Info("Pipeline watchdog started (threshold: %v, action: %s)", cfg.Watchdog.StallThreshold, cfg.Watchdog.Action)
+	}
 
 	// Setup HTTP routes
 	mux := http.NewServeMux()
@@ -134,11 +519,34 @@ func main() {
 
 	log.Info("Flight Event Throttler is running")
 	log.Info("Available endpoints:")
-	log.Info("  - GET /health       - Health check")
-	log.Info("  - GET /metrics      - System metrics")
-	log.Info("  - GET /events       - Get all buffered events")
-	log.Info("  - GET /events/batch - Get batch of events")
-	log.Info("  - GET /buffer/stats - Buffer statistics")
+	basePath := apiServer.BasePath()
+	log.Info("  - GET %s/health       - Health check", basePath)
+	log.Info("  - GET %s/readyz       - Readiness check", basePath)
+	log.Info("  - GET %s/metrics      - System metrics", basePath)
+	log.Info("  - GET %s/metrics/prometheus - Metrics in Prometheus text format", basePath)
+	if cfg.Server.AdminToken != "" {
+		log.Info("  - POST %s/metrics/reset - Reset metrics (X-Admin-Token)", basePath)
+		log.Info("  - GET %s/config       - Effective config with secrets redacted (X-Admin-Token)", basePath)
+	}
+	log.Info("  - GET %s/events       - Get all buffered events", basePath)
+	if cfg.Server.Ingest.Enabled {
+		log.Info("  - POST %s/events      - Push events from external producers (X-Admin-Token)", basePath)
+	}
+	log.Info("  - GET %s/events/batch - Get batch of events", basePath)
+	log.Info("  - GET %s/events/top   - Top-N aircraft by velocity or altitude", basePath)
+	log.Info("  - GET %s/events/aggregate - Grouped aggregates over buffered events", basePath)
+	log.Info("  - GET %s/events/lastgood - Buffer snapshot from the last successful poll", basePath)
+	log.Info("  - GET %s/events/stream - Live events as Server-Sent Events", basePath)
+	log.Info("  - GET %s/events/near  - Events within a radius of a point, nearest first", basePath)
+	log.Info("  - GET %s/events/{icao24} - Latest state of one aircraft", basePath)
+	log.Info("  - GET %s/ws           - Live events over WebSocket with per-connection filters", basePath)
+	log.Info("  - GET %s/stats/top    - Busiest origin countries or callsigns", basePath)
+	log.Info("  - GET %s/buffer/stats - Buffer statistics", basePath)
+	log.Info("  - GET %s/buffer/coverage - Aircraft per receiver sensor", basePath)
+	log.Info("  - GET %s/autoscale    - Autoscaling pressure signal", basePath)
+	if cfg.Server.EnableAdmin {
+		log.Info("  - GET %s/buffer/export - Export buffer as NDJSON (admin)", basePath)
+	}
 
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
@@ -149,11 +557,29 @@ func main() {
 
 	// Cancel context to stop background goroutines
 	cancel()
+	if adaptive != nil {
+		adaptive.Stop()
+	}
 
 	// Stop event processor
 	eventProcessor.Stop()
+	<-bufferDone
 	log.Info("Event processor stopped")
 
+	// Deliver what the sinks still hold, including pending retries
+	if sinks != nil {
+		sinks.Close()
+		log.Info("Sinks flushed")
+	}
+
+	// Close the ring buffer, releasing long-polling requests and the
+	// overflow file; spilled events stay on disk
+	if ringBuf != nil {
+		if err := ringBuf.Close(); err != nil {
+			log.Error("Failed to close overflow file: %v", err)
+		}
+	}
+
 	// Shutdown HTTP server
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
@@ -162,13 +588,103 @@ func main() {
 		log.Error("HTTP server forced to shutdown: %v", err)
 	}
 
+	// Stop periodic pushes, then push the final counts now that the
+	// pipeline has drained
+	<-pushDone
+	if pushExporter != nil {
+		pushExporter.PushFinal()
+	}
+
+	// Save the sliding window so the next run can resume without a gap
+	if slidingWin != nil && cfg.Buffer.SnapshotPath != "" {
+		if err := slidingWin.SaveSnapshotFile(cfg.Buffer.SnapshotPath, cfg.Buffer.PersistCompress); err != nil {
+			log.Error("Failed to save sliding window snapshot: %v", err)
+		} else {
+			log.Info("Sliding window snapshot saved to %s", cfg.Buffer.SnapshotPath)
+		}
+	}
+
+	// Persist cumulative counters for the next run
+	if cfg.Metrics.Persist {
+		if err := metricsCollector.Save(cfg.Metrics.PersistPath); err != nil {
+			log.Error("Failed to persist metrics: %v", err)
+		} else {
+			log.Info("Metrics persisted to %s", cfg.Metrics.PersistPath)
+		}
+	}
+	metricsCollector.Close()
+
 	// Print final metrics
 	snapshot := metricsCollector.GetSnapshot()
 	log.Info("Final metrics:")
 	log.Info("  Events received: %d", snapshot.EventsReceived)
 	log.Info("  Events processed: %d", snapshot.EventsProcessed)
 	log.Info("  Events dropped: %d", snapshot.EventsDropped)
+	runDropped := metricsCollector.GetRunEventsDropped()
 	log.Info("  Uptime: %d seconds", snapshot.UptimeSeconds)
 
+	exitCode := 0
+	// Only this run's drops count: not those restored from a previous run,
+	// and not undone by a metrics reset
+	thresholdExceeded := dropThresholdExceeded(cfg.Shutdown.MaxDroppedEvents, runDropped)
+	if thresholdExceeded {
+		log.Error("Dropped %d events during this run, above threshold of %d", runDropped, cfg.Shutdown.MaxDroppedEvents)
+		exitCode = 1
+	}
+
+	// Write machine-readable run summary if configured
+	if cfg.Shutdown.SummaryPath != "" {
+		summary := runSummary{
+			Metrics:           snapshot,
+			RunEventsDropped:  runDropped,
+			MaxDroppedEvents:  cfg.Shutdown.MaxDroppedEvents,
+			ThresholdExceeded: thresholdExceeded,
+			ExitCode:          exitCode,
+		}
+		if err := writeRunSummary(cfg.Shutdown.SummaryPath, summary); err != nil {
+			log.Error("Failed to write run summary: %v", err)
+		} else {
+			log.Info("Run summary written to %s", cfg.Shutdown.SummaryPath)
+		}
+	}
+
 	log.Info("Server stopped successfully")
+
+	// os.Exit skips deferred calls, so flush and close the log file here
+	if logFile != nil {
+		if err := logFile.Sync(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to sync log file: %v\n", err)
+		}
+		logFile.Close()
+	}
+	os.Exit(exitCode)
+}
+
+// runSummary is the machine-readable summary written on shutdown
+type runSummary struct {
+	Metrics           *metrics.Snapshot `json:"metrics"`
+	RunEventsDropped  int64             `json:"run_events_dropped"` // Compared against MaxDroppedEvents
+	MaxDroppedEvents  int64             `json:"max_dropped_events"`
+	ThresholdExceeded bool              `json:"threshold_exceeded"`
+	ExitCode          int               `json:"exit_code"`
+}
+
+// dropThresholdExceeded reports whether more events were dropped than the
+// configured threshold allows. A threshold of 0 disables the check.
+func dropThresholdExceeded(threshold, dropped int64) bool {
+	return threshold > 0 && dropped > threshold
+}
+
+// writeRunSummary writes the run summary as JSON to the given path
+func writeRunSummary(path string, summary runSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run summary: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run summary: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"flight-event-throttler/internal/metrics"
+)
+
+func TestDropThresholdExceeded(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int64
+		dropped   int64
+		want      bool
+	}{
+		{"disabled", 0, 100, false},
+		{"below", 10, 5, false},
+		{"at threshold", 10, 10, false},
+		{"above", 10, 11, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dropThresholdExceeded(tt.threshold, tt.dropped); got != tt.want {
+				t.Errorf("dropThresholdExceeded(%d, %d) = %v, want %v", tt.threshold, tt.dropped, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRunSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.json")
+	summary := runSummary{
+		Metrics:           &metrics.Snapshot{EventsReceived: 10, EventsDropped: 3},
+		MaxDroppedEvents:  2,
+		ThresholdExceeded: true,
+		ExitCode:          1,
+	}
+
+	if err := writeRunSummary(path, summary); err != nil {
+		t.Fatalf("writeRunSummary: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read summary: %v", err)
+	}
+
+	var got runSummary
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("summary is not valid JSON: %v", err)
+	}
+	if got.ExitCode != 1 || !got.ThresholdExceeded || got.MaxDroppedEvents != 2 {
+		t.Errorf("summary = %+v, want exit code 1 with threshold exceeded", got)
+	}
+	if got.Metrics == nil || got.Metrics.EventsDropped != 3 {
+		t.Errorf("summary metrics = %+v, want 3 dropped events", got.Metrics)
+	}
+}
+
+func TestWriteRunSummaryBadPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "summary.json")
+	if err := writeRunSummary(path, runSummary{}); err == nil {
+		t.Error("expected an error writing to a missing directory")
+	}
+}
+
+func TestRestoredDropsDoNotTripThreshold(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+
+	previous := metrics.NewMetrics()
+	defer previous.Close()
+	for i := 0; i < 50; i++ {
+		previous.IncrementEventsDropped()
+	}
+	if err := previous.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	m := metrics.NewMetrics()
+	defer m.Close()
+	if err := m.Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	m.IncrementEventsDropped()
+
+	if dropThresholdExceeded(10, m.GetRunEventsDropped()) {
+		t.Errorf("threshold of 10 exceeded with %d restored drops and 1 new one", m.GetEventsDropped()-1)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+
+	"flight-event-throttler/internal/config"
+	"flight-event-throttler/internal/fetcher"
+	"flight-event-throttler/internal/model"
+	"flight-event-throttler/pkg/logger"
+)
+
+// runPoller feeds events from source to handleEvents until ctx is cancelled,
+// warming up first when configured. Polling is relaunched whenever restart
+// fires. Warmup and bounding boxes are used when the source supports them;
+// a bounding box the source can't poll stops the poller rather than silently
+// polling the full feed.
+func runPoller(ctx context.Context, source fetcher.StateSource, cfg config.OpenSkyConfig, warmupDone func() bool,
+	handleEvents func([]*model.FlightEvent), restart <-chan struct{}, log logger.Interface) {
+	if cfg.WarmupPolls > 0 {
+		if warmer, ok := source.(fetcher.WarmupSource); ok {
+			warmer.Warmup(ctx, cfg.WarmupPolls, cfg.WarmupInterval, warmupDone, handleEvents)
+		} else {
+			log.Warn("Event source does not support warmup, skipping it")
+		}
+	}
+
+	for {
+		pollCtx, cancelPoll := context.WithCancel(ctx)
+		go func() {
+			select {
+			case <-restart:
+				cancelPoll()
+			case <-pollCtx.Done():
+			}
+		}()
+
+		if box := cfg.BoundingBox; box != nil {
+			regional, ok := source.(fetcher.BoundingBoxSource)
+			if !ok {
+				log.Error("Polling not started: event source does not support bounding boxes")
+				cancelPoll()
+				return
+			}
+			if err := regional.PollBoundingBox(pollCtx, cfg.PollInterval, box.LaMin, box.LoMin, box.LaMax, box.LoMax, handleEvents); err != nil {
+				log.Error("Polling not started: %v", err)
+				cancelPoll()
+				return
+			}
+		} else {
+			source.PollContinuously(pollCtx, cfg.PollInterval, handleEvents)
+		}
+		cancelPoll()
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warn("Restarting poller")
+	}
+}
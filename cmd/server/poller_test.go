@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/buffer"
+	"flight-event-throttler/internal/config"
+	"flight-event-throttler/internal/fetcher"
+	"flight-event-throttler/internal/model"
+	"flight-event-throttler/internal/processor"
+	"flight-event-throttler/pkg/logger"
+)
+
+// stateRow is an OpenSky state vector for icao24 over Zurich
+func stateRow(icao24 string) []interface{} {
+	return []interface{}{
+		icao24, "TEST123 ", "Testland", 1700000000.0, 1700000001.0,
+		8.5, 47.4, 10000.0, false, 250.0,
+		90.0, 0.0, nil, 10100.0, "1000", false, 0.0,
+	}
+}
+
+func TestPipelineWithMockSource(t *testing.T) {
+	log := logger.NewWithWriter("ERROR", io.Discard)
+	source := fetcher.NewMockSource([]*model.OpenSkyResponse{
+		{Time: 1700000000, States: [][]interface{}{stateRow("aaa111"), stateRow("bbb222")}},
+		{Time: 1700000010, States: [][]interface{}{stateRow("ccc333")}},
+	}, log)
+
+	ep := processor.NewEventProcessor(processor.NewRateLimiter(1000, 100), 100)
+	ep.Start()
+	defer ep.Stop()
+
+	ring := buffer.NewFlightEventRingBuffer(10)
+	buffered := make(chan struct{})
+	go func() {
+		for event := range ep.GetOutputChannel() {
+			ring.Push(event)
+			buffered <- struct{}{}
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cfg := config.OpenSkyConfig{PollInterval: time.Millisecond}
+		runPoller(ctx, source, cfg, func() bool { return true }, func(events []*model.FlightEvent) {
+			ep.SubmitBatch(events)
+		}, nil, log)
+	}()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-buffered:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("only %d of 3 events reached the buffer", i)
+		}
+	}
+
+	var got []string
+	for _, event := range ring.GetAll() {
+		got = append(got, event.ICAO24)
+	}
+	want := []string{"aaa111", "bbb222", "ccc333"}
+	if len(got) != len(want) {
+		t.Fatalf("buffered %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("buffered %v, want %v", got, want)
+			break
+		}
+	}
+
+	// Once the mock runs dry the poller is relaunched until ctx is cancelled
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("runPoller did not return after ctx was cancelled")
+	}
+	if remaining := source.Remaining(); remaining != 0 {
+		t.Errorf("Remaining() = %d, want 0", remaining)
+	}
+}
+
+func TestRunPollerStopsWithoutBoundingBoxSupport(t *testing.T) {
+	log := logger.NewWithWriter("ERROR", io.Discard)
+	source := fetcher.NewMockSource([]*model.OpenSkyResponse{
+		{Time: 1700000000, States: [][]interface{}{stateRow("aaa111")}},
+	}, log)
+	cfg := config.OpenSkyConfig{
+		PollInterval: time.Millisecond,
+		BoundingBox:  &config.BoundingBoxConfig{LaMin: 45, LoMin: 5, LaMax: 48, LoMax: 10},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runPoller(context.Background(), source, cfg, nil, func([]*model.FlightEvent) {
+			t.Error("events delivered although the source cannot poll a bounding box")
+		}, nil, log)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("runPoller kept running with an unsupported bounding box")
+	}
+	if remaining := source.Remaining(); remaining != 1 {
+		t.Errorf("Remaining() = %d, want 1 (no polls)", remaining)
+	}
+}
+
+// warmingSource is a MockSource that records its warmup call
+type warmingSource struct {
+	*fetcher.MockSource
+	polls    int
+	interval time.Duration
+	pending  int // Responses not yet polled when warmup started
+	reached  bool
+}
+
+func (s *warmingSource) Warmup(ctx context.Context, polls int, interval time.Duration, done func() bool, callback func([]*model.FlightEvent)) {
+	s.polls, s.interval = polls, interval
+	s.pending = s.Remaining()
+	s.reached = done()
+}
+
+func TestRunPollerWarmsUpBeforePolling(t *testing.T) {
+	log := logger.NewWithWriter("ERROR", io.Discard)
+	source := &warmingSource{MockSource: fetcher.NewMockSource([]*model.OpenSkyResponse{
+		{Time: 1700000000, States: [][]interface{}{stateRow("aaa111")}},
+		{Time: 1700000010, States: [][]interface{}{stateRow("bbb222")}},
+	}, log)}
+	cfg := config.OpenSkyConfig{PollInterval: time.Millisecond, WarmupPolls: 3, WarmupInterval: 5 * time.Second}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan string, 2)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runPoller(ctx, source, cfg, func() bool { return true }, func(batch []*model.FlightEvent) {
+			for _, event := range batch {
+				events <- event.ICAO24
+			}
+		}, nil, log)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-events:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("only %d of 2 events polled after warmup", i)
+		}
+	}
+	cancel()
+	<-done
+
+	if source.polls != 3 || source.interval != 5*time.Second {
+		t.Errorf("Warmup(%d, %v), want Warmup(3, 5s) from the config", source.polls, source.interval)
+	}
+	if source.pending != 2 {
+		t.Errorf("%d responses pending at warmup, want 2 (warmup runs before polling)", source.pending)
+	}
+	if !source.reached {
+		t.Error("warmup target function was not passed through")
+	}
+}
+
+func TestRunPollerSkipsWarmupWhenUnsupported(t *testing.T) {
+	log := logger.NewWithWriter("ERROR", io.Discard)
+	source := fetcher.NewMockSource([]*model.OpenSkyResponse{
+		{Time: 1700000000, States: [][]interface{}{stateRow("aaa111")}},
+	}, log)
+	cfg := config.OpenSkyConfig{PollInterval: time.Millisecond, WarmupPolls: 3, WarmupInterval: time.Hour}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	polled := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runPoller(ctx, source, cfg, nil, func([]*model.FlightEvent) { polled <- struct{}{} }, nil, log)
+	}()
+
+	// Polling starts right away instead of waiting on warmup intervals
+	select {
+	case <-polled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("source without warmup support was never polled")
+	}
+	cancel()
+	<-done
+}
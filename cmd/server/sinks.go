@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+
+	"flight-event-throttler/internal/config"
+	"flight-event-throttler/internal/sink"
+)
+
+// buildSinkOutputs opens every configured output and its dead-letter file,
+// closing whatever was already opened if one fails
+func buildSinkOutputs(cfg config.SinkConfig) ([]sink.Output, error) {
+	var opened []sink.Sink
+	fail := func(err error) ([]sink.Output, error) {
+		for _, s := range opened {
+			s.Close()
+		}
+		return nil, err
+	}
+
+	outputs := make([]sink.Output, 0, len(cfg.Outputs))
+	for _, out := range cfg.Outputs {
+		output := sink.Output{
+			Name:  out.Name,
+			Retry: sink.RetryPolicy{Retries: out.Retries, BaseDelay: out.RetryBaseDelay},
+		}
+
+		switch out.Type {
+		case "file":
+			fileSink, err := sink.NewFileSink(out.Path)
+			if err != nil {
+				return fail(fmt.Errorf("sink %s: %w", out.Name, err))
+			}
+			output.Sink = fileSink
+		case "http":
+			output.Sink = sink.NewHTTPSink(out.URL)
+		}
+		opened = append(opened, output.Sink)
+
+		if out.DLQ != "" {
+			dlq, err := sink.NewFileSink(out.DLQ)
+			if err != nil {
+				return fail(fmt.Errorf("sink %s dead-letter file: %w", out.Name, err))
+			}
+			output.DeadLetter = dlq
+			opened = append(opened, dlq)
+		}
+
+		outputs = append(outputs, output)
+	}
+	return outputs, nil
+}
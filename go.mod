@@ -3,6 +3,7 @@ module flight-event-throttler
 go 1.22.5
 
 require (
+	github.com/gorilla/websocket v1.5.3
 	golang.org/x/time v0.8.0
 	gopkg.in/yaml.v3 v3.0.1
 )
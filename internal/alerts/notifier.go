@@ -0,0 +1,97 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"flight-event-throttler/pkg/logger"
+)
+
+// Alert states
+const (
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+// Alert describes a rule transitioning between firing and resolved
+type Alert struct {
+	Rule      string    `json:"rule"`
+	Metric    string    `json:"metric"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	State     string    `json:"state"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Notifier delivers alerts to an external destination
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// WebhookNotifier posts alerts as JSON to a webhook URL
+type WebhookNotifier struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a notifier posting to the given URL
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url: url,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Notify posts the alert to the webhook
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// LogNotifier writes alerts to the log, used when no webhook is configured
+type LogNotifier struct {
+	logger logger.Interface
+}
+
+// NewLogNotifier creates a notifier writing to the given logger
+func NewLogNotifier(log logger.Interface) *LogNotifier {
+	return &LogNotifier{logger: log}
+}
+
+// Notify logs the alert as a warning when firing and info when resolved
+func (n *LogNotifier) Notify(ctx context.Context, alert Alert) error {
+	if alert.State == StateFiring {
+		n.logger.Warn("Alert %s firing: %s=%.2f crossed %.2f", alert.Rule, alert.Metric, alert.Value, alert.Threshold)
+	} else {
+		n.logger.Info("Alert %s resolved: %s=%.2f", alert.Rule, alert.Metric, alert.Value)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"flight-event-throttler/internal/metrics"
+	"flight-event-throttler/pkg/logger"
+)
+
+// Rule fires an alert when a derived metric crosses a threshold for a duration
+type Rule struct {
+	Name      string
+	Metric    string        // "drop_rate", "events_per_second", "buffer_utilization", or "api_error_rate"
+	Operator  string        // ">" or "<"
+	Threshold float64       // Value that must be crossed to fire
+	Clear     float64       // Value that must be crossed back to resolve; defaults to Threshold
+	For       time.Duration // How long the condition must hold before firing
+}
+
+// metricExtractors derive rule metrics from a snapshot. Rates are percentages
+// over the metrics rate window, so alerts resolve once a bad spell has passed.
+var metricExtractors = map[string]func(s *metrics.Snapshot) float64{
+	"drop_rate": func(s *metrics.Snapshot) float64 {
+		return s.RecentDropRate * 100
+	},
+	"events_per_second": func(s *metrics.Snapshot) float64 {
+		return float64(s.EventsPerSecond)
+	},
+	"buffer_utilization": func(s *metrics.Snapshot) float64 {
+		return s.BufferUtilization
+	},
+	"api_error_rate": func(s *metrics.Snapshot) float64 {
+		return s.RecentAPIErrorRate * 100
+	},
+}
+
+// Validate checks that the rule references a known metric and operator
+func (r Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("alert rule name cannot be empty")
+	}
+	if _, ok := metricExtractors[r.Metric]; !ok {
+		return fmt.Errorf("alert rule %q has unsupported metric %q", r.Name, r.Metric)
+	}
+	if r.Operator != ">" && r.Operator != "<" {
+		return fmt.Errorf("alert rule %q operator must be '>' or '<'", r.Name)
+	}
+	if r.For < 0 {
+		return fmt.Errorf("alert rule %q duration cannot be negative", r.Name)
+	}
+	return nil
+}
+
+// breached reports whether the value crosses the firing threshold
+func (r Rule) breached(value float64) bool {
+	if r.Operator == ">" {
+		return value > r.Threshold
+	}
+	return value < r.Threshold
+}
+
+// recovered reports whether the value has crossed back past the clear threshold
+func (r Rule) recovered(value float64) bool {
+	if r.Operator == ">" {
+		return value <= r.Clear
+	}
+	return value >= r.Clear
+}
+
+// ruleState tracks a rule's progress between evaluations
+type ruleState struct {
+	pendingSince time.Time
+	firing       bool
+}
+
+// Evaluator periodically evaluates rules against the metrics snapshot and
+// dispatches firing and resolved alerts through a notifier
+type Evaluator struct {
+	rules    []Rule
+	states   []ruleState
+	metrics  *metrics.Metrics
+	notifier Notifier
+	interval time.Duration
+	logger   logger.Interface
+	mu       sync.Mutex
+}
+
+// NewEvaluator creates a new evaluator. Rules without an explicit clear
+// threshold resolve as soon as the firing threshold is no longer crossed.
+func NewEvaluator(rules []Rule, m *metrics.Metrics, notifier Notifier, interval time.Duration, log logger.Interface) (*Evaluator, error) {
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return nil, err
+		}
+		if rules[i].Clear == 0 {
+			rules[i].Clear = rules[i].Threshold
+		}
+	}
+
+	return &Evaluator{
+		rules:    rules,
+		states:   make([]ruleState, len(rules)),
+		metrics:  m,
+		notifier: notifier,
+		interval: interval,
+		logger:   log,
+	}, nil
+}
+
+// Run evaluates rules every interval until the context is cancelled
+func (e *Evaluator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.Evaluate(ctx, time.Now())
+		}
+	}
+}
+
+// Evaluate checks all rules once against the current snapshot. Alerts are
+// sent after the rule state is updated, so a slow notifier never blocks a
+// concurrent evaluation.
+func (e *Evaluator) Evaluate(ctx context.Context, now time.Time) {
+	for _, alert := range e.transitions(now) {
+		e.dispatch(ctx, alert)
+	}
+}
+
+// transitions advances every rule's state and returns the alerts for rules
+// that started firing or resolved
+func (e *Evaluator) transitions(now time.Time) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var alerts []Alert
+	snapshot := e.metrics.GetSnapshot()
+
+	for i, rule := range e.rules {
+		state := &e.states[i]
+		value := metricExtractors[rule.Metric](snapshot)
+
+		if state.firing {
+			// Hysteresis: stay firing until the value crosses the clear threshold
+			if rule.recovered(value) {
+				state.firing = false
+				state.pendingSince = time.Time{}
+				alerts = append(alerts, Alert{Rule: rule.Name, Metric: rule.Metric, Value: value, Threshold: rule.Clear, State: StateResolved, Timestamp: now})
+			}
+			continue
+		}
+
+		if !rule.breached(value) {
+			state.pendingSince = time.Time{}
+			continue
+		}
+
+		if state.pendingSince.IsZero() {
+			state.pendingSince = now
+		}
+		if now.Sub(state.pendingSince) >= rule.For {
+			state.firing = true
+			alerts = append(alerts, Alert{Rule: rule.Name, Metric: rule.Metric, Value: value, Threshold: rule.Threshold, State: StateFiring, Timestamp: now})
+		}
+	}
+
+	return alerts
+}
+
+// dispatch sends an alert through the notifier, logging failures
+func (e *Evaluator) dispatch(ctx context.Context, alert Alert) {
+	if err := e.notifier.Notify(ctx, alert); err != nil {
+		e.logger.Error("Failed to dispatch alert %s: %v", alert.Rule, err)
+	}
+}
@@ -0,0 +1,66 @@
+package alerts
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/metrics"
+	"flight-event-throttler/pkg/logger"
+)
+
+// blockingNotifier records alerts and blocks each Notify until released
+type blockingNotifier struct {
+	mu      sync.Mutex
+	alerts  []Alert
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (n *blockingNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.mu.Lock()
+	n.alerts = append(n.alerts, alert)
+	n.mu.Unlock()
+
+	n.entered <- struct{}{}
+	<-n.release
+	return nil
+}
+
+func TestEvaluateNotifiesOutsideLock(t *testing.T) {
+	m := metrics.NewMetrics()
+	defer m.Close()
+	m.SetBufferSize(90)
+	m.SetBufferCapacity(100)
+
+	notifier := &blockingNotifier{entered: make(chan struct{}, 1), release: make(chan struct{})}
+	rules := []Rule{{Name: "full", Metric: "buffer_utilization", Operator: ">", Threshold: 80}}
+	evaluator, err := NewEvaluator(rules, m, notifier, time.Second, logger.New("ERROR"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	go evaluator.Evaluate(context.Background(), now)
+	<-notifier.entered
+
+	// A second evaluation must not wait for the stalled notification
+	finished := make(chan struct{})
+	go func() {
+		evaluator.Evaluate(context.Background(), now.Add(time.Second))
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(2 * time.Second):
+		t.Fatal("evaluation blocked behind an in-flight notification")
+	}
+	close(notifier.release)
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	if len(notifier.alerts) != 1 || notifier.alerts[0].State != StateFiring {
+		t.Errorf("alerts = %+v, want one firing alert", notifier.alerts)
+	}
+}
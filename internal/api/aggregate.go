@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// altitudeBandMeters is the width of each altitude band used for grouping
+const altitudeBandMeters = 3000
+
+// aggregateGroupers maps the allowed group_by values to a function deriving the group key
+var aggregateGroupers = map[string]func(event *model.FlightEvent) string{
+	"country": func(event *model.FlightEvent) string {
+		if event.OriginCountry == "" {
+			return "unknown"
+		}
+		return event.OriginCountry
+	},
+	"on_ground": func(event *model.FlightEvent) string {
+		return strconv.FormatBool(event.OnGround)
+	},
+	"altitude_band": func(event *model.FlightEvent) string {
+		if event.OnGround {
+			return "ground"
+		}
+		if event.BaroAltitude == nil {
+			return "unknown"
+		}
+		low := int(*event.BaroAltitude) / altitudeBandMeters * altitudeBandMeters
+		if *event.BaroAltitude < 0 {
+			low = 0
+		}
+		return fmt.Sprintf("%d-%d", low, low+altitudeBandMeters)
+	},
+}
+
+// aggregateMetrics maps the allowed metric values to the numeric field they average.
+// A nil field function means the metric is a plain count.
+var aggregateMetrics = map[string]func(event *model.FlightEvent) *float64{
+	"count":        nil,
+	"avg_altitude": func(event *model.FlightEvent) *float64 { return event.BaroAltitude },
+	"avg_velocity": func(event *model.FlightEvent) *float64 { return event.Velocity },
+}
+
+// aggregateAccumulator tracks running totals for a single group
+type aggregateAccumulator struct {
+	count   int
+	sum     float64
+	samples int
+}
+
+// aggregateGroup is a single row of the aggregation result
+type aggregateGroup struct {
+	Key     string   `json:"key"`
+	Count   int      `json:"count"`
+	Value   *float64 `json:"value"`
+	Samples int      `json:"samples"`
+}
+
+// handleEventsAggregate returns grouped aggregates computed over the buffer in one pass
+func (s *Server) handleEventsAggregate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	groupBy := r.URL.Query().Get("group_by")
+	groupFn, ok := aggregateGroupers[groupBy]
+	if !ok {
+		http.Error(w, "Query parameter 'group_by' must be 'country', 'on_ground', or 'altitude_band'", http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	metric := r.URL.Query().Get("metric")
+	if metric == "" {
+		metric = "count"
+	}
+	valueFn, ok := aggregateMetrics[metric]
+	if !ok {
+		http.Error(w, "Query parameter 'metric' must be 'count', 'avg_altitude', or 'avg_velocity'", http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	accumulators := make(map[string]*aggregateAccumulator)
+	found := s.forEachEvent(func(event *model.FlightEvent) bool {
+		if event == nil {
+			return true
+		}
+
+		key := groupFn(event)
+		acc, exists := accumulators[key]
+		if !exists {
+			acc = &aggregateAccumulator{}
+			accumulators[key] = acc
+		}
+		acc.count++
+
+		if valueFn != nil {
+			if value := valueFn(event); value != nil {
+				acc.sum += *value
+				acc.samples++
+			}
+		}
+		return true
+	})
+	if !found {
+		s.logger.Error("No buffer configured")
+		http.Error(w, "Buffer not available", http.StatusInternalServerError)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	groups := make([]aggregateGroup, 0, len(accumulators))
+	for key, acc := range accumulators {
+		group := aggregateGroup{
+			Key:     key,
+			Count:   acc.count,
+			Samples: acc.count,
+		}
+
+		if valueFn == nil {
+			value := float64(acc.count)
+			group.Value = &value
+		} else {
+			// Groups without any non-nil samples have no average
+			group.Samples = acc.samples
+			if acc.samples > 0 {
+				value := acc.sum / float64(acc.samples)
+				group.Value = &value
+			}
+		}
+
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+
+	response := map[string]interface{}{
+		"group_by":  groupBy,
+		"metric":    metric,
+		"groups":    groups,
+		"timestamp": time.Now().Unix(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error("Failed to encode aggregate response: %v", err)
+		s.metrics.IncrementHTTPErrors()
+	}
+}
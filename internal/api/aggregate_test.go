@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+// getAggregate requests /events/aggregate and returns its groups keyed by group key
+func getAggregate(t *testing.T, s *Server, query string) map[string]aggregateGroup {
+	t.Helper()
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/events/aggregate?"+query, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /events/aggregate?%s status = %d: %s", query, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Groups []aggregateGroup `json:"groups"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	groups := make(map[string]aggregateGroup, len(response.Groups))
+	for _, group := range response.Groups {
+		groups[group.Key] = group
+	}
+	return groups
+}
+
+// formatGroup renders a group as "count/samples/value" for comparison
+func formatGroup(group aggregateGroup) string {
+	value := "nil"
+	if group.Value != nil {
+		value = fmt.Sprintf("%g", *group.Value)
+	}
+	return fmt.Sprintf("%d/%d/%s", group.Count, group.Samples, value)
+}
+
+func aggregateFixture(t *testing.T) *Server {
+	t.Helper()
+
+	return newTestServer(t,
+		&model.FlightEvent{ICAO24: "a1", OriginCountry: "Germany", BaroAltitude: floatPtr(1000), Velocity: floatPtr(200)},
+		&model.FlightEvent{ICAO24: "a2", OriginCountry: "Germany", BaroAltitude: floatPtr(5000), Velocity: floatPtr(240)},
+		&model.FlightEvent{ICAO24: "a3", OriginCountry: "Germany", OnGround: true},
+		&model.FlightEvent{ICAO24: "a4", OriginCountry: "France", BaroAltitude: floatPtr(-50)},
+		&model.FlightEvent{ICAO24: "a5", OriginCountry: "France", BaroAltitude: floatPtr(3000)},
+		&model.FlightEvent{ICAO24: "a6"},
+	)
+}
+
+func TestEventsAggregate(t *testing.T) {
+	s := aggregateFixture(t)
+
+	tests := []struct {
+		query string
+		want  map[string]string
+	}{
+		{"group_by=country", map[string]string{
+			"Germany": "3/3/3", "France": "2/2/2", "unknown": "1/1/1",
+		}},
+		{"group_by=on_ground&metric=count", map[string]string{
+			"false": "5/5/5", "true": "1/1/1",
+		}},
+		// Negative altitudes fall into the lowest band; bands are [low, low+3000)
+		{"group_by=altitude_band", map[string]string{
+			"0-3000": "2/2/2", "3000-6000": "2/2/2", "ground": "1/1/1", "unknown": "1/1/1",
+		}},
+		// Averages only use events carrying the field
+		{"group_by=country&metric=avg_altitude", map[string]string{
+			"Germany": "3/2/3000", "France": "2/2/1475", "unknown": "1/0/nil",
+		}},
+		{"group_by=country&metric=avg_velocity", map[string]string{
+			"Germany": "3/2/220", "France": "2/0/nil", "unknown": "1/0/nil",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			groups := getAggregate(t, s, tt.query)
+			if len(groups) != len(tt.want) {
+				t.Fatalf("got %d groups, want %d: %v", len(groups), len(tt.want), groups)
+			}
+			for key, want := range tt.want {
+				group, ok := groups[key]
+				if !ok {
+					t.Errorf("missing group %q", key)
+					continue
+				}
+				if got := formatGroup(group); got != want {
+					t.Errorf("group %q = %s, want %s (count/samples/value)", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEventsAggregateSortsGroups(t *testing.T) {
+	s := aggregateFixture(t)
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/events/aggregate?group_by=country", nil))
+	var response struct {
+		Groups []aggregateGroup `json:"groups"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	want := []string{"France", "Germany", "unknown"}
+	if len(response.Groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(response.Groups), len(want))
+	}
+	for i, group := range response.Groups {
+		if group.Key != want[i] {
+			t.Errorf("groups[%d] = %q, want %q", i, group.Key, want[i])
+		}
+	}
+}
+
+func TestEventsAggregateEmptyBuffer(t *testing.T) {
+	s := newTestServer(t)
+
+	if groups := getAggregate(t, s, "group_by=country"); len(groups) != 0 {
+		t.Errorf("groups = %v, want none", groups)
+	}
+}
+
+func TestEventsAggregateRejectsBadParameters(t *testing.T) {
+	s := aggregateFixture(t)
+
+	for _, query := range []string{"", "group_by=callsign", "group_by=country&metric=max_altitude"} {
+		w := serve(s, httptest.NewRequest(http.MethodGet, "/events/aggregate?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GET /events/aggregate?%s status = %d, want 400", query, w.Code)
+		}
+	}
+
+	w := serve(s, httptest.NewRequest(http.MethodPost, "/events/aggregate?group_by=country", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", w.Code)
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"flight-event-throttler/internal/model"
+)
+
+// handleEventsAircraft returns the most recent buffered event for the
+// aircraft in the path, e.g. /events/3c6444. ICAO24 matching ignores case.
+func (s *Server) handleEventsAircraft(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	units, ok := parseUnits(r)
+	if !ok {
+		http.Error(w, "Query parameter 'units' must be 'si' or 'imperial'", http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	icao24 := strings.ToLower(r.PathValue("icao24"))
+
+	// Buffers iterate oldest first, so the last match is the latest state
+	var latest *model.FlightEvent
+	if !s.forEachEvent(func(event *model.FlightEvent) bool {
+		if event != nil && strings.ToLower(event.ICAO24) == icao24 {
+			latest = event
+		}
+		return true
+	}) {
+		s.logger.Error("No buffer configured")
+		http.Error(w, "Buffer not available", http.StatusInternalServerError)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	if latest == nil {
+		http.Error(w, "Aircraft not found", http.StatusNotFound)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(s.projectEvents([]*model.FlightEvent{latest}, units)[0]); err != nil {
+		s.logger.Error("Failed to encode aircraft response: %v", err)
+		s.metrics.IncrementHTTPErrors()
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+func TestEventsAircraftLookup(t *testing.T) {
+	s := newTestServer(t,
+		&model.FlightEvent{ICAO24: "3c6444", Callsign: "OLD", BaroAltitude: floatPtr(1000)},
+		&model.FlightEvent{ICAO24: "abc123", Callsign: "OTHER"},
+		&model.FlightEvent{ICAO24: "3C6444", Callsign: "NEW", BaroAltitude: floatPtr(1000)},
+	)
+
+	tests := []struct {
+		name     string
+		path     string
+		status   int
+		callsign string
+	}{
+		{"found", "/events/abc123", http.StatusOK, "OTHER"},
+		{"latest state wins", "/events/3c6444", http.StatusOK, "NEW"},
+		{"case-insensitive", "/events/ABC123", http.StatusOK, "OTHER"},
+		{"not found", "/events/ffffff", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(s, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("GET %s status = %d, want %d", tt.path, w.Code, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var event model.FlightEvent
+			if err := json.Unmarshal(w.Body.Bytes(), &event); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if event.Callsign != tt.callsign {
+				t.Errorf("callsign = %q, want %q", event.Callsign, tt.callsign)
+			}
+		})
+	}
+}
+
+func TestEventsAircraftUnits(t *testing.T) {
+	s := newTestServer(t, &model.FlightEvent{ICAO24: "abc123", BaroAltitude: floatPtr(1000)})
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/events/abc123?units=imperial", nil))
+	var event model.FlightEvent
+	if err := json.Unmarshal(w.Body.Bytes(), &event); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !approxEqual(event.BaroAltitude, 3280.84) {
+		t.Errorf("baro_altitude = %v, want 3280.84 ft", event.BaroAltitude)
+	}
+}
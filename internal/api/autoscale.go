@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// AutoscaleWeights controls how pipeline pressure components are blended into
+// the autoscaling signal
+type AutoscaleWeights struct {
+	Buffer     float64
+	Throughput float64
+	Drops      float64
+}
+
+// SetAutoscale configures the weights for the /autoscale signal and a function
+// returning the current throughput capacity in events per second
+func (s *Server) SetAutoscale(weights AutoscaleWeights, capacity func() int) {
+	s.autoscaleWeights = weights
+	s.throughputCapacity = capacity
+}
+
+// handleAutoscale returns a single pressure signal for external autoscalers.
+// Each component is a fraction in [0, 1]; the signal is their weighted sum.
+func (s *Server) handleAutoscale(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	snapshot := s.metrics.GetSnapshot()
+
+	bufferUtil := clampFraction(snapshot.BufferUtilization / 100)
+
+	throughputUtil := 0.0
+	if s.throughputCapacity != nil {
+		if capacity := s.throughputCapacity(); capacity > 0 {
+			throughputUtil = clampFraction(float64(snapshot.EventsPerSecond) / float64(capacity))
+		}
+	}
+
+	dropRate := snapshot.DropRate
+
+	weights := s.autoscaleWeights
+	signal := weights.Buffer*bufferUtil + weights.Throughput*throughputUtil + weights.Drops*dropRate
+
+	// Plain format for scalers that expect a bare number
+	if r.URL.Query().Get("format") == "plain" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "%g\n", signal)
+		return
+	}
+
+	response := map[string]interface{}{
+		"signal": signal,
+		"components": map[string]float64{
+			"buffer_utilization":     bufferUtil,
+			"throughput_utilization": throughputUtil,
+			"drop_rate":              dropRate,
+		},
+		"weights": map[string]float64{
+			"buffer":     weights.Buffer,
+			"throughput": weights.Throughput,
+			"drops":      weights.Drops,
+		},
+		"timestamp": time.Now().Unix(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error("Failed to encode autoscale response: %v", err)
+		s.metrics.IncrementHTTPErrors()
+	}
+}
+
+// clampFraction limits a value to the range [0, 1]
+func clampFraction(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type autoscaleResponse struct {
+	Signal     float64            `json:"signal"`
+	Components map[string]float64 `json:"components"`
+}
+
+func getAutoscale(t *testing.T, s *Server) autoscaleResponse {
+	t.Helper()
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/autoscale", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /autoscale status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var response autoscaleResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	return response
+}
+
+// pressuredServer returns a server with a half full buffer and a 25% drop rate
+func pressuredServer(t *testing.T) *Server {
+	t.Helper()
+
+	s := newTestServer(t)
+	s.metrics.SetBufferCapacity(100)
+	s.metrics.SetBufferSize(50)
+	for i := 0; i < 3; i++ {
+		s.metrics.IncrementEventsProcessed()
+	}
+	s.metrics.IncrementEventsDropped()
+	return s
+}
+
+func TestAutoscaleWeightsComponents(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights AutoscaleWeights
+		want    float64
+	}{
+		{"buffer only", AutoscaleWeights{Buffer: 1}, 0.5},
+		{"drops only", AutoscaleWeights{Drops: 1}, 0.25},
+		{"blended", AutoscaleWeights{Buffer: 0.5, Throughput: 0.3, Drops: 0.2}, 0.3},
+		{"zero weights", AutoscaleWeights{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := pressuredServer(t)
+			s.SetAutoscale(tt.weights, nil)
+
+			response := getAutoscale(t, s)
+			if math.Abs(response.Signal-tt.want) > 1e-9 {
+				t.Errorf("signal = %g, want %g", response.Signal, tt.want)
+			}
+			if response.Components["buffer_utilization"] != 0.5 {
+				t.Errorf("buffer_utilization = %g, want 0.5", response.Components["buffer_utilization"])
+			}
+			if response.Components["drop_rate"] != 0.25 {
+				t.Errorf("drop_rate = %g, want 0.25", response.Components["drop_rate"])
+			}
+			if response.Components["throughput_utilization"] != 0 {
+				t.Errorf("throughput_utilization = %g, want 0 without a capacity", response.Components["throughput_utilization"])
+			}
+		})
+	}
+}
+
+func TestAutoscaleClampsBufferUtilization(t *testing.T) {
+	s := newTestServer(t)
+	s.metrics.SetBufferCapacity(10)
+	s.metrics.SetBufferSize(25)
+	s.SetAutoscale(AutoscaleWeights{Buffer: 1}, nil)
+
+	if response := getAutoscale(t, s); response.Signal != 1 {
+		t.Errorf("signal = %g, want 1 with an overfull buffer", response.Signal)
+	}
+}
+
+func TestAutoscaleThroughputUtilization(t *testing.T) {
+	s := newTestServer(t)
+
+	// Keep events flowing so the per-second rate stays above zero
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Millisecond):
+				s.metrics.IncrementEventsProcessed()
+			}
+		}
+	}()
+
+	capacity := 0
+	s.SetAutoscale(AutoscaleWeights{Throughput: 1}, func() int { return capacity })
+
+	// The rate is sampled once a second
+	deadline := time.Now().Add(3 * time.Second)
+	for s.metrics.GetEventsPerSecond() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("events_per_second never rose above zero")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if response := getAutoscale(t, s); response.Components["throughput_utilization"] != 0 {
+		t.Errorf("throughput_utilization = %g, want 0 with a zero capacity", response.Components["throughput_utilization"])
+	}
+
+	// A capacity below the observed rate saturates the component
+	capacity = 1
+	if response := getAutoscale(t, s); response.Signal != 1 {
+		t.Errorf("signal = %g, want 1 above capacity", response.Signal)
+	}
+}
+
+func TestAutoscalePlainFormat(t *testing.T) {
+	s := pressuredServer(t)
+	s.SetAutoscale(AutoscaleWeights{Buffer: 1}, nil)
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/autoscale?format=plain", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+
+	signal, err := strconv.ParseFloat(strings.TrimSpace(w.Body.String()), 64)
+	if err != nil {
+		t.Fatalf("body %q is not a bare number: %v", w.Body.String(), err)
+	}
+	if signal != 0.5 {
+		t.Errorf("signal = %g, want 0.5", signal)
+	}
+}
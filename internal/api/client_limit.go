@@ -0,0 +1,71 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"flight-event-throttler/internal/processor"
+)
+
+// unlimitedRoutes are never rate limited so orchestrator probes keep working
+// while a client is being throttled
+var unlimitedRoutes = map[string]bool{
+	"/health": true,
+	"/readyz": true,
+}
+
+// SetClientRateLimit limits each client IP to requestsPerSec requests per
+// second with bursts of up to burst requests. Zero disables the limit.
+// Clients are identified by the connection's address unless trustForwarded
+// is set, which uses the first X-Forwarded-For hop instead; enable it only
+// behind a reverse proxy that sets the header, since clients can forge it.
+func (s *Server) SetClientRateLimit(requestsPerSec, burst int, trustForwarded bool) {
+	s.trustForwarded = trustForwarded
+	if requestsPerSec <= 0 {
+		s.clientLimiter = nil
+		return
+	}
+	s.clientLimiter = processor.NewKeyedRateLimiter(requestsPerSec, burst)
+	s.clientRetryAfter = strconv.Itoa(int(math.Ceil(1 / float64(requestsPerSec))))
+}
+
+// clientLimitHandler rejects requests over the per-client limit with 429
+func (s *Server) clientLimitHandler(route string, handler http.HandlerFunc) http.HandlerFunc {
+	if unlimitedRoutes[route] {
+		return handler
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.clientLimiter != nil && !s.clientLimiter.AllowKey(s.clientIP(r)) {
+			s.metrics.IncrementHTTPRequests()
+			s.metrics.IncrementHTTPErrors()
+			w.Header().Set("Retry-After", s.clientRetryAfter)
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// clientIP identifies the client by the remote address of the connection or,
+// when forwarded headers are trusted, by the first X-Forwarded-For hop, which
+// a reverse proxy sets to the original client
+func (s *Server) clientIP(r *http.Request) string {
+	if s.trustForwarded {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			if first = strings.TrimSpace(first); first != "" {
+				return first
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fromClient builds a request to path from remoteAddr with an optional
+// X-Forwarded-For header
+func fromClient(path, remoteAddr, forwardedFor string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	r.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		r.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	return r
+}
+
+func TestClientRateLimitReturns429(t *testing.T) {
+	s := newTestServer(t)
+	s.SetClientRateLimit(1, 3, false)
+
+	for i := 0; i < 3; i++ {
+		if w := serve(s, fromClient("/events", "192.0.2.1:5000", "")); w.Code != http.StatusOK {
+			t.Fatalf("request %d within the burst: status = %d, want 200", i+1, w.Code)
+		}
+	}
+
+	w := serve(s, fromClient("/events", "192.0.2.1:5001", ""))
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over the burst: status = %d, want 429", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want 1", got)
+	}
+
+	// Probes stay reachable while the client is throttled
+	if w := serve(s, fromClient("/health", "192.0.2.1:5002", "")); w.Code == http.StatusTooManyRequests {
+		t.Error("/health was rate limited")
+	}
+}
+
+func TestClientRateLimitPerIP(t *testing.T) {
+	s := newTestServer(t)
+	s.SetClientRateLimit(1, 2, false)
+
+	for i := 0; i < 2; i++ {
+		serve(s, fromClient("/events", "192.0.2.1:5000", ""))
+	}
+	if w := serve(s, fromClient("/events", "192.0.2.1:5000", "")); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("noisy client status = %d, want 429", w.Code)
+	}
+
+	for _, addr := range []string{"192.0.2.2:5000", "198.51.100.7:6000", "[2001:db8::1]:7000"} {
+		if w := serve(s, fromClient("/events", addr, "")); w.Code != http.StatusOK {
+			t.Errorf("client %s status = %d, want 200 while another client is throttled", addr, w.Code)
+		}
+	}
+}
+
+func TestClientRateLimitIgnoresForwardedByDefault(t *testing.T) {
+	s := newTestServer(t)
+	s.SetClientRateLimit(1, 1, false)
+
+	serve(s, fromClient("/events", "192.0.2.1:5000", "203.0.113.1"))
+
+	// A forged header doesn't get a client a fresh bucket
+	if w := serve(s, fromClient("/events", "192.0.2.1:5000", "203.0.113.2")); w.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d with a different X-Forwarded-For, want 429", w.Code)
+	}
+}
+
+func TestClientRateLimitTrustsForwardedWhenEnabled(t *testing.T) {
+	s := newTestServer(t)
+	s.SetClientRateLimit(1, 1, true)
+
+	// Every request arrives from the proxy; clients are told apart by the header
+	proxy := "10.0.0.1:443"
+	if w := serve(s, fromClient("/events", proxy, "203.0.113.1, 10.0.0.1")); w.Code != http.StatusOK {
+		t.Fatalf("first client status = %d, want 200", w.Code)
+	}
+	if w := serve(s, fromClient("/events", proxy, "203.0.113.2")); w.Code != http.StatusOK {
+		t.Errorf("second client status = %d, want 200", w.Code)
+	}
+	if w := serve(s, fromClient("/events", proxy, "203.0.113.1")); w.Code != http.StatusTooManyRequests {
+		t.Errorf("repeat client status = %d, want 429", w.Code)
+	}
+}
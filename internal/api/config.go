@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"flight-event-throttler/internal/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetConfigSource sets the function returning the config in effect, served
+// by /config. It is a function so settings reloaded at runtime show up.
+func (s *Server) SetConfigSource(current func() *config.Config) {
+	s.configSource = current
+}
+
+// handleConfig returns the effective config, after merging files, environment
+// variables and defaults, with secrets redacted. Keys and durations are
+// written as in the config file.
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	if !s.validAdminToken(r) {
+		http.Error(w, "Invalid or missing admin token", http.StatusUnauthorized)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	if s.configSource == nil {
+		http.Error(w, "Config not available", http.StatusServiceUnavailable)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	document, err := configDocument(s.configSource().Redacted())
+	if err != nil {
+		s.logger.Error("Failed to encode config: %v", err)
+		http.Error(w, "Failed to encode config", http.StatusInternalServerError)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(document); err != nil {
+		s.logger.Error("Failed to encode config response: %v", err)
+		s.metrics.IncrementHTTPErrors()
+	}
+}
+
+// configDocument converts the config to a generic document keyed by its YAML
+// names, so the JSON matches the config file rather than the Go field names
+func configDocument(cfg *config.Config) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// corsAllowedMethods are the methods the API serves cross-origin
+const corsAllowedMethods = "GET, POST, OPTIONS"
+
+// CORSPolicy controls which browser origins may call the API
+type CORSPolicy struct {
+	AllowedOrigins   []string // Exact origins, or "*" for any
+	AllowCredentials bool     // Must not be combined with "*"
+	MaxAge           time.Duration
+}
+
+// SetCORS enables CORS with the given policy. An empty origin list disables
+// it. Browsers refuse credentials with a wildcard origin, so combining "*"
+// with AllowCredentials is an error.
+func (s *Server) SetCORS(policy CORSPolicy) error {
+	corsAny := false
+	origins := make(map[string]struct{}, len(policy.AllowedOrigins))
+	for _, origin := range policy.AllowedOrigins {
+		if origin == "*" {
+			corsAny = true
+			continue
+		}
+		origins[origin] = struct{}{}
+	}
+	if corsAny && policy.AllowCredentials {
+		return fmt.Errorf("CORS origin '*' cannot be combined with credentials")
+	}
+
+	s.cors, s.corsOrigins, s.corsAny = policy, origins, corsAny
+	return nil
+}
+
+// corsEnabled reports whether a CORS policy is set
+func (s *Server) corsEnabled() bool {
+	return len(s.corsOrigins) > 0 || s.corsAny
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for origin, or
+// "" if it is not allowed. Matched origins are echoed; a wildcard policy
+// without credentials answers "*".
+func (s *Server) allowOrigin(origin string) string {
+	if origin == "" {
+		return ""
+	}
+	if _, ok := s.corsOrigins[origin]; ok {
+		return origin
+	}
+	if s.corsAny {
+		return "*"
+	}
+	return ""
+}
+
+// corsHandler adds CORS headers for allowed origins and answers preflight
+// requests itself. Requests from other origins are served without CORS
+// headers, so the browser blocks them.
+func (s *Server) corsHandler(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.corsEnabled() {
+			handler(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		origin := r.Header.Get("Origin")
+		allowed := s.allowOrigin(origin)
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		if allowed == "" {
+			if preflight {
+				http.Error(w, "Origin not allowed", http.StatusForbidden)
+				return
+			}
+			handler(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", allowed)
+		if s.cors.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			handler(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+		w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+		if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+		}
+		if s.cors.MaxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(s.cors.MaxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func corsServer(t *testing.T, policy CORSPolicy) *Server {
+	t.Helper()
+
+	s := newTestServer(t)
+	if err := s.SetCORS(policy); err != nil {
+		t.Fatalf("SetCORS() error = %v", err)
+	}
+	return s
+}
+
+func corsRequest(method, origin string) *http.Request {
+	r := httptest.NewRequest(method, "/events", nil)
+	if origin != "" {
+		r.Header.Set("Origin", origin)
+	}
+	return r
+}
+
+func TestCORSAllowedOrigin(t *testing.T) {
+	s := corsServer(t, CORSPolicy{AllowedOrigins: []string{"https://dashboard.example.com"}, AllowCredentials: true})
+
+	w := serve(s, corsRequest(http.MethodGet, "https://dashboard.example.com"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://dashboard.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want the origin echoed", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Access-Control-Allow-Credentials = %q, want true", got)
+	}
+	if got := w.Header().Values("Vary"); !containsValue(got, "Origin") {
+		t.Errorf("Vary = %v, want Origin", got)
+	}
+}
+
+func TestCORSDisallowedOrigin(t *testing.T) {
+	s := corsServer(t, CORSPolicy{AllowedOrigins: []string{"https://dashboard.example.com"}})
+
+	// Served without CORS headers, so the browser blocks the response
+	w := serve(s, corsRequest(http.MethodGet, "https://evil.example.com"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	for _, header := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Credentials"} {
+		if got := w.Header().Get(header); got != "" {
+			t.Errorf("%s = %q for a disallowed origin, want none", header, got)
+		}
+	}
+
+	preflight := corsRequest(http.MethodOptions, "https://evil.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", "GET")
+	if w := serve(s, preflight); w.Code != http.StatusForbidden {
+		t.Errorf("preflight status = %d for a disallowed origin, want 403", w.Code)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	s := corsServer(t, CORSPolicy{AllowedOrigins: []string{"https://dashboard.example.com"}, MaxAge: 10 * time.Minute})
+
+	r := corsRequest(http.MethodOptions, "https://dashboard.example.com")
+	r.Header.Set("Access-Control-Request-Method", "POST")
+	r.Header.Set("Access-Control-Request-Headers", "Content-Type, X-Admin-Token")
+	w := serve(s, r)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":  "https://dashboard.example.com",
+		"Access-Control-Allow-Methods": corsAllowedMethods,
+		"Access-Control-Allow-Headers": "Content-Type, X-Admin-Token",
+		"Access-Control-Max-Age":       "600",
+	}
+	for header, value := range want {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("preflight body = %q, want empty", w.Body.String())
+	}
+}
+
+func TestCORSWildcard(t *testing.T) {
+	s := corsServer(t, CORSPolicy{AllowedOrigins: []string{"*"}})
+
+	w := serve(s, corsRequest(http.MethodGet, "https://anywhere.example.com"))
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+
+	err := newTestServer(t).SetCORS(CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+	if err == nil || !strings.Contains(err.Error(), "credentials") {
+		t.Errorf("SetCORS(* with credentials) error = %v, want a credentials error", err)
+	}
+}
+
+func TestCORSDisabledByDefault(t *testing.T) {
+	w := serve(newTestServer(t), corsRequest(http.MethodGet, "https://dashboard.example.com"))
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q without a policy, want none", got)
+	}
+}
+
+func containsValue(values []string, want string) bool {
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if strings.TrimSpace(part) == want {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// coverageCacheTTL is how long a computed coverage report is reused, so
+// frequent polling under load doesn't rescan the buffer on every request
+const coverageCacheTTL = 2 * time.Second
+
+// coverageCache holds the most recently encoded coverage report
+type coverageCache struct {
+	mu         sync.Mutex
+	body       []byte
+	computedAt time.Time
+}
+
+// sensorCoverage is the number of distinct aircraft a sensor currently contributes to
+type sensorCoverage struct {
+	Sensor   int `json:"sensor"`
+	Aircraft int `json:"aircraft"`
+}
+
+// handleBufferCoverage reports how many buffered aircraft each receiver
+// (sensor ID) contributes to, revealing coverage and gaps in the network
+func (s *Server) handleBufferCoverage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	s.coverage.mu.Lock()
+	defer s.coverage.mu.Unlock()
+
+	if s.coverage.body == nil || time.Since(s.coverage.computedAt) >= coverageCacheTTL {
+		body, ok := s.computeCoverage()
+		if !ok {
+			s.logger.Error("No buffer configured")
+			http.Error(w, "Buffer not available", http.StatusInternalServerError)
+			s.metrics.IncrementHTTPErrors()
+			return
+		}
+		s.coverage.body = body
+		s.coverage.computedAt = time.Now()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(s.coverage.body); err != nil {
+		s.logger.Error("Failed to write coverage response: %v", err)
+		s.metrics.IncrementHTTPErrors()
+	}
+}
+
+// computeCoverage tallies distinct aircraft per sensor in a single pass over
+// the buffer and returns the encoded report
+func (s *Server) computeCoverage() ([]byte, bool) {
+	aircraftBySensor := make(map[int]map[string]bool)
+	withSensors := make(map[string]bool)
+	withoutSensors := make(map[string]bool)
+
+	found := s.forEachEvent(func(event *model.FlightEvent) bool {
+		if event == nil {
+			return true
+		}
+		if len(event.Sensors) == 0 {
+			withoutSensors[event.ICAO24] = true
+			return true
+		}
+
+		withSensors[event.ICAO24] = true
+		for _, sensor := range event.Sensors {
+			aircraft, ok := aircraftBySensor[sensor]
+			if !ok {
+				aircraft = make(map[string]bool)
+				aircraftBySensor[sensor] = aircraft
+			}
+			aircraft[event.ICAO24] = true
+		}
+		return true
+	})
+	if !found {
+		return nil, false
+	}
+
+	// An aircraft seen both with and without sensor data counts as covered
+	for icao24 := range withSensors {
+		delete(withoutSensors, icao24)
+	}
+
+	sensors := make([]sensorCoverage, 0, len(aircraftBySensor))
+	for sensor, aircraft := range aircraftBySensor {
+		sensors = append(sensors, sensorCoverage{Sensor: sensor, Aircraft: len(aircraft)})
+	}
+	sort.Slice(sensors, func(i, j int) bool {
+		if sensors[i].Aircraft != sensors[j].Aircraft {
+			return sensors[i].Aircraft > sensors[j].Aircraft
+		}
+		return sensors[i].Sensor < sensors[j].Sensor
+	})
+
+	response := map[string]interface{}{
+		"sensors":                  sensors,
+		"sensor_count":             len(sensors),
+		"aircraft_with_sensors":    len(withSensors),
+		"aircraft_without_sensors": len(withoutSensors),
+		"timestamp":                time.Now().Unix(),
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		s.logger.Error("Failed to encode coverage response: %v", err)
+		return nil, false
+	}
+	return append(body, '\n'), true
+}
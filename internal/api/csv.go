@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// csvHeader lists the CSV columns, matching the JSON field names
+var csvHeader = []string{
+	"icao24", "callsign", "origin_country", "time_position", "last_contact",
+	"longitude", "latitude", "baro_altitude", "on_ground", "velocity",
+	"true_track", "vertical_rate", "sensors", "geo_altitude", "squawk", "spi",
+	"position_source", "registration", "aircraft_type", "timestamp",
+}
+
+// writeEventsCSV streams events as CSV with a header row. Missing values are
+// written as empty cells and sensors are separated by semicolons.
+func writeEventsCSV(w http.ResponseWriter, events []*model.FlightEvent) error {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	record := make([]string, len(csvHeader))
+	for _, event := range events {
+		if event == nil {
+			continue
+		}
+		csvRecord(record, event)
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvRecord fills record with the event's values in csvHeader order
+func csvRecord(record []string, event *model.FlightEvent) {
+	sensors := make([]string, len(event.Sensors))
+	for i, sensor := range event.Sensors {
+		sensors[i] = strconv.Itoa(sensor)
+	}
+
+	squawk := ""
+	if event.Squawk != nil {
+		squawk = *event.Squawk
+	}
+
+	timestamp := ""
+	if !event.Timestamp.IsZero() {
+		timestamp = event.Timestamp.UTC().Format(time.RFC3339)
+	}
+
+	record[0] = event.ICAO24
+	record[1] = event.Callsign
+	record[2] = event.OriginCountry
+	record[3] = strconv.FormatInt(event.TimePosition, 10)
+	record[4] = strconv.FormatInt(event.LastContact, 10)
+	record[5] = csvFloat(event.Longitude)
+	record[6] = csvFloat(event.Latitude)
+	record[7] = csvFloat(event.BaroAltitude)
+	record[8] = strconv.FormatBool(event.OnGround)
+	record[9] = csvFloat(event.Velocity)
+	record[10] = csvFloat(event.TrueTrack)
+	record[11] = csvFloat(event.VerticalRate)
+	record[12] = strings.Join(sensors, ";")
+	record[13] = csvFloat(event.GeoAltitude)
+	record[14] = squawk
+	record[15] = strconv.FormatBool(event.Spi)
+	record[16] = strconv.Itoa(event.PositionSource)
+	record[17] = event.Registration
+	record[18] = event.AircraftType
+	record[19] = timestamp
+}
+
+// csvFloat formats a value with no trailing zeros, or an empty cell for nil
+func csvFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// getCSV requests path with the given Accept header and parses the CSV body
+func getCSV(t *testing.T, s *Server, path, accept string) [][]string {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := serve(s, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s status = %d: %s", path, w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Fatalf("Content-Type = %q, want text/csv", ct)
+	}
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("GET %s returned invalid CSV: %v", path, err)
+	}
+	return records
+}
+
+func TestEventsCSV(t *testing.T) {
+	squawk := "7700"
+	s := newTestServer(t,
+		&model.FlightEvent{
+			ICAO24: "abc123", Callsign: "SWR100", OriginCountry: "Switzerland",
+			Longitude: floatPtr(8.55), Latitude: floatPtr(47.46), BaroAltitude: floatPtr(10000),
+			Velocity: floatPtr(230.5), Sensors: []int{7, 42}, Squawk: &squawk, OnGround: false,
+			Timestamp: time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+		},
+		&model.FlightEvent{ICAO24: "def456", Callsign: `A "quoted", name`, OriginCountry: "France", OnGround: true},
+	)
+
+	for _, tt := range []struct{ name, path, accept string }{
+		{"Accept header", "/events", "text/csv"},
+		{"format override", "/events?format=csv", "application/json"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			records := getCSV(t, s, tt.path, tt.accept)
+			if len(records) != 3 {
+				t.Fatalf("got %d records, want a header and 2 rows", len(records))
+			}
+			if got := strings.Join(records[0], ","); got != strings.Join(csvHeader, ",") {
+				t.Errorf("header = %s", got)
+			}
+
+			column := func(row []string, name string) string {
+				for i, header := range records[0] {
+					if header == name {
+						return row[i]
+					}
+				}
+				t.Fatalf("no %s column", name)
+				return ""
+			}
+
+			full := records[1]
+			for name, want := range map[string]string{
+				"icao24": "abc123", "longitude": "8.55", "baro_altitude": "10000", "velocity": "230.5",
+				"sensors": "7;42", "squawk": "7700", "on_ground": "false", "timestamp": "2026-03-01T12:30:00Z",
+			} {
+				if got := column(full, name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+
+			sparse := records[2]
+			if got := column(sparse, "callsign"); got != `A "quoted", name` {
+				t.Errorf("callsign = %q, want the quoted value round-tripped", got)
+			}
+			for _, name := range []string{"longitude", "latitude", "baro_altitude", "velocity", "geo_altitude", "sensors", "squawk", "timestamp"} {
+				if got := column(sparse, name); got != "" {
+					t.Errorf("%s = %q for a missing value, want an empty cell", name, got)
+				}
+			}
+		})
+	}
+}
+
+func TestEventsCSVAppliesFiltersAndPaging(t *testing.T) {
+	s := newTestServer(t,
+		&model.FlightEvent{ICAO24: "aaa111", OriginCountry: "Germany"},
+		&model.FlightEvent{ICAO24: "bbb222", OriginCountry: "France"},
+		&model.FlightEvent{ICAO24: "ccc333", OriginCountry: "Germany"},
+		&model.FlightEvent{ICAO24: "ddd444", OriginCountry: "Germany"},
+	)
+
+	records := getCSV(t, s, "/events?format=csv&origin_country=Germany&limit=2&offset=1", "")
+	if len(records) != 3 || records[1][0] != "ccc333" || records[2][0] != "ddd444" {
+		t.Errorf("records = %v, want the header then ccc333 and ddd444", records)
+	}
+
+	records = getCSV(t, s, "/events?format=csv&origin_country=Nowhere", "")
+	if len(records) != 1 {
+		t.Errorf("got %d records for no matches, want just the header", len(records))
+	}
+}
+
+func TestEventsRejectsUnknownFormat(t *testing.T) {
+	w := serve(newTestServer(t), httptest.NewRequest(http.MethodGet, "/events?format=xml", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
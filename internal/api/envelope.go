@@ -0,0 +1,29 @@
+package api
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// wantsEnvelope reports whether event listings should be wrapped in the
+// {events, timestamp} envelope. Clients opt out with ?envelope=false or an
+// Accept header carrying the envelope=false media type parameter, e.g.
+// "Accept: application/json; envelope=false".
+func wantsEnvelope(r *http.Request) bool {
+	if value := r.URL.Query().Get("envelope"); value != "" {
+		return !strings.EqualFold(value, "false")
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil || mediaType != "application/json" {
+			continue
+		}
+		if strings.EqualFold(params["envelope"], "false") {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"flight-event-throttler/internal/model"
+)
+
+// Page sizes for /events
+const (
+	defaultEventsLimit    = 500
+	defaultMaxEventsLimit = 5000
+)
+
+// eventQuery holds the /events filters. Unset filters match every event.
+type eventQuery struct {
+	icao24   map[string]struct{} // Any of these addresses; nil matches all
+	country  string
+	onGround *bool
+	box      *filterBox
+}
+
+// filterBox is a geographic bounding box in degrees
+type filterBox struct {
+	LaMin float64 `json:"lamin"`
+	LoMin float64 `json:"lomin"`
+	LaMax float64 `json:"lamax"`
+	LoMax float64 `json:"lomax"`
+}
+
+// validate checks that the box is within range and not inverted
+func (b *filterBox) validate() error {
+	if b.LaMin < -90 || b.LaMax > 90 || b.LoMin < -180 || b.LoMax > 180 {
+		return fmt.Errorf("is out of range")
+	}
+	if b.LaMin > b.LaMax || b.LoMin > b.LoMax {
+		return fmt.Errorf("minimums must not exceed maximums")
+	}
+	return nil
+}
+
+// contains reports whether the event's position is inside the box, edges
+// included. Events without a position are never inside.
+func (b *filterBox) contains(event *model.FlightEvent) bool {
+	if event.Latitude == nil || event.Longitude == nil {
+		return false
+	}
+	lat, lon := *event.Latitude, *event.Longitude
+	return lat >= b.LaMin && lat <= b.LaMax && lon >= b.LoMin && lon <= b.LoMax
+}
+
+// parseEventQuery reads the icao24, origin_country and on_ground filters and
+// the lamin/lomin/lamax/lomax bounding box. icao24 may be repeated or
+// comma-separated; the values are OR-combined.
+func parseEventQuery(r *http.Request) (*eventQuery, error) {
+	params := r.URL.Query()
+	query := &eventQuery{country: params.Get("origin_country")}
+
+	for _, value := range params["icao24"] {
+		for _, icao24 := range strings.Split(value, ",") {
+			if icao24 = strings.TrimSpace(icao24); icao24 == "" {
+				continue
+			}
+			if query.icao24 == nil {
+				query.icao24 = make(map[string]struct{})
+			}
+			query.icao24[icao24] = struct{}{}
+		}
+	}
+
+	if value := params.Get("on_ground"); value != "" {
+		onGround, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("on_ground must be 'true' or 'false'")
+		}
+		query.onGround = &onGround
+	}
+
+	box, err := parseBoundingBox(params)
+	if err != nil {
+		return nil, err
+	}
+	query.box = box
+
+	return query, nil
+}
+
+// parsePage reads the limit and offset query parameters. limit defaults to
+// 500 and is clamped to maxLimit; offset defaults to 0.
+func parsePage(r *http.Request, maxLimit int) (limit, offset int, err error) {
+	limit = min(defaultEventsLimit, maxLimit)
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return 0, 0, fmt.Errorf("limit must be a positive integer")
+		}
+		limit = min(parsed, maxLimit)
+	}
+
+	if value := r.URL.Query().Get("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+		offset = parsed
+	}
+
+	return limit, offset, nil
+}
+
+// paginate returns up to limit events starting at offset. An offset past the
+// end yields an empty page.
+func paginate(events []*model.FlightEvent, offset, limit int) []*model.FlightEvent {
+	if offset >= len(events) {
+		return []*model.FlightEvent{}
+	}
+	return events[offset:min(offset+limit, len(events))]
+}
+
+// parseBoundingBox reads lamin, lomin, lamax and lomax. It returns nil when
+// none are set; setting only some of them is an error.
+func parseBoundingBox(params url.Values) (*filterBox, error) {
+	names := [4]string{"lamin", "lomin", "lamax", "lomax"}
+	var values [4]float64
+	set := 0
+	for i, name := range names {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(parsed) {
+			return nil, fmt.Errorf("%s must be a number", name)
+		}
+		values[i] = parsed
+		set++
+	}
+
+	switch set {
+	case 0:
+		return nil, nil
+	case len(names):
+	default:
+		return nil, fmt.Errorf("lamin, lomin, lamax and lomax must be given together")
+	}
+
+	box := &filterBox{LaMin: values[0], LoMin: values[1], LaMax: values[2], LoMax: values[3]}
+	if err := box.validate(); err != nil {
+		return nil, fmt.Errorf("bounding box %w", err)
+	}
+	return box, nil
+}
+
+// match reports whether the event passes every filter
+func (q *eventQuery) match(event *model.FlightEvent) bool {
+	if event == nil {
+		return false
+	}
+	if q.icao24 != nil {
+		if _, ok := q.icao24[event.ICAO24]; !ok {
+			return false
+		}
+	}
+	if q.country != "" && event.OriginCountry != q.country {
+		return false
+	}
+	if q.onGround != nil && event.OnGround != *q.onGround {
+		return false
+	}
+	if q.box != nil && !q.box.contains(event) {
+		return false
+	}
+	return true
+}
+
+// filter returns the matching events, keeping their order
+func (q *eventQuery) filter(events []*model.FlightEvent) []*model.FlightEvent {
+	if q.icao24 == nil && q.country == "" && q.onGround == nil && q.box == nil {
+		return events
+	}
+
+	matched := make([]*model.FlightEvent, 0, len(events))
+	for _, event := range events {
+		if q.match(event) {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
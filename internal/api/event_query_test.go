@@ -0,0 +1,119 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+// icao24s lists the events' addresses in order
+func icao24s(events []*model.FlightEvent) string {
+	list := make([]string, len(events))
+	for i, event := range events {
+		list[i] = event.ICAO24
+	}
+	return strings.Join(list, ",")
+}
+
+// filterFixture is a buffer covering each filter dimension
+func filterFixture(t *testing.T) *Server {
+	t.Helper()
+
+	return newTestServer(t,
+		&model.FlightEvent{ICAO24: "aaa111", OriginCountry: "Germany", OnGround: false},
+		&model.FlightEvent{ICAO24: "bbb222", OriginCountry: "Germany", OnGround: true},
+		&model.FlightEvent{ICAO24: "ccc333", OriginCountry: "France", OnGround: false},
+		&model.FlightEvent{ICAO24: "ddd444", OriginCountry: "United States", OnGround: true},
+	)
+}
+
+func TestEventsFilters(t *testing.T) {
+	s := filterFixture(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"no filter", "", "aaa111,bbb222,ccc333,ddd444"},
+		{"icao24", "icao24=ccc333", "ccc333"},
+		{"repeated icao24", "icao24=aaa111&icao24=ddd444", "aaa111,ddd444"},
+		{"comma-separated icao24", "icao24=ddd444,%20bbb222,", "bbb222,ddd444"},
+		{"unknown icao24", "icao24=zzz999", ""},
+		{"country", "origin_country=Germany", "aaa111,bbb222"},
+		{"country with spaces", "origin_country=United+States", "ddd444"},
+		{"country is exact", "origin_country=germany", ""},
+		{"on ground", "on_ground=true", "bbb222,ddd444"},
+		{"airborne", "on_ground=false", "aaa111,ccc333"},
+		{"country and on ground", "origin_country=Germany&on_ground=false", "aaa111"},
+		{"icao24 and country", "icao24=aaa111,ccc333&origin_country=France", "ccc333"},
+		{"all three", "icao24=bbb222,ddd444&origin_country=Germany&on_ground=true", "bbb222"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := getEvents(t, s, "/events?envelope=false&"+tt.query)
+			if got := icao24s(events); got != tt.want {
+				t.Errorf("GET /events?%s = [%s], want [%s]", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventsRejectsInvalidOnGround(t *testing.T) {
+	w := serve(filterFixture(t), httptest.NewRequest(http.MethodGet, "/events?on_ground=maybe", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestEventsBoundingBox(t *testing.T) {
+	s := newTestServer(t,
+		&model.FlightEvent{ICAO24: "zurich", Latitude: floatPtr(47.46), Longitude: floatPtr(8.55)},
+		&model.FlightEvent{ICAO24: "geneva", Latitude: floatPtr(46.24), Longitude: floatPtr(6.11)},
+		&model.FlightEvent{ICAO24: "london", Latitude: floatPtr(51.47), Longitude: floatPtr(-0.45)},
+		&model.FlightEvent{ICAO24: "edge01", Latitude: floatPtr(45.8), Longitude: floatPtr(10.5)},
+		&model.FlightEvent{ICAO24: "nolat1", Longitude: floatPtr(8.55)},
+		&model.FlightEvent{ICAO24: "nopos1"},
+	)
+
+	events := getEvents(t, s, "/events?envelope=false&lamin=45.8&lomin=5.9&lamax=47.8&lomax=10.5")
+	if got, want := icao24s(events), "zurich,geneva,edge01"; got != want {
+		t.Errorf("events in box = [%s], want [%s]; edges inclusive, no nil coordinates", got, want)
+	}
+
+	events = getEvents(t, s, "/events?envelope=false&lamin=45.8&lomin=5.9&lamax=47.8&lomax=10.5&icao24=geneva,london")
+	if got := icao24s(events); got != "geneva" {
+		t.Errorf("box with icao24 filter = [%s], want [geneva]", got)
+	}
+}
+
+func TestEventsBoundingBoxErrors(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"inverted latitude", "lamin=47.8&lomin=5.9&lamax=45.8&lomax=10.5", "minimums must not exceed maximums"},
+		{"inverted longitude", "lamin=45.8&lomin=10.5&lamax=47.8&lomax=5.9", "minimums must not exceed maximums"},
+		{"out of range", "lamin=-91&lomin=5.9&lamax=47.8&lomax=10.5", "out of range"},
+		{"malformed", "lamin=north&lomin=5.9&lamax=47.8&lomax=10.5", "lamin must be a number"},
+		{"NaN", "lamin=NaN&lomin=5.9&lamax=47.8&lomax=10.5", "lamin must be a number"},
+		{"partial", "lamin=45.8&lamax=47.8", "must be given together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(s, httptest.NewRequest(http.MethodGet, "/events?"+tt.query, nil))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("body = %q, want it to mention %q", w.Body.String(), tt.want)
+			}
+		})
+	}
+}
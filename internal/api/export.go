@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// handleBufferExport streams the entire buffer as NDJSON, one event per line
+// with its timestamp, so a live snapshot can be captured for offline replay.
+// The buffer is copied before streaming rather than iterated in place.
+func (s *Server) handleBufferExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	if !s.adminEnabled {
+		http.Error(w, "Admin endpoints are disabled", http.StatusForbidden)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	// Copy the events out first so a slow client doesn't hold the buffer
+	// lock, and with it ingestion, for the whole transfer
+	events, ok := s.getAllEvents()
+	if !ok {
+		s.logger.Error("No buffer configured")
+		http.Error(w, "Buffer not available", http.StatusInternalServerError)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	filename := fmt.Sprintf("buffer-%d.ndjson", time.Now().Unix())
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	// Encoder writes a trailing newline after each event, producing NDJSON
+	encoder := json.NewEncoder(w)
+	exported := 0
+	var encodeErr error
+	for _, event := range events {
+		if event == nil {
+			continue
+		}
+		if encodeErr = encoder.Encode(event); encodeErr != nil {
+			break
+		}
+		exported++
+	}
+
+	if encodeErr != nil {
+		s.logger.Error("Failed to stream buffer export: %v", encodeErr)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	s.logger.Debug("Exported %d events from buffer", exported)
+}
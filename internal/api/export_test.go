@@ -0,0 +1,50 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+func TestBufferExportRequiresAdmin(t *testing.T) {
+	s := newTestServer(t, &model.FlightEvent{ICAO24: "abc123"})
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/buffer/export", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestBufferExportNDJSON(t *testing.T) {
+	s := newTestServer(t,
+		&model.FlightEvent{ICAO24: "aaa111"},
+		&model.FlightEvent{ICAO24: "bbb222"},
+	)
+	s.SetAdminEnabled(true)
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/buffer/export", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get("Content-Disposition"); !strings.HasPrefix(got, "attachment;") {
+		t.Errorf("Content-Disposition = %q, want an attachment", got)
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var event model.FlightEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("line %q is not a JSON event: %v", scanner.Text(), err)
+		}
+		ids = append(ids, event.ICAO24)
+	}
+	if len(ids) != 2 || ids[0] != "aaa111" || ids[1] != "bbb222" {
+		t.Errorf("exported %v, want [aaa111 bbb222]", ids)
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Response formats for event listings
+const (
+	formatJSON    = "json"
+	formatCSV     = "csv"
+	formatGeoJSON = "geojson"
+)
+
+// acceptFormats maps the media types /events can serve to their formats
+var acceptFormats = map[string]string{
+	"application/json":     formatJSON,
+	"text/csv":             formatCSV,
+	"application/geo+json": formatGeoJSON,
+}
+
+// parseFormat returns the response format for /events. A ?format= parameter
+// wins. Otherwise the Accept header's most preferred supported media type is
+// used, by q-value and then by order, with q=0 meaning "not acceptable".
+// Anything else gets JSON. It reports false for unknown format parameters.
+func parseFormat(r *http.Request) (string, bool) {
+	switch format := strings.ToLower(r.URL.Query().Get("format")); format {
+	case formatJSON, formatCSV, formatGeoJSON:
+		return format, true
+	case "":
+	default:
+		return "", false
+	}
+
+	best, bestQ := formatJSON, 0.0
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		format, ok := acceptFormats[mediaType]
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+
+	return best, true
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		accept string
+		want   string
+		ok     bool
+	}{
+		{"default", "", "", formatJSON, true},
+		{"wildcard", "", "*/*", formatJSON, true},
+		{"csv", "", "text/csv", formatCSV, true},
+		{"geojson", "", "application/geo+json", formatGeoJSON, true},
+		{"first supported type", "", "text/html, text/csv, application/json", formatCSV, true},
+		{"csv refused with q=0", "", "text/csv;q=0", formatJSON, true},
+		{"csv refused, geojson taken", "", "text/csv;q=0, application/geo+json", formatGeoJSON, true},
+		{"json preferred by q", "", "text/csv;q=0.5, application/json", formatJSON, true},
+		{"csv preferred by q", "", "application/json;q=0.4, text/csv;q=0.9", formatCSV, true},
+		{"equal q keeps order", "", "application/geo+json;q=0.8, text/csv;q=0.8", formatGeoJSON, true},
+		{"bad q ignored", "", "text/csv;q=high", formatJSON, true},
+		{"envelope parameter", "", "application/json; envelope=false", formatJSON, true},
+		{"parameter wins", "format=csv", "application/geo+json", formatCSV, true},
+		{"parameter is case-insensitive", "format=GeoJSON", "", formatGeoJSON, true},
+		{"unknown parameter", "format=xml", "text/csv", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/events?"+tt.query, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			got, ok := parseFormat(r)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("parseFormat() = %q, %v; want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestEventsGeoJSON(t *testing.T) {
+	s := newTestServer(t,
+		&model.FlightEvent{ICAO24: "abc123", Callsign: "SWR100", Longitude: floatPtr(8.55), Latitude: floatPtr(47.46), Velocity: floatPtr(230)},
+		&model.FlightEvent{ICAO24: "nopos1", Callsign: "NOWHERE"},
+		&model.FlightEvent{ICAO24: "def456", Longitude: floatPtr(-73.78), Latitude: floatPtr(40.64)},
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Accept", "application/geo+json")
+	w := serve(s, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/geo+json" {
+		t.Errorf("Content-Type = %q, want application/geo+json", ct)
+	}
+
+	var collection struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Type     string `json:"type"`
+			Geometry struct {
+				Type        string    `json:"type"`
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &collection); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if collection.Type != "FeatureCollection" {
+		t.Errorf("type = %q, want FeatureCollection", collection.Type)
+	}
+	if len(collection.Features) != 2 {
+		t.Fatalf("got %d features, want 2 (positionless events omitted)", len(collection.Features))
+	}
+
+	first := collection.Features[0]
+	if first.Type != "Feature" || first.Geometry.Type != "Point" {
+		t.Errorf("feature type %q geometry %q, want Feature and Point", first.Type, first.Geometry.Type)
+	}
+	if c := first.Geometry.Coordinates; len(c) != 2 || c[0] != 8.55 || c[1] != 47.46 {
+		t.Errorf("coordinates = %v, want [lon, lat] = [8.55, 47.46]", c)
+	}
+	if c := collection.Features[1].Geometry.Coordinates; len(c) != 2 || c[0] != -73.78 || c[1] != 40.64 {
+		t.Errorf("coordinates = %v, want [lon, lat] = [-73.78, 40.64]", c)
+	}
+
+	props := first.Properties
+	if props["icao24"] != "abc123" || props["callsign"] != "SWR100" || props["velocity"] != float64(230) {
+		t.Errorf("properties = %v", props)
+	}
+	for _, key := range []string{"true_track", "baro_altitude"} {
+		if value, ok := props[key]; !ok || value != nil {
+			t.Errorf("%s = %v (present %v), want null", key, value, ok)
+		}
+	}
+	if strings.Contains(w.Body.String(), "nopos1") {
+		t.Error("event without a position was included")
+	}
+}
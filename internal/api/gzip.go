@@ -0,0 +1,186 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// gzipMinSize is the smallest response worth compressing; below it the gzip
+// framing outweighs the savings
+const gzipMinSize = 1024
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} { return gzip.NewWriter(nil) },
+}
+
+// gzipHandler compresses responses for clients that accept gzip. Responses
+// are held back until they reach gzipMinSize, so small ones are sent as is.
+// Event streams, upgraded connections and responses that already carry a
+// Content-Encoding pass through untouched.
+func gzipHandler(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r) || r.Header.Get("Upgrade") != "" || r.Method == http.MethodHead {
+			handler(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.close()
+		handler(gw, r)
+	}
+}
+
+// acceptsGzip reports whether Accept-Encoding lists gzip with a non-zero q
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if value, err := strconv.ParseFloat(q, 64); err == nil && value == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of a response to decide whether to
+// compress it, then either streams through a gzip.Writer or passes through
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	status  int          // Status held back until the decision is made
+	buf     bytes.Buffer // Body held back until the decision is made
+	decided bool
+	gz      *gzip.Writer // Non-nil once compressing
+}
+
+// WriteHeader records the status; it is sent once the body decides the encoding
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if !w.decided && w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		if w.passthrough() {
+			w.decide(false)
+		} else {
+			w.buf.Write(p)
+			if w.buf.Len() < gzipMinSize {
+				return len(p), nil
+			}
+			w.decide(true)
+			return len(p), w.flushBuffer()
+		}
+	}
+
+	if w.gz != nil {
+		return w.gz.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// passthrough reports whether the response must not be compressed
+func (w *gzipResponseWriter) passthrough() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return true
+	}
+	if strings.HasPrefix(header.Get("Content-Type"), "text/event-stream") {
+		return true
+	}
+	return w.status == http.StatusNoContent || w.status == http.StatusNotModified
+}
+
+// decide sends the header, switching to gzip when compress is set
+func (w *gzipResponseWriter) decide(compress bool) {
+	w.decided = true
+	if compress {
+		// Sniff before compressing, or net/http would sniff the gzip bytes
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(w.buf.Bytes()))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Del("Content-Length")
+		w.gz = gzipWriters.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// flushBuffer writes any held-back body through the chosen encoding
+func (w *gzipResponseWriter) flushBuffer() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	data := w.buf.Bytes()
+	w.buf = bytes.Buffer{}
+
+	var err error
+	if w.gz != nil {
+		_, err = w.gz.Write(data)
+	} else {
+		_, err = w.ResponseWriter.Write(data)
+	}
+	return err
+}
+
+// Flush sends everything written so far. Flushing before the size threshold
+// is reached commits to an uncompressed response, which keeps streams such as
+// SSE working.
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		w.decide(false)
+		w.flushBuffer()
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Hijack lets WebSocket upgrades take over the connection; they write their
+// own response, so nothing is buffered or compressed
+func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// close finishes the response once the handler returns. Nothing is written
+// if the handler never responded, e.g. because it hijacked the connection.
+func (w *gzipResponseWriter) close() {
+	if !w.decided {
+		if w.status == 0 && w.buf.Len() == 0 {
+			return
+		}
+		w.decide(false)
+		w.flushBuffer()
+	}
+
+	if w.gz != nil {
+		w.gz.Close()
+		w.gz.Reset(nil)
+		gzipWriters.Put(w.gz)
+		w.gz = nil
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+// getWithEncoding requests path with the given Accept-Encoding header
+func getWithEncoding(s *Server, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	return serve(s, req)
+}
+
+func TestGzipCompressesLargeResponses(t *testing.T) {
+	events := make([]*model.FlightEvent, 200)
+	for i := range events {
+		events[i] = &model.FlightEvent{ICAO24: fmt.Sprintf("e%05d", i), OriginCountry: "Germany", Latitude: floatPtr(47.4), Longitude: floatPtr(8.5)}
+	}
+	s := newTestServer(t, events...)
+
+	plain := getWithEncoding(s, "/events?envelope=false", "")
+	if plain.Header().Get("Content-Encoding") != "" {
+		t.Fatal("response compressed for a client that didn't accept gzip")
+	}
+
+	w := getWithEncoding(s, "/events?envelope=false", "deflate, gzip;q=0.8")
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if got := w.Header().Get("Vary"); !strings.Contains(got, "Accept-Encoding") {
+		t.Errorf("Vary = %q, want Accept-Encoding", got)
+	}
+	if w.Header().Get("Content-Length") != "" {
+		t.Error("compressed response carries the uncompressed Content-Length")
+	}
+	if w.Body.Len() >= plain.Body.Len() {
+		t.Errorf("compressed body is %d bytes, not smaller than %d", w.Body.Len(), plain.Body.Len())
+	}
+
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("body is not gzip: %v", err)
+	}
+	decoded, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("reading gzip body: %v", err)
+	}
+	if !bytes.Equal(decoded, plain.Body.Bytes()) {
+		t.Error("decompressed body differs from the uncompressed response")
+	}
+}
+
+func TestGzipPassesSmallResponsesThrough(t *testing.T) {
+	s := newTestServer(t, &model.FlightEvent{ICAO24: "abc123"})
+
+	w := getWithEncoding(s, "/events/abc123", "gzip")
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q for a small response, want none", got)
+	}
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"abc123"`) {
+		t.Errorf("status %d body %q, want the event uncompressed", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Vary"); !strings.Contains(got, "Accept-Encoding") {
+		t.Errorf("Vary = %q, want Accept-Encoding on uncompressed responses too", got)
+	}
+
+	// Errors keep their status when passed through
+	w = getWithEncoding(s, "/events/ffffff", "gzip")
+	if w.Code != http.StatusNotFound || w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("status %d encoding %q, want an uncompressed 404", w.Code, w.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"GZIP", true},
+		{"deflate, br", false},
+		{"br, gzip;q=0.5", true},
+		{"gzip;q=0", false},
+		{"gzip; q=0.0", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Encoding", tt.header)
+		if got := acceptsGzip(r); got != tt.want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package api
+
+import "time"
+
+// Health statuses reported by /health
+const (
+	healthHealthy   = "healthy"
+	healthDegraded  = "degraded"
+	healthUnhealthy = "unhealthy"
+)
+
+// Thresholds for /health. Poll ages are multiples of the poll interval.
+const (
+	degradedPollIntervals  = 2   // Last success older than this many intervals is degraded
+	unhealthyPollIntervals = 3   // Last success older than this many intervals is unhealthy
+	degradedAPIErrorRate   = 0.1 // Upstream error rate at or above this is degraded
+)
+
+// healthStatus derives the service status from the time since the last
+// successful poll and the upstream API error rate. Poll age is ignored when
+// pollInterval is 0.
+func healthStatus(sincePoll time.Duration, apiErrorRate float64, pollInterval time.Duration) string {
+	if pollInterval > 0 && sincePoll > unhealthyPollIntervals*pollInterval {
+		return healthUnhealthy
+	}
+	if pollInterval > 0 && sincePoll > degradedPollIntervals*pollInterval {
+		return healthDegraded
+	}
+	if apiErrorRate >= degradedAPIErrorRate {
+		return healthDegraded
+	}
+	return healthHealthy
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// readyzStatus requests /readyz and returns the status code and reported status
+func readyzStatus(t *testing.T, s *Server) (int, string) {
+	t.Helper()
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var body struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decode /readyz: %v", err)
+	}
+	return w.Code, body.Status
+}
+
+func TestReadyzTogglesWithReadiness(t *testing.T) {
+	s := newTestServer(t)
+
+	steps := []struct {
+		name       string
+		apply      func()
+		wantCode   int
+		wantStatus string
+	}{
+		{"before first poll", func() {}, http.StatusServiceUnavailable, "not_ready"},
+		{"after first poll", func() { s.SetReady(true) }, http.StatusOK, "ready"},
+		{"stalled", func() { s.SetStalled(true) }, http.StatusServiceUnavailable, "stalled"},
+		{"recovered", func() { s.SetStalled(false) }, http.StatusOK, "ready"},
+		{"readiness cleared", func() { s.SetReady(false) }, http.StatusServiceUnavailable, "not_ready"},
+	}
+	for _, step := range steps {
+		step.apply()
+		code, status := readyzStatus(t, s)
+		if code != step.wantCode || status != step.wantStatus {
+			t.Errorf("%s: /readyz = %d %q, want %d %q", step.name, code, status, step.wantCode, step.wantStatus)
+		}
+	}
+}
+
+func TestHealthIgnoresReadiness(t *testing.T) {
+	s := newTestServer(t)
+
+	// A live but not yet ready instance must not be restarted by its liveness probe
+	if w := serve(s, httptest.NewRequest(http.MethodGet, "/health", nil)); w.Code != http.StatusOK {
+		t.Errorf("/health before readiness = %d, want 200", w.Code)
+	}
+}
+
+func TestHealthStatusThresholds(t *testing.T) {
+	interval := 10 * time.Second
+	tests := []struct {
+		name      string
+		sincePoll time.Duration
+		errorRate float64
+		want      string
+	}{
+		{"on schedule", 5 * time.Second, 0, healthHealthy},
+		{"one poll missed", 15 * time.Second, 0, healthHealthy},
+		{"polls overdue", 25 * time.Second, 0, healthDegraded},
+		{"upstream failing", 5 * time.Second, 0.5, healthDegraded},
+		{"error rate at threshold", 5 * time.Second, degradedAPIErrorRate, healthDegraded},
+		{"no poll within 3 intervals", 31 * time.Second, 0, healthUnhealthy},
+		{"no poll and failing", 31 * time.Second, 1, healthUnhealthy},
+	}
+	for _, tt := range tests {
+		if got := healthStatus(tt.sincePoll, tt.errorRate, interval); got != tt.want {
+			t.Errorf("%s: healthStatus(%v, %v) = %q, want %q", tt.name, tt.sincePoll, tt.errorRate, got, tt.want)
+		}
+	}
+
+	// Without a poll interval only the error rate counts
+	if got := healthStatus(time.Hour, 0, 0); got != healthHealthy {
+		t.Errorf("healthStatus without interval = %q, want %q", got, healthHealthy)
+	}
+}
+
+func TestHealthReportsPollAgeButStaysLive(t *testing.T) {
+	tests := []struct {
+		name    string
+		pollAge time.Duration
+		want    string
+		readyz  string
+	}{
+		{"healthy", time.Second, healthHealthy, "ready"},
+		{"degraded", 25 * time.Second, healthDegraded, "ready"},
+		{"unhealthy", time.Minute, healthUnhealthy, healthUnhealthy},
+	}
+	for _, tt := range tests {
+		s := newTestServer(t)
+		s.SetPollInterval(10 * time.Second)
+		s.SetReady(true)
+		lastPoll := time.Now().Add(-tt.pollAge)
+		s.metrics.SetLastSuccessfulPoll(lastPoll)
+
+		w := serve(s, httptest.NewRequest(http.MethodGet, "/health", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: /health = %d, want 200", tt.name, w.Code)
+		}
+		var body struct {
+			Status   string `json:"status"`
+			LastPoll int64  `json:"last_successful_poll_unix"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: decode /health: %v", tt.name, err)
+		}
+		if body.Status != tt.want {
+			t.Errorf("%s: status = %q, want %q", tt.name, body.Status, tt.want)
+		}
+		if body.LastPoll != lastPoll.Unix() {
+			t.Errorf("%s: last_successful_poll_unix = %d, want %d", tt.name, body.LastPoll, lastPoll.Unix())
+		}
+
+		// Only an unhealthy instance is taken out of rotation
+		if _, status := readyzStatus(t, s); status != tt.readyz {
+			t.Errorf("%s: /readyz status = %q, want %q", tt.name, status, tt.readyz)
+		}
+	}
+}
@@ -1,46 +1,170 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"flight-event-throttler/internal/buffer"
+	"flight-event-throttler/internal/config"
 	"flight-event-throttler/internal/metrics"
+	"flight-event-throttler/internal/model"
+	"flight-event-throttler/internal/processor"
+	"flight-event-throttler/internal/redaction"
 	"flight-event-throttler/pkg/logger"
 )
 
+// maxBatchWait caps /events/batch long-polling below the default write timeout
+const maxBatchWait = 10 * time.Second
+
+// metricsStaleAfter is how long the metrics rate ticker may go without running
+// before it is reported as stale
+const metricsStaleAfter = 5 * time.Second
+
 // Server represents the HTTP API server
 type Server struct {
-	logger      *logger.Logger
-	metrics     *metrics.Metrics
-	ringBuffer  *buffer.RingBuffer
-	slidingWin  *buffer.SlidingWindowBuffer
-	bufferType  string
+	logger       logger.Interface
+	metrics      *metrics.Metrics
+	ringBuffer   *buffer.FlightEventRingBuffer
+	slidingWin   *buffer.SlidingWindowBuffer
+	bufferType   string
+	basePath     string
+	adminEnabled bool
+	adminToken   string
+
+	autoscaleWeights   AutoscaleWeights
+	throughputCapacity func() int
+
+	redaction *redaction.Policy
+
+	coverage coverageCache
+
+	stalled atomic.Bool
+	ready   atomic.Bool // Set once the first poll succeeds
+
+	pollInterval time.Duration // Expected time between polls; 0 disables poll age checks
+
+	lastGood *buffer.LastGood
+
+	maxEventsLimit int
+
+	cors        CORSPolicy
+	corsOrigins map[string]struct{} // Exact allowed origins
+	corsAny     bool                // "*" is allowed
+
+	clientLimiter    *processor.KeyedRateLimiter // Per-IP request limit; nil disables it
+	clientRetryAfter string                      // Retry-After seconds sent with 429s
+	trustForwarded   bool                        // Identify clients by X-Forwarded-For
+
+	hub *EventHub
+
+	configSource func() *config.Config
+
+	ingest          IngestFunc // Handles POST /events; nil disables it
+	maxRequestBytes int64      // Largest request body ingest endpoints accept
 }
 
 // NewServer creates a new HTTP server instance
-func NewServer(log *logger.Logger, m *metrics.Metrics, ringBuf *buffer.RingBuffer, slidingWin *buffer.SlidingWindowBuffer, bufferType string) *Server {
+// The admin token guards endpoints such as /metrics/reset; when empty they are disabled.
+func NewServer(log logger.Interface, m *metrics.Metrics, ringBuf *buffer.FlightEventRingBuffer, slidingWin *buffer.SlidingWindowBuffer, bufferType string, adminToken string) *Server {
 	return &Server{
 		logger:     log,
 		metrics:    m,
 		ringBuffer: ringBuf,
 		slidingWin: slidingWin,
 		bufferType: bufferType,
+		adminToken: adminToken,
+
+		maxEventsLimit:  defaultMaxEventsLimit,
+		maxRequestBytes: defaultMaxRequestBytes,
 	}
 }
 
+// SetBasePath sets a prefix (e.g. "/throttler") applied to every route
+func (s *Server) SetBasePath(basePath string) {
+	s.basePath = strings.TrimSuffix(basePath, "/")
+}
+
+// BasePath returns the route prefix as applied, without a trailing slash
+func (s *Server) BasePath() string {
+	return s.basePath
+}
+
+// SetAdminEnabled enables endpoints that expose or modify all buffered data
+func (s *Server) SetAdminEnabled(enabled bool) {
+	s.adminEnabled = enabled
+}
+
+// SetMaxEventsLimit sets the largest page /events returns; larger limits are
+// clamped to it
+func (s *Server) SetMaxEventsLimit(limit int) {
+	if limit > 0 {
+		s.maxEventsLimit = limit
+	}
+}
+
+// SetPollInterval sets the expected time between polls, used by /health to
+// judge how overdue the last successful poll is
+func (s *Server) SetPollInterval(interval time.Duration) {
+	s.pollInterval = interval
+}
+
+// SetRedactionPolicy sets the policy applied to events before they are served
+func (s *Server) SetRedactionPolicy(policy *redaction.Policy) {
+	s.redaction = policy
+}
+
+// SetStalled marks the pipeline as stalled, failing /readyz until cleared
+func (s *Server) SetStalled(stalled bool) {
+	s.stalled.Store(stalled)
+}
+
+// SetReady marks the service as ready to serve traffic; /readyz fails until
+// it is set
+func (s *Server) SetReady(ready bool) {
+	s.ready.Store(ready)
+}
+
 // SetupRoutes configures all HTTP routes
 func (s *Server) SetupRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/health", s.handleHealth)
-	mux.HandleFunc("/metrics", s.handleMetrics)
-	mux.HandleFunc("/events", s.handleEvents)
-	mux.HandleFunc("/events/batch", s.handleEventsBatch)
-	mux.HandleFunc("/buffer/stats", s.handleBufferStats)
+	s.handle(mux, "/health", s.handleHealth)
+	s.handle(mux, "/readyz", s.handleReadyz)
+	s.handle(mux, "/metrics", s.handleMetrics)
+	s.handle(mux, "/metrics/prometheus", s.handleMetricsPrometheus)
+	s.handle(mux, "/metrics/reset", s.handleMetricsReset)
+	s.handle(mux, "/config", s.handleConfig)
+	s.handle(mux, "/events", s.handleEvents)
+	s.handle(mux, "/events/batch", s.handleEventsBatch)
+	s.handle(mux, "/events/top", s.handleEventsTop)
+	s.handle(mux, "/events/aggregate", s.handleEventsAggregate)
+	s.handle(mux, "/events/lastgood", s.handleEventsLastGood)
+	s.handle(mux, "/events/stream", s.handleEventsStream)
+	s.handle(mux, "/events/near", s.handleEventsNear)
+	// Registered without a method so the fixed /events/... routes above take
+	// precedence instead of conflicting with the wildcard
+	s.handle(mux, "/events/{icao24}", s.handleEventsAircraft)
+	s.handle(mux, "/ws", s.handleWebSocket)
+	s.handle(mux, "/stats/top", s.handleStatsTop)
+	s.handle(mux, "/buffer/stats", s.handleBufferStats)
+	s.handle(mux, "/buffer/export", s.handleBufferExport)
+	s.handle(mux, "/buffer/overflow/drain", s.handleBufferOverflowDrain)
+	s.handle(mux, "/buffer/coverage", s.handleBufferCoverage)
+	s.handle(mux, "/autoscale", s.handleAutoscale)
+}
+
+// path prefixes a route with the configured base path
+func (s *Server) path(route string) string {
+	return s.basePath + route
 }
 
-// handleHealth returns the health status of the service
+// handleHealth returns the health status of the service: healthy, degraded
+// while OpenSky is failing or polls are overdue, or unhealthy once no poll has
+// succeeded for too long. It is a liveness check and always returns 200, so an
+// upstream outage never gets the service restarted; /readyz fails instead.
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -49,14 +173,64 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 
 	s.metrics.IncrementHTTPRequests()
 
+	now := time.Now()
+	lastPoll := s.metrics.GetLastSuccessfulPoll()
+	errorRate := s.metrics.GetRecentAPIErrorRate()
+
 	response := map[string]interface{}{
-		"status":    "healthy",
-		"timestamp": time.Now().Unix(),
-		"uptime":    s.metrics.GetUptime().String(),
+		"status":                    s.currentHealth(now, lastPoll, errorRate),
+		"timestamp":                 now.Unix(),
+		"uptime":                    s.metrics.GetUptime().String(),
+		"metrics_stale":             s.metrics.IsRateTickerStale(metricsStaleAfter),
+		"last_successful_poll_unix": lastPoll,
+		"api_error_rate":            errorRate,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// currentHealth derives the current status from the last successful poll (Unix
+// seconds, 0 if none) and the recent upstream error rate
+func (s *Server) currentHealth(now time.Time, lastPoll int64, errorRate float64) string {
+	// Before the first success, measure from startup so a fresh instance
+	// isn't reported unhealthy
+	sincePoll := s.metrics.GetUptime()
+	if lastPoll > 0 {
+		sincePoll = now.Sub(time.Unix(lastPoll, 0))
+	}
+	return healthStatus(sincePoll, errorRate, s.pollInterval)
+}
+
+// handleReadyz reports whether the service is ready to serve traffic. It
+// returns 503 until the first poll succeeds, while the pipeline is stalled and
+// while /health reports unhealthy.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	now := time.Now()
+	status, code := "ready", http.StatusOK
+	switch {
+	case !s.ready.Load():
+		status, code = "not_ready", http.StatusServiceUnavailable
+	case s.stalled.Load():
+		status, code = "stalled", http.StatusServiceUnavailable
+	case s.currentHealth(now, s.metrics.GetLastSuccessfulPoll(), s.metrics.GetRecentAPIErrorRate()) == healthUnhealthy:
+		status, code = healthUnhealthy, http.StatusServiceUnavailable
+	}
+
+	response := map[string]interface{}{
+		"status":    status,
+		"timestamp": now.Unix(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -76,8 +250,30 @@ func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(snapshot)
 }
 
-// handleEvents returns all current events from the buffer
+// handleMetricsPrometheus returns current metrics in the Prometheus text exposition format
+func (s *Server) handleMetricsPrometheus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := s.metrics.WritePrometheus(w); err != nil {
+		s.logger.Error("Failed to write Prometheus metrics: %v", err)
+		s.metrics.IncrementHTTPErrors()
+	}
+}
+
+// handleEvents returns all current events from the buffer. When ingestion is
+// enabled, POST accepts events pushed by external producers.
 func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && s.ingest != nil {
+		s.handleEventsIngest(w, r)
+		return
+	}
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -85,7 +281,35 @@ func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 
 	s.metrics.IncrementHTTPRequests()
 
-	var events interface{}
+	units, ok := parseUnits(r)
+	if !ok {
+		http.Error(w, "Query parameter 'units' must be 'si' or 'imperial'", http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	format, ok := parseFormat(r)
+	if !ok {
+		http.Error(w, "Query parameter 'format' must be 'json', 'csv' or 'geojson'", http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	query, err := parseEventQuery(r)
+	if err != nil {
+		http.Error(w, "Invalid filter: "+err.Error(), http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	limit, offset, err := parsePage(r, s.maxEventsLimit)
+	if err != nil {
+		http.Error(w, "Invalid page: "+err.Error(), http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	var events []*model.FlightEvent
 
 	if s.bufferType == "ring" && s.ringBuffer != nil {
 		events = s.ringBuffer.GetAll()
@@ -98,9 +322,39 @@ func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := map[string]interface{}{
-		"events":    events,
-		"timestamp": time.Now().Unix(),
+	// Filter after redaction so redacted values can't be probed through filters
+	events = query.filter(s.projectEvents(events, units))
+	total := len(events)
+	events = paginate(events, offset, limit)
+
+	switch format {
+	case formatCSV:
+		if err := writeEventsCSV(w, events); err != nil {
+			s.logger.Error("Failed to write CSV events response: %v", err)
+			s.metrics.IncrementHTTPErrors()
+		}
+		return
+	case formatGeoJSON:
+		w.Header().Set("Content-Type", "application/geo+json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(model.NewGeoJSONFeatureCollection(events)); err != nil {
+			s.logger.Error("Failed to encode GeoJSON events response: %v", err)
+			s.metrics.IncrementHTTPErrors()
+		}
+		return
+	}
+
+	var response interface{} = events
+	if wantsEnvelope(r) {
+		response = map[string]interface{}{
+			"events":    response,
+			"total":     total,
+			"limit":     limit,
+			"offset":    offset,
+			"timestamp": time.Now().Unix(),
+		}
+	} else if events == nil {
+		response = []*model.FlightEvent{}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -129,10 +383,37 @@ func (s *Server) handleEventsBatch(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	var events interface{}
+	units, ok := parseUnits(r)
+	if !ok {
+		http.Error(w, "Query parameter 'units' must be 'si' or 'imperial'", http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	// Optional long-poll: wait up to this long for the first event when empty
+	var wait time.Duration
+	if waitStr := r.URL.Query().Get("wait"); waitStr != "" {
+		parsed, err := time.ParseDuration(waitStr)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Query parameter 'wait' must be a non-negative duration", http.StatusBadRequest)
+			s.metrics.IncrementHTTPErrors()
+			return
+		}
+		wait = min(parsed, maxBatchWait)
+	}
+
+	var events []*model.FlightEvent
 
 	if s.bufferType == "ring" && s.ringBuffer != nil {
 		events = s.ringBuffer.PopBatch(batchSize)
+		if len(events) == 0 && wait > 0 {
+			waitCtx, cancel := context.WithTimeout(r.Context(), wait)
+			first, err := s.ringBuffer.PopBlocking(waitCtx)
+			cancel()
+			if err == nil {
+				events = append([]*model.FlightEvent{first}, s.ringBuffer.PopBatch(batchSize-1)...)
+			}
+		}
 	} else if s.bufferType == "sliding_window" && s.slidingWin != nil {
 		events = s.slidingWin.PopBatch(batchSize)
 	} else {
@@ -142,10 +423,15 @@ func (s *Server) handleEventsBatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := map[string]interface{}{
-		"events":     events,
-		"batch_size": batchSize,
-		"timestamp":  time.Now().Unix(),
+	var response interface{} = s.projectEvents(events, units)
+	if wantsEnvelope(r) {
+		response = map[string]interface{}{
+			"events":     response,
+			"batch_size": batchSize,
+			"timestamp":  time.Now().Unix(),
+		}
+	} else if events == nil {
+		response = []*model.FlightEvent{}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -156,6 +442,16 @@ func (s *Server) handleEventsBatch(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// fieldStats formats min/max/mean statistics along with the number of samples
+func fieldStats(min, max, mean float64, count int) map[string]interface{} {
+	return map[string]interface{}{
+		"min":     min,
+		"max":     max,
+		"mean":    mean,
+		"samples": count,
+	}
+}
+
 // handleBufferStats returns buffer statistics
 func (s *Server) handleBufferStats(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -175,10 +471,14 @@ func (s *Server) handleBufferStats(w http.ResponseWriter, r *http.Request) {
 			"is_empty": s.ringBuffer.IsEmpty(),
 		}
 	} else if s.bufferType == "sliding_window" && s.slidingWin != nil {
+		altMin, altMax, altMean, altCount := s.slidingWin.AltitudeStats()
+		velMin, velMax, velMean, velCount := s.slidingWin.VelocityStats()
 		stats = map[string]interface{}{
 			"type":     "sliding_window",
 			"count":    s.slidingWin.Count(),
 			"is_empty": s.slidingWin.IsEmpty(),
+			"altitude": fieldStats(altMin, altMax, altMean, altCount),
+			"velocity": fieldStats(velMin, velMax, velMean, velCount),
 		}
 	} else {
 		s.logger.Error("No buffer configured")
@@ -197,6 +497,30 @@ func (s *Server) handleBufferStats(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// forEachEvent iterates the configured buffer from oldest to newest.
+// It returns false if no buffer is configured.
+func (s *Server) forEachEvent(fn func(event *model.FlightEvent) bool) bool {
+	if s.bufferType == "ring" && s.ringBuffer != nil {
+		s.ringBuffer.ForEach(fn)
+		return true
+	} else if s.bufferType == "sliding_window" && s.slidingWin != nil {
+		s.slidingWin.ForEach(fn)
+		return true
+	}
+	return false
+}
+
+// getAllEvents copies the events in the configured buffer, oldest to newest.
+// It returns false if no buffer is configured.
+func (s *Server) getAllEvents() ([]*model.FlightEvent, bool) {
+	if s.bufferType == "ring" && s.ringBuffer != nil {
+		return s.ringBuffer.GetAll(), true
+	} else if s.bufferType == "sliding_window" && s.slidingWin != nil {
+		return s.slidingWin.GetAll(), true
+	}
+	return nil, false
+}
+
 // parsePositiveInt parses a string to a positive integer
 func parsePositiveInt(s string) (int, error) {
 	var n int
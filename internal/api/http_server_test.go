@@ -0,0 +1,67 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/buffer"
+	"flight-event-throttler/internal/metrics"
+	"flight-event-throttler/internal/model"
+	"flight-event-throttler/pkg/logger"
+)
+
+// newTestServer returns a server backed by a ring buffer holding events
+func newTestServer(t *testing.T, events ...*model.FlightEvent) *Server {
+	t.Helper()
+
+	m := metrics.NewMetrics()
+	t.Cleanup(m.Close)
+
+	size := len(events)
+	if size < 16 {
+		size = 16
+	}
+	rb := buffer.NewFlightEventRingBuffer(size)
+	for _, event := range events {
+		rb.Push(event)
+	}
+
+	return NewServer(logger.NewWithWriter("error", io.Discard), m, rb, nil, "ring", "secret")
+}
+
+// serve runs a request through the server's routes and returns the recorded response
+func serve(s *Server, r *http.Request) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	s.SetupRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	return w
+}
+
+func floatPtr(f float64) *float64 { return &f }
+
+func stringPtr(s string) *string { return &s }
+
+func TestSetBasePathPrefixesRoutes(t *testing.T) {
+	s := newTestServer(t, &model.FlightEvent{ICAO24: "abc123"})
+	s.SetBasePath("/throttler/")
+	s.SetReady(true)
+	s.metrics.SetLastSuccessfulPoll(time.Now())
+
+	if got := s.BasePath(); got != "/throttler" {
+		t.Errorf("BasePath() = %q, want the trailing slash trimmed", got)
+	}
+
+	for _, route := range []string{"/health", "/readyz", "/events"} {
+		if w := serve(s, httptest.NewRequest(http.MethodGet, "/throttler"+route, nil)); w.Code != http.StatusOK {
+			t.Errorf("GET /throttler%s status = %d, want 200", route, w.Code)
+		}
+		if w := serve(s, httptest.NewRequest(http.MethodGet, route, nil)); w.Code != http.StatusNotFound {
+			t.Errorf("GET %s status = %d, want 404 without the prefix", route, w.Code)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"sync"
+
+	"flight-event-throttler/internal/model"
+)
+
+// subscriberBuffer is how many events a streaming client may fall behind
+// before further events are dropped for it
+const subscriberBuffer = 64
+
+// EventPredicate reports whether a subscriber wants an event. A nil
+// predicate matches every event.
+type EventPredicate func(event *model.FlightEvent) bool
+
+// subscriber is a streaming client's channel and the events it wants
+type subscriber struct {
+	ch    chan *model.FlightEvent
+	match EventPredicate
+}
+
+// EventHub fans out processed events to streaming clients. A slow client
+// never blocks the pipeline or other clients: events that don't fit in its
+// buffer are dropped for that client only. Each subscriber may register a
+// predicate so the hub only forwards the events it wants.
+type EventHub struct {
+	mu          sync.Mutex
+	subscribers map[<-chan *model.FlightEvent]*subscriber
+	closed      bool
+	onDropped   func()
+}
+
+// NewEventHub creates an event hub with no subscribers
+func NewEventHub() *EventHub {
+	return &EventHub{
+		subscribers: make(map[<-chan *model.FlightEvent]*subscriber),
+	}
+}
+
+// OnDropped registers a callback invoked each time an event is dropped for a
+// lagging subscriber, e.g. to count drops in metrics. It must be set before
+// Run is called.
+func (h *EventHub) OnDropped(fn func()) {
+	h.onDropped = fn
+}
+
+// Run broadcasts events, typically the processor's output channel, to all
+// subscribers until the channel is closed. It then closes every subscriber
+// channel so streaming handlers return.
+func (h *EventHub) Run(events <-chan *model.FlightEvent) {
+	for event := range events {
+		h.broadcast(event)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for key, sub := range h.subscribers {
+		delete(h.subscribers, key)
+		close(sub.ch)
+	}
+}
+
+// broadcast offers the event to every subscriber whose predicate matches it,
+// without blocking. Events a subscriber filtered out are never counted as
+// dropped for it.
+func (h *EventHub) broadcast(event *model.FlightEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, sub := range h.subscribers {
+		if sub.match != nil && !sub.match(event) {
+			continue
+		}
+
+		select {
+		case sub.ch <- event:
+		default:
+			// Subscriber is lagging
+			if h.onDropped != nil {
+				h.onDropped()
+			}
+		}
+	}
+}
+
+// Subscribe registers a new subscriber that receives the events match
+// accepts, or every event if match is nil. The returned channel is closed
+// when the hub stops; the returned function unsubscribes and must be called
+// once the subscriber is done.
+func (h *EventHub) Subscribe(match EventPredicate) (<-chan *model.FlightEvent, func()) {
+	ch := make(chan *model.FlightEvent, subscriberBuffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	h.subscribers[ch] = &subscriber{ch: ch, match: match}
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// SetFilter replaces the predicate of the subscriber receiving on events,
+// taking effect from the next broadcast. A nil predicate matches every event.
+func (h *EventHub) SetFilter(events <-chan *model.FlightEvent, match EventPredicate) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if sub, ok := h.subscribers[events]; ok {
+		sub.match = match
+	}
+}
+
+// Subscribers returns the number of connected subscribers
+func (h *EventHub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.subscribers)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// receive returns the next event from ch, failing the test after a timeout
+func receive(t *testing.T, ch <-chan *model.FlightEvent) *model.FlightEvent {
+	t.Helper()
+
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for an event")
+		return nil
+	}
+}
+
+// expectNone fails the test if ch holds an event
+func expectNone(t *testing.T, ch <-chan *model.FlightEvent) {
+	t.Helper()
+
+	select {
+	case event := <-ch:
+		t.Fatalf("unexpected event %+v", event)
+	default:
+	}
+}
+
+func TestHubForwardsOnlyMatchingEvents(t *testing.T) {
+	hub := NewEventHub()
+	german, unsubscribe := hub.Subscribe(func(event *model.FlightEvent) bool {
+		return event.OriginCountry == "Germany"
+	})
+	defer unsubscribe()
+	all, unsubscribeAll := hub.Subscribe(nil)
+	defer unsubscribeAll()
+
+	hub.broadcast(&model.FlightEvent{ICAO24: "aaa111", OriginCountry: "France"})
+	hub.broadcast(&model.FlightEvent{ICAO24: "bbb222", OriginCountry: "Germany"})
+
+	if got := receive(t, german); got.ICAO24 != "bbb222" {
+		t.Errorf("filtered subscriber got %s, want bbb222", got.ICAO24)
+	}
+	expectNone(t, german)
+
+	if got := receive(t, all); got.ICAO24 != "aaa111" {
+		t.Errorf("unfiltered subscriber got %s, want aaa111", got.ICAO24)
+	}
+	if got := receive(t, all); got.ICAO24 != "bbb222" {
+		t.Errorf("unfiltered subscriber got %s, want bbb222", got.ICAO24)
+	}
+}
+
+func TestHubFilteredEventsAreNotDrops(t *testing.T) {
+	hub := NewEventHub()
+	var dropped atomic.Int64
+	hub.OnDropped(func() { dropped.Add(1) })
+
+	_, unsubscribe := hub.Subscribe(func(event *model.FlightEvent) bool { return false })
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBuffer*2; i++ {
+		hub.broadcast(&model.FlightEvent{ICAO24: "aaa111"})
+	}
+
+	if got := dropped.Load(); got != 0 {
+		t.Errorf("dropped = %d, want 0 for events the subscriber filtered out", got)
+	}
+}
+
+func TestHubSetFilter(t *testing.T) {
+	hub := NewEventHub()
+	events, unsubscribe := hub.Subscribe(nil)
+	defer unsubscribe()
+
+	hub.SetFilter(events, func(event *model.FlightEvent) bool { return event.OnGround })
+	hub.broadcast(&model.FlightEvent{ICAO24: "air111"})
+	hub.broadcast(&model.FlightEvent{ICAO24: "gnd222", OnGround: true})
+
+	if got := receive(t, events); got.ICAO24 != "gnd222" {
+		t.Errorf("got %s, want gnd222", got.ICAO24)
+	}
+	expectNone(t, events)
+}
+
+func TestEventsStreamRejectsInvalidFilter(t *testing.T) {
+	s := newTestServer(t)
+	s.SetEventHub(NewEventHub())
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/events/stream?lamin=50&lomin=0&lamax=40&lomax=10", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d for an inverted bounding box", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"flight-event-throttler/internal/model"
+)
+
+// defaultMaxRequestBytes is the largest ingest request body accepted unless
+// configured otherwise
+const defaultMaxRequestBytes = 10 << 20
+
+// IngestFunc runs pushed events through the same pipeline as polled ones
+// (throttling, rate limiting and buffering) and returns the events accepted
+type IngestFunc func(events []*model.FlightEvent) []*model.FlightEvent
+
+// ingestError reports why one event of a pushed batch was rejected
+type ingestError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// SetIngest enables POST /events, handing valid events to ingest
+func (s *Server) SetIngest(ingest IngestFunc) {
+	s.ingest = ingest
+}
+
+// SetMaxRequestBytes sets the largest request body ingest endpoints accept;
+// larger bodies are rejected with 413
+func (s *Server) SetMaxRequestBytes(maxBytes int64) {
+	if maxBytes > 0 {
+		s.maxRequestBytes = maxBytes
+	}
+}
+
+// handleEventsIngest accepts a JSON array of events, or a batch object with
+// an "events" array, from an external producer holding the admin token.
+// Invalid events are rejected individually; the rest are submitted and the
+// response reports how many were accepted, rejected and dropped by the rate
+// limits.
+func (s *Server) handleEventsIngest(w http.ResponseWriter, r *http.Request) {
+	s.metrics.IncrementHTTPRequests()
+
+	if !s.validAdminToken(r) {
+		http.Error(w, "Invalid or missing admin token", http.StatusUnauthorized)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxRequestBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, fmt.Sprintf("Request body exceeds %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+		} else {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		}
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	events, err := decodeIngestBody(data)
+	if err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	valid := make([]*model.FlightEvent, 0, len(events))
+	rejected := []ingestError{}
+	for i, event := range events {
+		if err := validateIngestEvent(event); err != nil {
+			rejected = append(rejected, ingestError{Index: i, Error: err.Error()})
+			continue
+		}
+		valid = append(valid, event)
+	}
+
+	accepted := 0
+	if len(valid) > 0 {
+		accepted = len(s.ingest(valid))
+	}
+
+	response := map[string]interface{}{
+		"accepted": accepted,
+		"rejected": len(rejected),
+		"dropped":  len(valid) - accepted,
+		"errors":   rejected,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error("Failed to encode ingest response: %v", err)
+		s.metrics.IncrementHTTPErrors()
+	}
+}
+
+// decodeIngestBody decodes either a JSON array of events or a
+// model.FlightEventBatch
+func decodeIngestBody(data []byte) ([]*model.FlightEvent, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("body is empty")
+	}
+
+	if data[0] == '[' {
+		var events []*model.FlightEvent
+		if err := json.Unmarshal(data, &events); err != nil {
+			return nil, err
+		}
+		return events, nil
+	}
+
+	var batch model.FlightEventBatch
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, err
+	}
+	events := make([]*model.FlightEvent, len(batch.Events))
+	for i := range batch.Events {
+		events[i] = &batch.Events[i]
+	}
+	return events, nil
+}
+
+// validateIngestEvent checks a pushed event and normalizes its ICAO24 address
+// to OpenSky's lowercase form
+func validateIngestEvent(event *model.FlightEvent) error {
+	if event == nil {
+		return fmt.Errorf("event is null")
+	}
+
+	event.ICAO24 = strings.ToLower(strings.TrimSpace(event.ICAO24))
+	if len(event.ICAO24) != 6 || strings.Trim(event.ICAO24, "0123456789abcdef") != "" {
+		return fmt.Errorf("icao24 must be 6 hex digits")
+	}
+
+	if (event.Latitude == nil) != (event.Longitude == nil) {
+		return fmt.Errorf("latitude and longitude must be given together")
+	}
+	if event.Latitude != nil && (*event.Latitude < -90 || *event.Latitude > 90) {
+		return fmt.Errorf("latitude must be between -90 and 90")
+	}
+	if event.Longitude != nil && (*event.Longitude < -180 || *event.Longitude > 180) {
+		return fmt.Errorf("longitude must be between -180 and 180")
+	}
+
+	return nil
+}
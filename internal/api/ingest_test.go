@@ -0,0 +1,159 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+// acceptAll is an IngestFunc that accepts every event
+func acceptAll(events []*model.FlightEvent) []*model.FlightEvent {
+	return events
+}
+
+// ingestResponse is the body returned by POST /events
+type ingestResponse struct {
+	Accepted int           `json:"accepted"`
+	Rejected int           `json:"rejected"`
+	Dropped  int           `json:"dropped"`
+	Errors   []ingestError `json:"errors"`
+}
+
+// postEvents sends body to POST /events with the test admin token
+func postEvents(s *Server, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	r.Header.Set(adminTokenHeader, "secret")
+	return serve(s, r)
+}
+
+// decodeIngest decodes a successful ingest response
+func decodeIngest(t *testing.T, w *httptest.ResponseRecorder) ingestResponse {
+	t.Helper()
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp ingestResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode ingest response: %v", err)
+	}
+	return resp
+}
+
+func TestIngestRequiresAdminToken(t *testing.T) {
+	s := newTestServer(t)
+	s.SetIngest(func(events []*model.FlightEvent) []*model.FlightEvent {
+		t.Error("events ingested without a valid admin token")
+		return events
+	})
+
+	for _, token := range []string{"", "wrong"} {
+		r := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(`[{"icao24":"abc123"}]`))
+		if token != "" {
+			r.Header.Set(adminTokenHeader, token)
+		}
+		if w := serve(s, r); w.Code != http.StatusUnauthorized {
+			t.Errorf("token %q: status = %d, want 401", token, w.Code)
+		}
+	}
+}
+
+func TestIngestAcceptsValidBatch(t *testing.T) {
+	s := newTestServer(t)
+	var got []*model.FlightEvent
+	s.SetIngest(func(events []*model.FlightEvent) []*model.FlightEvent {
+		got = events
+		return events
+	})
+
+	bodies := map[string]string{
+		"array":        `[{"icao24":"ABC123","latitude":47.4,"longitude":8.5},{"icao24":"def456"}]`,
+		"batch object": `{"events":[{"icao24":"ABC123","latitude":47.4,"longitude":8.5},{"icao24":"def456"}]}`,
+	}
+	for name, body := range bodies {
+		got = nil
+		resp := decodeIngest(t, postEvents(s, body))
+
+		if resp.Accepted != 2 || resp.Rejected != 0 || resp.Dropped != 0 || len(resp.Errors) != 0 {
+			t.Errorf("%s: response = %+v, want 2 accepted", name, resp)
+		}
+		if ids := icao24s(got); ids != "abc123,def456" {
+			t.Errorf("%s: ingested %s, want abc123,def456 with icao24 lowercased", name, ids)
+		}
+	}
+}
+
+func TestIngestReportsPartialFailures(t *testing.T) {
+	s := newTestServer(t)
+	var got []*model.FlightEvent
+	s.SetIngest(func(events []*model.FlightEvent) []*model.FlightEvent {
+		got = events
+		// The rate limits turn away the last valid event
+		return events[:len(events)-1]
+	})
+
+	body := `[
+		{"icao24":"abc123"},
+		{"icao24":"xyz"},
+		{"icao24":"def456","latitude":47.4},
+		{"icao24":"aaa111","latitude":95,"longitude":8.5},
+		null,
+		{"icao24":"bbb222"}
+	]`
+	resp := decodeIngest(t, postEvents(s, body))
+
+	if resp.Accepted != 1 || resp.Rejected != 4 || resp.Dropped != 1 {
+		t.Errorf("response = %+v, want 1 accepted, 4 rejected, 1 dropped", resp)
+	}
+	var indexes []int
+	for _, e := range resp.Errors {
+		indexes = append(indexes, e.Index)
+	}
+	if len(indexes) != 4 || indexes[0] != 1 || indexes[1] != 2 || indexes[2] != 3 || indexes[3] != 4 {
+		t.Errorf("rejected indexes = %v, want [1 2 3 4]", indexes)
+	}
+	// Only valid events reach the pipeline
+	if ids := icao24s(got); ids != "abc123,bbb222" {
+		t.Errorf("ingested %s, want abc123,bbb222", ids)
+	}
+}
+
+func TestIngestRejectsMalformedBody(t *testing.T) {
+	s := newTestServer(t)
+	s.SetIngest(acceptAll)
+
+	for _, body := range []string{"", "not json", `[{"icao24":`} {
+		if w := postEvents(s, body); w.Code != http.StatusBadRequest {
+			t.Errorf("body %q: status = %d, want 400", body, w.Code)
+		}
+	}
+}
+
+func TestIngestRejectsOversizedBody(t *testing.T) {
+	s := newTestServer(t)
+	s.SetIngest(acceptAll)
+	s.SetMaxRequestBytes(64)
+
+	body := `[{"icao24":"abc123","callsign":"` + strings.Repeat("X", 128) + `"}]`
+	w := postEvents(s, body)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestIngestAcceptsBodyWithinLimit(t *testing.T) {
+	s := newTestServer(t)
+	s.SetIngest(acceptAll)
+	s.SetMaxRequestBytes(1024)
+
+	w := postEvents(s, `[{"icao24":"abc123"}]`)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"time"
+)
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Hijack lets WebSocket upgrades take over the connection, recording the
+// request as switching protocols
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// handle registers a handler under the base path, recording per-endpoint
+// request counts and latency under the route, applying the CORS policy and
+// per-client rate limit and compressing large responses
+func (s *Server) handle(mux *http.ServeMux, route string, handler http.HandlerFunc) {
+	handler = s.clientLimitHandler(route, gzipHandler(handler))
+	mux.HandleFunc(s.path(route), s.instrument(route, s.corsHandler(handler)))
+}
+
+// instrument wraps a handler to time each request and report its final status
+func (s *Server) instrument(route string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		handler(recorder, r)
+
+		s.metrics.RecordHTTPRequest(route, recorder.status, time.Since(start).Milliseconds())
+	}
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEndpointMetricsCountedPerRoute(t *testing.T) {
+	s := newTestServer(t)
+	mux := http.NewServeMux()
+	s.SetupRoutes(mux)
+
+	for i := 0; i < 3; i++ {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/events", nil))
+	}
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/events?units=bogus", nil))
+
+	stats := s.metrics.GetEndpointStats()
+	if got := stats["/events"]; got.Requests != 4 || got.Errors != 1 {
+		t.Errorf("/events = %+v, want 4 requests and 1 error", got)
+	}
+	if got := stats["/metrics"]; got.Requests != 1 || got.Errors != 0 {
+		t.Errorf("/metrics = %+v, want 1 request and no errors", got)
+	}
+	if _, ok := stats["/health"]; ok {
+		t.Error("endpoints that were not hit should not be reported")
+	}
+}
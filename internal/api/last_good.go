@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"flight-event-throttler/internal/buffer"
+	"flight-event-throttler/internal/model"
+)
+
+// SetLastGood sets the snapshot served by /events/lastgood
+func (s *Server) SetLastGood(lastGood *buffer.LastGood) {
+	s.lastGood = lastGood
+}
+
+// handleEventsLastGood serves the buffer as of the last successful poll. The
+// data may be stale but is always a complete, consistent snapshot.
+func (s *Server) handleEventsLastGood(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	units, ok := parseUnits(r)
+	if !ok {
+		http.Error(w, "Query parameter 'units' must be 'si' or 'imperial'", http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	events, takenAt, ok := s.lastGood.Load()
+	if !ok {
+		http.Error(w, "No successful poll yet", http.StatusServiceUnavailable)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	var response interface{} = s.projectEvents(events, units)
+	if wantsEnvelope(r) {
+		response = map[string]interface{}{
+			"events":        response,
+			"snapshot_time": takenAt.Unix(),
+			"age_seconds":   time.Since(takenAt).Seconds(),
+			"timestamp":     time.Now().Unix(),
+		}
+	} else if events == nil {
+		response = []*model.FlightEvent{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error("Failed to encode last-good response: %v", err)
+		s.metrics.IncrementHTTPErrors()
+	}
+}
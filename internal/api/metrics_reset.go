@@ -0,0 +1,48 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+)
+
+// adminTokenHeader carries the shared secret for token-guarded endpoints
+const adminTokenHeader = "X-Admin-Token"
+
+// validAdminToken reports whether the request carries the configured admin
+// token. It always fails when no token is configured.
+func (s *Server) validAdminToken(r *http.Request) bool {
+	if s.adminToken == "" {
+		return false
+	}
+	provided := r.Header.Get(adminTokenHeader)
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(s.adminToken)) == 1
+}
+
+// handleMetricsReset zeroes all counters, e.g. between load-test runs, and
+// returns the snapshot taken just before the reset
+func (s *Server) handleMetricsReset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	if !s.validAdminToken(r) {
+		http.Error(w, "Invalid or missing admin token", http.StatusUnauthorized)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	snapshot := s.metrics.GetSnapshot()
+	s.metrics.Reset()
+	s.logger.Warn("Metrics reset via API")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		s.logger.Error("Failed to encode metrics reset response: %v", err)
+		s.metrics.IncrementHTTPErrors()
+	}
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"flight-event-throttler/internal/metrics"
+)
+
+func TestMetricsResetReturnsPreResetSnapshot(t *testing.T) {
+	s := newTestServer(t)
+	s.metrics.IncrementEventsReceived()
+	s.metrics.IncrementEventsReceived()
+
+	r := httptest.NewRequest(http.MethodPost, "/metrics/reset", nil)
+	r.Header.Set(adminTokenHeader, "secret")
+	w := serve(s, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var snapshot metrics.Snapshot
+	if err := json.Unmarshal(w.Body.Bytes(), &snapshot); err != nil {
+		t.Fatalf("invalid snapshot: %v", err)
+	}
+	if snapshot.EventsReceived != 2 {
+		t.Errorf("snapshot events_received = %d, want 2", snapshot.EventsReceived)
+	}
+	if got := s.metrics.GetEventsReceived(); got != 0 {
+		t.Errorf("events_received after reset = %d, want 0", got)
+	}
+}
+
+func TestMetricsResetWrongToken(t *testing.T) {
+	s := newTestServer(t)
+	s.metrics.IncrementEventsReceived()
+
+	r := httptest.NewRequest(http.MethodPost, "/metrics/reset", nil)
+	r.Header.Set(adminTokenHeader, "wrong")
+	if w := serve(s, r); w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if got := s.metrics.GetEventsReceived(); got != 1 {
+		t.Errorf("events_received = %d, counters should be untouched", got)
+	}
+}
+
+func TestMetricsResetWrongMethod(t *testing.T) {
+	s := newTestServer(t)
+
+	r := httptest.NewRequest(http.MethodGet, "/metrics/reset", nil)
+	r.Header.Set(adminTokenHeader, "secret")
+	if w := serve(s, r); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
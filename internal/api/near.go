@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// defaultNearRadiusKm is the /events/near search radius when none is given
+const defaultNearRadiusKm = 50
+
+// nearEvent is an event with its distance from the /events/near point
+type nearEvent struct {
+	*model.FlightEvent
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// parseNearQuery reads lat, lon and radius_km for /events/near
+func parseNearQuery(params url.Values) (lat, lon, radiusKm float64, err error) {
+	parse := func(name string, min, max float64) (float64, error) {
+		value := params.Get(name)
+		if value == "" {
+			return 0, fmt.Errorf("%s is required", name)
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(parsed) || parsed < min || parsed > max {
+			return 0, fmt.Errorf("%s must be a number between %g and %g", name, min, max)
+		}
+		return parsed, nil
+	}
+
+	if lat, err = parse("lat", -90, 90); err != nil {
+		return 0, 0, 0, err
+	}
+	if lon, err = parse("lon", -180, 180); err != nil {
+		return 0, 0, 0, err
+	}
+
+	radiusKm = defaultNearRadiusKm
+	if value := params.Get("radius_km"); value != "" {
+		radiusKm, err = strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(radiusKm) || math.IsInf(radiusKm, 0) || radiusKm <= 0 {
+			return 0, 0, 0, fmt.Errorf("radius_km must be a positive number")
+		}
+	}
+
+	return lat, lon, radiusKm, nil
+}
+
+// handleEventsNear returns the buffered events within radius_km (default 50)
+// of lat/lon, nearest first, each with its distance_km. Events without a
+// position are excluded.
+func (s *Server) handleEventsNear(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	units, ok := parseUnits(r)
+	if !ok {
+		http.Error(w, "Query parameter 'units' must be 'si' or 'imperial'", http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	lat, lon, radiusKm, err := parseNearQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, "Invalid query: "+err.Error(), http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	var buffered []*model.FlightEvent
+	if !s.forEachEvent(func(event *model.FlightEvent) bool {
+		buffered = append(buffered, event)
+		return true
+	}) {
+		s.logger.Error("No buffer configured")
+		http.Error(w, "Buffer not available", http.StatusInternalServerError)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	// Measure after redaction so redacted positions are never matched
+	events := []nearEvent{}
+	for _, event := range s.projectEvents(buffered, units) {
+		if distance, ok := event.DistanceTo(lat, lon); ok && distance <= radiusKm {
+			events = append(events, nearEvent{FlightEvent: event, DistanceKm: distance})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].DistanceKm < events[j].DistanceKm })
+
+	response := map[string]interface{}{
+		"events":    events,
+		"count":     len(events),
+		"lat":       lat,
+		"lon":       lon,
+		"radius_km": radiusKm,
+		"timestamp": time.Now().Unix(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error("Failed to encode near events response: %v", err)
+		s.metrics.IncrementHTTPErrors()
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// handleBufferOverflowDrain reads back the events the ring buffer spilled to
+// its overflow file, streams them as NDJSON in eviction order, and truncates
+// the file. The drained events are removed from disk, so the endpoint needs
+// the admin token.
+func (s *Server) handleBufferOverflowDrain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	if !s.validAdminToken(r) {
+		http.Error(w, "Invalid or missing admin token", http.StatusUnauthorized)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	if s.ringBuffer == nil || !s.ringBuffer.HasOverflow() {
+		http.Error(w, "Buffer overflow spill is not enabled", http.StatusNotFound)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	events, err := s.ringBuffer.DrainOverflow()
+	if err != nil {
+		// Events recovered before the failure are still returned below
+		s.logger.Error("Failed to drain buffer overflow: %v", err)
+		s.metrics.IncrementHTTPErrors()
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			s.logger.Error("Failed to stream drained overflow events: %v", err)
+			s.metrics.IncrementHTTPErrors()
+			return
+		}
+	}
+
+	s.logger.Info("Drained %d events from buffer overflow", len(events))
+}
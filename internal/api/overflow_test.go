@@ -0,0 +1,64 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"flight-event-throttler/internal/buffer"
+	"flight-event-throttler/internal/metrics"
+	"flight-event-throttler/internal/model"
+	"flight-event-throttler/pkg/logger"
+)
+
+func TestBufferOverflowDrain(t *testing.T) {
+	m := metrics.NewMetrics()
+	t.Cleanup(m.Close)
+	rb, err := buffer.NewRingBufferWithOverflow[*model.FlightEvent](1, filepath.Join(t.TempDir(), "overflow.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, icao24 := range []string{"aaa111", "bbb222", "ccc333"} {
+		rb.Push(&model.FlightEvent{ICAO24: icao24})
+	}
+	s := NewServer(logger.NewWithWriter("error", io.Discard), m, rb, nil, "ring", "secret")
+
+	r := httptest.NewRequest(http.MethodPost, "/buffer/overflow/drain", nil)
+	if w := serve(s, r); w.Code != http.StatusUnauthorized {
+		t.Errorf("status without token = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/buffer/overflow/drain", nil)
+	r.Header.Set(adminTokenHeader, "secret")
+	w := serve(s, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var event model.FlightEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("line %q is not a JSON event: %v", scanner.Text(), err)
+		}
+		ids = append(ids, event.ICAO24)
+	}
+	if len(ids) != 2 || ids[0] != "aaa111" || ids[1] != "bbb222" {
+		t.Errorf("drained %v, want [aaa111 bbb222]", ids)
+	}
+}
+
+func TestBufferOverflowDrainWithoutSpill(t *testing.T) {
+	s := newTestServer(t)
+
+	r := httptest.NewRequest(http.MethodPost, "/buffer/overflow/drain", nil)
+	r.Header.Set(adminTokenHeader, "secret")
+	if w := serve(s, r); w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
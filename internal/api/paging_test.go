@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+// pageEnvelope is the enveloped /events response
+type pageEnvelope struct {
+	Events []*model.FlightEvent `json:"events"`
+	Total  int                  `json:"total"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+}
+
+// pagingServer buffers n events with addresses e00000, e00001, ...
+func pagingServer(t *testing.T, n int) *Server {
+	t.Helper()
+
+	events := make([]*model.FlightEvent, n)
+	for i := range events {
+		events[i] = &model.FlightEvent{ICAO24: fmt.Sprintf("e%05d", i)}
+	}
+	return newTestServer(t, events...)
+}
+
+func getPage(t *testing.T, s *Server, path string) pageEnvelope {
+	t.Helper()
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, path, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s status = %d: %s", path, w.Code, w.Body.String())
+	}
+	var page pageEnvelope
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("GET %s returned invalid JSON: %v", path, err)
+	}
+	return page
+}
+
+func TestEventsPaging(t *testing.T) {
+	s := pagingServer(t, 1200)
+	s.SetMaxEventsLimit(800)
+
+	tests := []struct {
+		name          string
+		query         string
+		limit, offset int
+		count         int
+		first         string
+	}{
+		{"default", "", 500, 0, 500, "e00000"},
+		{"explicit page", "?limit=100&offset=250", 100, 250, 100, "e00250"},
+		{"last partial page", "?limit=500&offset=1000", 500, 1000, 200, "e01000"},
+		{"clamped to max", "?limit=5000", 800, 0, 800, "e00000"},
+		{"past the end", "?offset=1200", 500, 1200, 0, ""},
+		{"far past the end", "?limit=10&offset=99999", 10, 99999, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := getPage(t, s, "/events"+tt.query)
+			if page.Total != 1200 || page.Limit != tt.limit || page.Offset != tt.offset {
+				t.Errorf("total/limit/offset = %d/%d/%d, want 1200/%d/%d", page.Total, page.Limit, page.Offset, tt.limit, tt.offset)
+			}
+			if len(page.Events) != tt.count {
+				t.Fatalf("returned %d events, want %d", len(page.Events), tt.count)
+			}
+			if tt.count > 0 && page.Events[0].ICAO24 != tt.first {
+				t.Errorf("first event = %s, want %s", page.Events[0].ICAO24, tt.first)
+			}
+			if page.Events == nil {
+				t.Error("events is null, want an array")
+			}
+		})
+	}
+}
+
+func TestEventsPagingTotalCountsFilteredEvents(t *testing.T) {
+	s := newTestServer(t,
+		&model.FlightEvent{ICAO24: "aaa111", OriginCountry: "Germany"},
+		&model.FlightEvent{ICAO24: "bbb222", OriginCountry: "France"},
+		&model.FlightEvent{ICAO24: "ccc333", OriginCountry: "Germany"},
+	)
+
+	page := getPage(t, s, "/events?origin_country=Germany&limit=1&offset=1")
+	if page.Total != 2 || len(page.Events) != 1 || page.Events[0].ICAO24 != "ccc333" {
+		t.Errorf("page = total %d, events [%s], want total 2, events [ccc333]", page.Total, icao24s(page.Events))
+	}
+}
+
+func TestEventsPagingRejectsInvalidValues(t *testing.T) {
+	s := pagingServer(t, 10)
+
+	for _, query := range []string{"limit=0", "limit=-5", "limit=ten", "offset=-1", "offset=1.5"} {
+		w := serve(s, httptest.NewRequest(http.MethodGet, "/events?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GET /events?%s status = %d, want 400", query, w.Code)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestMetricsPrometheusExposition(t *testing.T) {
+	s := newTestServer(t)
+	s.metrics.IncrementEventsReceived()
+	s.metrics.RecordAPILatency(120)
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/metrics/prometheus", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", got)
+	}
+	body := w.Body.String()
+
+	for _, name := range []string{
+		"events_received_total", "events_processed_total", "events_dropped_total", "events_failed_total",
+		"buffer_size", "buffer_capacity", "buffer_utilization_percent",
+	} {
+		pattern := regexp.MustCompile(`(?m)^# HELP flight_throttler_` + name + ` .+\n# TYPE flight_throttler_` + name + ` (counter|gauge)\nflight_throttler_` + name + ` [0-9.e+-]+$`)
+		if !pattern.MatchString(body) {
+			t.Errorf("metric %s missing or malformed", name)
+		}
+	}
+
+	if !regexp.MustCompile(`(?m)^flight_throttler_events_received_total 1$`).MatchString(body) {
+		t.Error("events_received_total should report the recorded event")
+	}
+	for _, pattern := range []string{
+		`(?m)^# TYPE flight_throttler_api_latency_ms histogram$`,
+		`(?m)^flight_throttler_api_latency_ms_bucket\{le="\+Inf"\} 1$`,
+		`(?m)^flight_throttler_api_latency_ms_count 1$`,
+	} {
+		if !regexp.MustCompile(pattern).MatchString(body) {
+			t.Errorf("output does not match %s", pattern)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"flight-event-throttler/internal/config"
+	"flight-event-throttler/internal/model"
+	"flight-event-throttler/internal/redaction"
+)
+
+// redactedServer serves a military and a civilian aircraft over the same
+// area, with positions and callsigns of the military address block redacted
+func redactedServer(t *testing.T) (*Server, *model.FlightEvent) {
+	t.Helper()
+
+	military := &model.FlightEvent{ICAO24: "ae1234", Callsign: "RCH123", OriginCountry: "United States", Latitude: floatPtr(49.44), Longitude: floatPtr(7.6)}
+	civilian := &model.FlightEvent{ICAO24: "3c6444", Callsign: "DLH4AB", OriginCountry: "Germany", Latitude: floatPtr(49.5), Longitude: floatPtr(7.7)}
+	s := newTestServer(t, military, civilian)
+
+	policy, err := redaction.NewPolicy([]redaction.Rule{{
+		ICAO24Ranges: []string{"ae0000-afffff"},
+		Fields:       []string{"latitude", "longitude", "callsign"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetRedactionPolicy(policy)
+	return s, military
+}
+
+func TestEventsServesRedactedEvents(t *testing.T) {
+	s, military := redactedServer(t)
+
+	events := getEvents(t, s, "/events?envelope=false")
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	for _, event := range events {
+		redacted := event.Latitude == nil && event.Longitude == nil && event.Callsign == ""
+		if event.ICAO24 == "ae1234" && !redacted {
+			t.Errorf("military event served unredacted: %+v", event)
+		}
+		if event.ICAO24 == "3c6444" && redacted {
+			t.Errorf("civilian event was redacted: %+v", event)
+		}
+	}
+
+	// Redaction applies to a copy; the buffer keeps the full event
+	if military.Latitude == nil || military.Callsign != "RCH123" {
+		t.Errorf("buffered event was modified: %+v", military)
+	}
+}
+
+func TestEventsFiltersApplyAfterRedaction(t *testing.T) {
+	s, _ := redactedServer(t)
+
+	// The redacted position must not place the aircraft inside the box
+	events := getEvents(t, s, "/events?envelope=false&lamin=49&lomin=7&lamax=50&lomax=8")
+	if got := icao24s(events); got != "3c6444" {
+		t.Errorf("events inside the box = %s, want only 3c6444", got)
+	}
+
+	// Filtering by address still finds the aircraft, redacted
+	events = getEvents(t, s, "/events?envelope=false&icao24=ae1234")
+	if len(events) != 1 || events[0].Latitude != nil || events[0].Callsign != "" {
+		t.Errorf("events for ae1234 = %+v, want one redacted event", events)
+	}
+}
+
+func TestConfigShowsRedactionRules(t *testing.T) {
+	s, _ := redactedServer(t)
+	cfg := testConfig()
+	cfg.Redaction.Rules = []config.RedactionRule{{
+		ICAO24Ranges: []string{"ae0000-afffff"},
+		Fields:       []string{"latitude", "longitude", "callsign"},
+	}}
+	s.SetConfigSource(func() *config.Config { return cfg })
+
+	// Rules are not secrets, so /config reports them as configured
+	var document struct {
+		Redaction struct {
+			Rules []struct {
+				ICAO24Ranges []string `json:"icao24_ranges"`
+				Fields       []string `json:"fields"`
+			} `json:"rules"`
+		} `json:"redaction"`
+	}
+	if err := json.Unmarshal(getConfig(t, s), &document); err != nil {
+		t.Fatalf("decode /config: %v", err)
+	}
+	rules := document.Redaction.Rules
+	if len(rules) != 1 || len(rules[0].ICAO24Ranges) != 1 || rules[0].ICAO24Ranges[0] != "ae0000-afffff" || len(rules[0].Fields) != 3 {
+		t.Errorf("/config redaction rules = %+v, want the configured rule", rules)
+	}
+
+	// Serving /config leaves the policy in effect for events
+	events := getEvents(t, s, "/events?envelope=false&icao24=ae1234")
+	if len(events) != 1 || events[0].Latitude != nil {
+		t.Errorf("events after /config = %+v, want ae1234 still redacted", events)
+	}
+}
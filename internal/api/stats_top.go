@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+const (
+	defaultStatsTopN = 10
+	maxStatsTopN     = 1000
+)
+
+// statsTopKeys maps the allowed `by` values to the event field counted.
+// Events with an empty value are not counted.
+var statsTopKeys = map[string]func(event *model.FlightEvent) string{
+	"country":  func(event *model.FlightEvent) string { return event.OriginCountry },
+	"callsign": func(event *model.FlightEvent) string { return strings.TrimSpace(event.Callsign) },
+}
+
+// statsTopEntry is one row of the leaderboard
+type statsTopEntry struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// handleStatsTop returns the N origin countries or callsigns with the most
+// buffered events, busiest first. Ties are ordered by name.
+func (s *Server) handleStatsTop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	by := r.URL.Query().Get("by")
+	keyFn, ok := statsTopKeys[by]
+	if !ok {
+		http.Error(w, "Query parameter 'by' must be 'country' or 'callsign'", http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	n := defaultStatsTopN
+	if nStr := r.URL.Query().Get("n"); nStr != "" {
+		parsed, err := parsePositiveInt(nStr)
+		if err != nil {
+			http.Error(w, "Query parameter 'n' must be a positive integer", http.StatusBadRequest)
+			s.metrics.IncrementHTTPErrors()
+			return
+		}
+		n = min(parsed, maxStatsTopN)
+	}
+
+	// Count redacted events so hidden values never show up in the leaderboard
+	counts := make(map[string]int)
+	found := s.forEachEvent(func(event *model.FlightEvent) bool {
+		if event == nil {
+			return true
+		}
+		if key := keyFn(s.redaction.Apply(event)); key != "" {
+			counts[key]++
+		}
+		return true
+	})
+	if !found {
+		s.logger.Error("No buffer configured")
+		http.Error(w, "Buffer not available", http.StatusInternalServerError)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	entries := make([]statsTopEntry, 0, len(counts))
+	for key, count := range counts {
+		entries = append(entries, statsTopEntry{Key: key, Count: count})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Key < entries[j].Key
+	})
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+
+	response := map[string]interface{}{
+		"entries":   entries,
+		"by":        by,
+		"n":         n,
+		"timestamp": time.Now().Unix(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error("Failed to encode top stats response: %v", err)
+		s.metrics.IncrementHTTPErrors()
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+// getStatsTop requests /stats/top and returns its entries as "key:count"
+func getStatsTop(t *testing.T, s *Server, query string) ([]string, int) {
+	t.Helper()
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/stats/top?"+query, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /stats/top?%s status = %d: %s", query, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Entries []statsTopEntry `json:"entries"`
+		N       int             `json:"n"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	entries := make([]string, len(response.Entries))
+	for i, entry := range response.Entries {
+		entries[i] = entry.Key + ":" + strconv.Itoa(entry.Count)
+	}
+	return entries, response.N
+}
+
+func statsFixture(t *testing.T) *Server {
+	t.Helper()
+
+	var events []*model.FlightEvent
+	add := func(country, callsign string, n int) {
+		for i := 0; i < n; i++ {
+			events = append(events, &model.FlightEvent{ICAO24: "abc123", OriginCountry: country, Callsign: callsign})
+		}
+	}
+	add("Germany", "DLH400 ", 4)
+	add("France", "AFR100", 2)
+	add("Austria", "AUA200", 2)
+	add("Spain", "", 3)
+	add("", "SWR300", 1)
+	return newTestServer(t, events...)
+}
+
+func TestStatsTopOrdering(t *testing.T) {
+	s := statsFixture(t)
+
+	tests := []struct {
+		query string
+		want  []string
+		n     int
+	}{
+		// Equal counts are ordered by name; empty values are skipped
+		{"by=country", []string{"Germany:4", "Spain:3", "Austria:2", "France:2"}, 10},
+		{"by=callsign", []string{"DLH400:4", "AFR100:2", "AUA200:2", "SWR300:1"}, 10},
+		{"by=country&n=3", []string{"Germany:4", "Spain:3", "Austria:2"}, 3},
+		{"by=country&n=1", []string{"Germany:4"}, 1},
+		{"by=country&n=999999", []string{"Germany:4", "Spain:3", "Austria:2", "France:2"}, maxStatsTopN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, n := getStatsTop(t, s, tt.query)
+			if len(got) != len(tt.want) {
+				t.Fatalf("entries = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("entries = %v, want %v", got, tt.want)
+				}
+			}
+			if n != tt.n {
+				t.Errorf("n = %d, want %d", n, tt.n)
+			}
+		})
+	}
+}
+
+func TestStatsTopRejectsBadParameters(t *testing.T) {
+	s := statsFixture(t)
+
+	for _, query := range []string{"", "by=aircraft", "by=country&n=0", "by=country&n=-1", "by=country&n=lots"} {
+		w := serve(s, httptest.NewRequest(http.MethodGet, "/stats/top?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GET /stats/top?%s status = %d, want 400", query, w.Code)
+		}
+	}
+}
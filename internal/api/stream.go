@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// sseHeartbeatInterval is how often an idle event stream sends a comment line
+// so proxies don't close the connection
+const sseHeartbeatInterval = 15 * time.Second
+
+// SetEventHub sets the hub that /events/stream subscribes to
+func (s *Server) SetEventHub(hub *EventHub) {
+	s.hub = hub
+}
+
+// handleEventsStream streams processed events as Server-Sent Events, one JSON
+// event per data frame, until the client disconnects or the hub stops. It
+// takes the same filters as /events; the hub only forwards matching events.
+func (s *Server) handleEventsStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	units, ok := parseUnits(r)
+	if !ok {
+		http.Error(w, "Query parameter 'units' must be 'si' or 'imperial'", http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	query, err := parseEventQuery(r)
+	if err != nil {
+		http.Error(w, "Invalid filter: "+err.Error(), http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	if s.hub == nil {
+		http.Error(w, "Event stream not available", http.StatusServiceUnavailable)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	events, unsubscribe := s.hub.Subscribe(s.servedMatch(query.match))
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// The stream outlives the server's write timeout, so lift it for this
+	// connection; writers without deadline support simply keep none
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{})
+	if err := rc.Flush(); err != nil {
+		s.logger.Error("Event stream not supported: %v", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(s.projectEvents([]*model.FlightEvent{event}, units)[0])
+			if err != nil {
+				s.logger.Error("Failed to encode streamed event: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// servedMatch adapts a filter to see events as they are served, after
+// redaction, so redacted values can't be probed through stream filters
+func (s *Server) servedMatch(match EventPredicate) EventPredicate {
+	return func(event *model.FlightEvent) bool {
+		return match(s.redaction.Apply(event))
+	}
+}
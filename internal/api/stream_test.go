@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// liveServer serves s over HTTP with a running hub fed by the returned
+// channel; closing the channel stops the hub
+func liveServer(t *testing.T, s *Server) (*httptest.Server, *EventHub, chan<- *model.FlightEvent) {
+	t.Helper()
+
+	hub := NewEventHub()
+	s.SetEventHub(hub)
+	events := make(chan *model.FlightEvent)
+	go hub.Run(events)
+
+	mux := http.NewServeMux()
+	s.SetupRoutes(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, hub, events
+}
+
+// waitForSubscribers waits until the hub has n subscribers
+func waitForSubscribers(t *testing.T, hub *EventHub, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got := hub.Subscribers()
+		if got == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("hub has %d subscribers, want %d", got, n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// readFrame returns the payload of the next SSE data frame
+func readFrame(t *testing.T, lines *bufio.Scanner) string {
+	t.Helper()
+
+	for lines.Scan() {
+		if data, ok := strings.CutPrefix(lines.Text(), "data: "); ok {
+			return data
+		}
+	}
+	t.Fatalf("stream ended before the next frame: %v", lines.Err())
+	return ""
+}
+
+func TestEventsStreamSendsFrames(t *testing.T) {
+	server, hub, events := liveServer(t, newTestServer(t))
+	defer close(events)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events/stream?icao24=abc123,def456", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /events/stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	// The handler subscribes before sending headers, so nothing is missed
+	events <- &model.FlightEvent{ICAO24: "abc123", Callsign: "FIRST"}
+	events <- &model.FlightEvent{ICAO24: "zzz999", Callsign: "FILTERED"}
+	events <- &model.FlightEvent{ICAO24: "def456", Callsign: "SECOND"}
+
+	lines := bufio.NewScanner(resp.Body)
+	for _, want := range []string{"abc123", "def456"} {
+		var event model.FlightEvent
+		if err := json.Unmarshal([]byte(readFrame(t, lines)), &event); err != nil {
+			t.Fatalf("frame is not an event: %v", err)
+		}
+		if event.ICAO24 != want {
+			t.Errorf("streamed %s, want %s", event.ICAO24, want)
+		}
+	}
+
+	// Cancelling the request ends the handler, which unsubscribes
+	waitForSubscribers(t, hub, 1)
+	cancel()
+	waitForSubscribers(t, hub, 0)
+}
+
+func TestEventsStreamEndsWhenHubStops(t *testing.T) {
+	server, _, events := liveServer(t, newTestServer(t))
+
+	resp, err := http.Get(server.URL + "/events/stream")
+	if err != nil {
+		t.Fatalf("GET /events/stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	close(events)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lines := bufio.NewScanner(resp.Body)
+		for lines.Scan() {
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream kept running after the hub stopped")
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"container/heap"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+const (
+	defaultTopN = 10
+	maxTopN     = 1000
+)
+
+// topFields maps the allowed `by` values to the event field they rank on
+var topFields = map[string]func(event *model.FlightEvent) *float64{
+	"velocity": func(event *model.FlightEvent) *float64 { return event.Velocity },
+	"altitude": func(event *model.FlightEvent) *float64 { return event.BaroAltitude },
+}
+
+// rankedEvent pairs an event with the value it is ranked by
+type rankedEvent struct {
+	event *model.FlightEvent
+	value float64
+}
+
+// less orders ranked events by value, breaking ties by ICAO24 so the
+// lexicographically smaller address ranks higher
+func (a rankedEvent) less(b rankedEvent) bool {
+	if a.value != b.value {
+		return a.value < b.value
+	}
+	return a.event.ICAO24 > b.event.ICAO24
+}
+
+// rankedHeap is a min-heap holding the current top-N candidates
+type rankedHeap []rankedEvent
+
+func (h rankedHeap) Len() int            { return len(h) }
+func (h rankedHeap) Less(i, j int) bool  { return h[i].less(h[j]) }
+func (h rankedHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *rankedHeap) Push(x interface{}) { *h = append(*h, x.(rankedEvent)) }
+func (h *rankedHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// handleEventsTop returns the top-N airborne events ranked by velocity or altitude
+func (s *Server) handleEventsTop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	by := r.URL.Query().Get("by")
+	fieldFn, ok := topFields[by]
+	if !ok {
+		http.Error(w, "Query parameter 'by' must be 'velocity' or 'altitude'", http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	n := defaultTopN
+	if nStr := r.URL.Query().Get("n"); nStr != "" {
+		parsed, err := parsePositiveInt(nStr)
+		if err != nil {
+			http.Error(w, "Query parameter 'n' must be a positive integer", http.StatusBadRequest)
+			s.metrics.IncrementHTTPErrors()
+			return
+		}
+		n = parsed
+	}
+	if n > maxTopN {
+		n = maxTopN
+	}
+
+	// Keep only the best n candidates in a min-heap so the buffer is never fully sorted
+	h := make(rankedHeap, 0, n)
+	found := s.forEachEvent(func(event *model.FlightEvent) bool {
+		if event == nil || event.OnGround {
+			return true
+		}
+		// Rank after redaction so the order can't reveal redacted values
+		event = s.redaction.Apply(event)
+		value := fieldFn(event)
+		if value == nil {
+			return true
+		}
+
+		candidate := rankedEvent{event: event, value: *value}
+		if h.Len() < n {
+			heap.Push(&h, candidate)
+		} else if h[0].less(candidate) {
+			h[0] = candidate
+			heap.Fix(&h, 0)
+		}
+		return true
+	})
+	if !found {
+		s.logger.Error("No buffer configured")
+		http.Error(w, "Buffer not available", http.StatusInternalServerError)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	// Order the winners from highest to lowest
+	sort.Slice(h, func(i, j int) bool { return h[j].less(h[i]) })
+	events := make([]*model.FlightEvent, 0, len(h))
+	for _, ranked := range h {
+		events = append(events, ranked.event)
+	}
+
+	response := map[string]interface{}{
+		"events":    events,
+		"by":        by,
+		"n":         n,
+		"timestamp": time.Now().Unix(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error("Failed to encode top events response: %v", err)
+		s.metrics.IncrementHTTPErrors()
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+	"flight-event-throttler/internal/redaction"
+)
+
+// getTopEvents requests /events/top and returns the ICAO24s in rank order
+func getTopEvents(t *testing.T, s *Server, query string) string {
+	t.Helper()
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/events/top?"+query, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /events/top?%s status = %d: %s", query, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Events []*model.FlightEvent `json:"events"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	return icao24s(response.Events)
+}
+
+// moving returns an airborne event with the given velocity and altitude
+func moving(icao24 string, velocity, altitude float64) *model.FlightEvent {
+	return &model.FlightEvent{ICAO24: icao24, Velocity: floatPtr(velocity), BaroAltitude: floatPtr(altitude)}
+}
+
+func TestEventsTopRanksAirborneEvents(t *testing.T) {
+	grounded := moving("ddd444", 500, 0)
+	grounded.OnGround = true
+	s := newTestServer(t,
+		moving("aaa111", 200, 9000),
+		moving("bbb222", 250, 11000),
+		moving("ccc333", 250, 10000),
+		grounded,
+		&model.FlightEvent{ICAO24: "eee555"},
+	)
+
+	// Ties rank the smaller ICAO24 first
+	if got := getTopEvents(t, s, "by=velocity"); got != "bbb222,ccc333,aaa111" {
+		t.Errorf("by=velocity = %s, want bbb222,ccc333,aaa111", got)
+	}
+	if got := getTopEvents(t, s, "by=altitude&n=2"); got != "bbb222,ccc333" {
+		t.Errorf("by=altitude&n=2 = %s, want bbb222,ccc333", got)
+	}
+
+	if w := serve(s, httptest.NewRequest(http.MethodGet, "/events/top?by=callsign", nil)); w.Code != http.StatusBadRequest {
+		t.Errorf("by=callsign status = %d, want 400", w.Code)
+	}
+}
+
+func TestEventsTopRanksRedactedValues(t *testing.T) {
+	policy, err := redaction.NewPolicy([]redaction.Rule{{ICAO24Ranges: []string{"ae0000-afffff"}, Fields: []string{"velocity"}}})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+
+	// The military aircraft's real velocity must not move it, or anyone
+	// else, in the ranking
+	var results []string
+	for _, velocity := range []float64{10, 240, 900} {
+		s := newTestServer(t,
+			moving("aaa111", 200, 9000),
+			moving("ae1234", velocity, 9500),
+			moving("bbb222", 250, 11000),
+		)
+		s.SetRedactionPolicy(policy)
+		results = append(results, getTopEvents(t, s, "by=velocity"))
+	}
+
+	for _, got := range results {
+		if got != "bbb222,aaa111" {
+			t.Errorf("ranking with a redacted velocity = %s, want bbb222,aaa111", got)
+		}
+	}
+}
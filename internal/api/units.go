@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+
+	"flight-event-throttler/internal/model"
+)
+
+// Unit systems accepted by the units query parameter
+const (
+	unitsSI       = "si"
+	unitsImperial = "imperial"
+)
+
+// feetPerMinutePerMPS converts vertical rate from m/s to ft/min
+const feetPerMinutePerMPS = model.FeetPerMeter * 60
+
+// parseUnits returns the unit system requested via ?units=, defaulting to SI.
+// It reports false for unknown unit systems.
+func parseUnits(r *http.Request) (string, bool) {
+	switch units := r.URL.Query().Get("units"); units {
+	case "", unitsSI:
+		return unitsSI, true
+	case unitsImperial:
+		return unitsImperial, true
+	default:
+		return "", false
+	}
+}
+
+// projectEvents prepares buffered events for output: redaction is applied and
+// values are converted to the given unit system. Buffered events are never
+// modified; converted events are copies.
+func (s *Server) projectEvents(events []*model.FlightEvent, units string) []*model.FlightEvent {
+	events = s.redaction.ApplyAll(events)
+	if units != unitsImperial {
+		return events
+	}
+
+	converted := make([]*model.FlightEvent, len(events))
+	for i, event := range events {
+		converted[i] = toImperial(event)
+	}
+	return converted
+}
+
+// toImperial returns a copy of the event with altitudes in feet, velocity in
+// knots, and vertical rate in feet per minute. Missing values stay nil.
+func toImperial(event *model.FlightEvent) *model.FlightEvent {
+	if event == nil {
+		return nil
+	}
+
+	converted := *event
+	converted.BaroAltitude = scalePtr(event.BaroAltitude, model.FeetPerMeter)
+	converted.GeoAltitude = scalePtr(event.GeoAltitude, model.FeetPerMeter)
+	converted.Velocity = scalePtr(event.Velocity, model.KnotsPerMeterPerSecond)
+	converted.VerticalRate = scalePtr(event.VerticalRate, feetPerMinutePerMPS)
+	return &converted
+}
+
+// scalePtr returns a new pointer to the value multiplied by factor, or nil
+func scalePtr(v *float64, factor float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	scaled := *v * factor
+	return &scaled
+}
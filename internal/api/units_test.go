@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+// getEvents requests path without the envelope and decodes the event list
+func getEvents(t *testing.T, s *Server, path string) []*model.FlightEvent {
+	t.Helper()
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, path, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s status = %d, want %d: %s", path, w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var events []*model.FlightEvent
+	if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil {
+		t.Fatalf("GET %s returned invalid JSON: %v", path, err)
+	}
+	return events
+}
+
+func approxEqual(got *float64, want float64) bool {
+	return got != nil && math.Abs(*got-want) < 1e-6
+}
+
+func TestEventsImperialUnits(t *testing.T) {
+	stored := &model.FlightEvent{
+		ICAO24:       "abc123",
+		BaroAltitude: floatPtr(1000),
+		GeoAltitude:  floatPtr(1100),
+		Velocity:     floatPtr(100),
+		VerticalRate: floatPtr(5),
+	}
+	s := newTestServer(t, stored)
+
+	events := getEvents(t, s, "/events?units=imperial&envelope=false")
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	event := events[0]
+
+	if !approxEqual(event.BaroAltitude, 3280.84) {
+		t.Errorf("baro_altitude = %v ft, want 3280.84", *event.BaroAltitude)
+	}
+	if !approxEqual(event.GeoAltitude, 3608.924) {
+		t.Errorf("geo_altitude = %v ft, want 3608.924", *event.GeoAltitude)
+	}
+	if !approxEqual(event.Velocity, 194.3844) {
+		t.Errorf("velocity = %v kt, want 194.3844", *event.Velocity)
+	}
+	if !approxEqual(event.VerticalRate, 984.252) {
+		t.Errorf("vertical_rate = %v ft/min, want 984.252", *event.VerticalRate)
+	}
+
+	// Storage stays in SI
+	if *stored.BaroAltitude != 1000 || *stored.Velocity != 100 {
+		t.Error("imperial output modified the buffered event")
+	}
+}
+
+func TestEventsDefaultToSI(t *testing.T) {
+	s := newTestServer(t, &model.FlightEvent{ICAO24: "abc123", BaroAltitude: floatPtr(1000)})
+
+	events := getEvents(t, s, "/events?envelope=false")
+	if len(events) != 1 || !approxEqual(events[0].BaroAltitude, 1000) {
+		t.Errorf("events = %+v, want baro_altitude 1000 m", events)
+	}
+}
+
+func TestImperialKeepsMissingValuesNil(t *testing.T) {
+	converted := toImperial(&model.FlightEvent{ICAO24: "abc123"})
+	if converted.BaroAltitude != nil || converted.GeoAltitude != nil || converted.Velocity != nil || converted.VerticalRate != nil {
+		t.Errorf("missing values should stay nil, got %+v", converted)
+	}
+	if toImperial(nil) != nil {
+		t.Error("toImperial(nil) should return nil")
+	}
+}
+
+func TestEventsRejectsUnknownUnits(t *testing.T) {
+	s := newTestServer(t)
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/events?units=furlongs", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
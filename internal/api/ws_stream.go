@@ -0,0 +1,322 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"flight-event-throttler/internal/model"
+)
+
+// wsPingInterval is how often an open WebSocket is pinged to keep proxies
+// from closing it
+const wsPingInterval = 30 * time.Second
+
+// wsWriteTimeout bounds each frame write so a stalled client is disconnected
+// rather than holding the handler forever
+const wsWriteTimeout = 10 * time.Second
+
+// wsMaxMessageSize caps client messages; filters are small
+const wsMaxMessageSize = 64 << 10
+
+// wsUpgrader accepts any origin; handleWebSocket checks origins against the
+// CORS policy before upgrading
+var wsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// streamFilter is the JSON message a WebSocket client sends to narrow its
+// stream. Empty fields match everything; an empty object clears the filter.
+type streamFilter struct {
+	ICAO24        []string   `json:"icao24"`
+	OriginCountry string     `json:"origin_country"`
+	OnGround      *bool      `json:"on_ground"`
+	BoundingBox   *filterBox `json:"bbox"`
+}
+
+// eventFilter is a validated streamFilter. A nil filter matches every event.
+type eventFilter struct {
+	icao24   map[string]struct{}
+	country  string
+	onGround *bool
+	box      *filterBox
+}
+
+// parseStreamFilter decodes and validates a filter message
+func parseStreamFilter(data []byte) (*eventFilter, error) {
+	var msg streamFilter
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	filter := &eventFilter{country: msg.OriginCountry, onGround: msg.OnGround, box: msg.BoundingBox}
+	if box := msg.BoundingBox; box != nil {
+		if err := box.validate(); err != nil {
+			return nil, fmt.Errorf("invalid filter: bbox %w", err)
+		}
+	}
+	if len(msg.ICAO24) > 0 {
+		filter.icao24 = make(map[string]struct{}, len(msg.ICAO24))
+		for _, icao24 := range msg.ICAO24 {
+			filter.icao24[strings.ToLower(strings.TrimSpace(icao24))] = struct{}{}
+		}
+	}
+
+	if filter.icao24 == nil && filter.country == "" && filter.onGround == nil && filter.box == nil {
+		return nil, nil
+	}
+	return filter, nil
+}
+
+// match reports whether the event passes the filter. Events without a
+// position never match a bounding box.
+func (f *eventFilter) match(event *model.FlightEvent) bool {
+	if f == nil {
+		return true
+	}
+	if event == nil {
+		return false
+	}
+
+	if f.icao24 != nil {
+		if _, ok := f.icao24[strings.ToLower(event.ICAO24)]; !ok {
+			return false
+		}
+	}
+	if f.country != "" && event.OriginCountry != f.country {
+		return false
+	}
+	if f.onGround != nil && event.OnGround != *f.onGround {
+		return false
+	}
+
+	return f.box == nil || f.box.contains(event)
+}
+
+// wsIncoming is a filter message handed from the read loop to the write
+// loop, or the error to report for an invalid one
+type wsIncoming struct {
+	filter    *eventFilter
+	filterErr error
+}
+
+// handleWebSocket upgrades to a WebSocket that first sends the buffered
+// events and then streams processed events, one JSON text frame per event.
+// Clients narrow their own stream by sending a filter message such as
+// {"icao24": ["abc123"], "origin_country": "Germany", "on_ground": false,
+// "bbox": {"lamin": 45, "lomin": 5, "lamax": 48, "lomax": 10}}. The hub
+// applies the filter from its next broadcast; events it already queued for
+// the connection are still sent. When a CORS policy is set, browsers from
+// other origins are refused, since CORS itself doesn't cover WebSockets.
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.metrics.IncrementHTTPRequests()
+
+	units, ok := parseUnits(r)
+	if !ok {
+		http.Error(w, "Query parameter 'units' must be 'si' or 'imperial'", http.StatusBadRequest)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	if s.hub == nil {
+		http.Error(w, "Event stream not available", http.StatusServiceUnavailable)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	if origin := r.Header.Get("Origin"); origin != "" && s.corsEnabled() && s.allowOrigin(origin) == "" {
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+
+	// The upgrader writes the HTTP error response itself on failure
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.Debug("WebSocket upgrade failed: %v", err)
+		s.metrics.IncrementHTTPErrors()
+		return
+	}
+	defer conn.Close()
+
+	// Lift the server's read timeout for the life of the socket; writes get
+	// their own deadline each
+	conn.SetReadDeadline(time.Time{})
+	conn.SetReadLimit(wsMaxMessageSize)
+
+	// Subscribe before reading the buffer so no event falls between the two.
+	// Sending the buffered events can take a while for a slow client, so
+	// live events are held from the start instead of overflowing the
+	// subscription, which the hub would count as drops.
+	events, unsubscribe := s.hub.Subscribe(nil)
+	defer unsubscribe()
+	release := holdEvents(events)
+
+	done := make(chan struct{})
+	defer close(done)
+	incoming := make(chan wsIncoming)
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		s.readWebSocket(conn, incoming, done)
+	}()
+
+	send := func(event *model.FlightEvent) bool {
+		data, err := json.Marshal(s.projectEvents([]*model.FlightEvent{event}, units)[0])
+		if err != nil {
+			s.logger.Error("Failed to encode WebSocket event: %v", err)
+			return true
+		}
+		return wsWrite(conn, websocket.TextMessage, data) == nil
+	}
+
+	// Buffered events first, so clients start from the current picture,
+	// then the held events. Events broadcast after subscribing may already
+	// be in the buffer; those are not sent twice.
+	var backlog []*model.FlightEvent
+	s.forEachEvent(func(event *model.FlightEvent) bool {
+		backlog = append(backlog, event)
+		return true
+	})
+	sent := true
+	for _, event := range backlog {
+		if sent = send(event); !sent {
+			break
+		}
+	}
+	held, open := release()
+	if !sent {
+		return
+	}
+
+	inBacklog := make(map[*model.FlightEvent]struct{}, len(backlog))
+	for _, event := range backlog {
+		inBacklog[event] = struct{}{}
+	}
+	for _, event := range held {
+		if _, ok := inBacklog[event]; ok {
+			continue
+		}
+		if !send(event) {
+			return
+		}
+	}
+	if !open {
+		wsClose(conn, websocket.CloseGoingAway, "server shutting down")
+		return
+	}
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-readerDone:
+			return
+		case msg := <-incoming:
+			switch {
+			case msg.filterErr != nil:
+				data, _ := json.Marshal(map[string]string{"error": msg.filterErr.Error()})
+				if wsWrite(conn, websocket.TextMessage, data) != nil {
+					return
+				}
+			case msg.filter == nil:
+				s.hub.SetFilter(events, nil)
+			default:
+				s.hub.SetFilter(events, s.servedMatch(msg.filter.match))
+			}
+		case event, ok := <-events:
+			if !ok {
+				wsClose(conn, websocket.CloseGoingAway, "server shutting down")
+				return
+			}
+			if !send(event) {
+				return
+			}
+		case <-ping.C:
+			if conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)) != nil {
+				return
+			}
+		}
+	}
+}
+
+// holdEvents collects events from a subscription in the background until
+// the returned release is called. Release returns the held events in order
+// and whether the subscription is still open.
+func holdEvents(events <-chan *model.FlightEvent) (release func() ([]*model.FlightEvent, bool)) {
+	stop := make(chan struct{})
+	type result struct {
+		held []*model.FlightEvent
+		open bool
+	}
+	results := make(chan result, 1)
+
+	go func() {
+		var held []*model.FlightEvent
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					results <- result{held, false}
+					return
+				}
+				held = append(held, event)
+			case <-stop:
+				results <- result{held, true}
+				return
+			}
+		}
+	}()
+
+	return func() ([]*model.FlightEvent, bool) {
+		close(stop)
+		r := <-results
+		return r.held, r.open
+	}
+}
+
+// wsWrite writes a message with a deadline
+func wsWrite(conn *websocket.Conn, messageType int, data []byte) error {
+	conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return conn.WriteMessage(messageType, data)
+}
+
+// wsClose sends a close frame; the connection is closed by the caller
+func wsClose(conn *websocket.Conn, code int, reason string) {
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(wsWriteTimeout))
+}
+
+// readWebSocket reads client messages until the connection fails or closes,
+// handing filters to the write loop. Pings, pongs and the close handshake
+// are answered by the connection itself. It returns early once done is
+// closed.
+func (s *Server) readWebSocket(conn *websocket.Conn, incoming chan<- wsIncoming, done <-chan struct{}) {
+	for {
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if messageType == websocket.BinaryMessage {
+			wsClose(conn, websocket.CloseUnsupportedData, "filters must be text messages")
+			return
+		}
+
+		filter, err := parseStreamFilter(message)
+		select {
+		case incoming <- wsIncoming{filter: filter, filterErr: err}:
+		case <-done:
+			return
+		}
+	}
+}
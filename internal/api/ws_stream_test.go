@@ -0,0 +1,259 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"flight-event-throttler/internal/model"
+)
+
+// wsClient is a WebSocket client for tests
+type wsClient struct {
+	conn *websocket.Conn
+}
+
+// dialWS connects to server's /ws endpoint
+func dialWS(t *testing.T, server *httptest.Server, origin string) *wsClient {
+	t.Helper()
+
+	header := http.Header{}
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", header)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return &wsClient{conn: conn}
+}
+
+// send writes a text message
+func (c *wsClient) send(t *testing.T, message string) {
+	t.Helper()
+
+	if err := c.conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+}
+
+// read returns the next message
+func (c *wsClient) read(t *testing.T) (int, []byte) {
+	t.Helper()
+
+	messageType, payload, err := c.conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	return messageType, payload
+}
+
+// readEvent returns the ICAO24 of the next event message
+func (c *wsClient) readEvent(t *testing.T) string {
+	t.Helper()
+
+	messageType, payload := c.read(t)
+	var event model.FlightEvent
+	if messageType != websocket.TextMessage || json.Unmarshal(payload, &event) != nil || event.ICAO24 == "" {
+		t.Fatalf("got message %d %q, want an event", messageType, payload)
+	}
+	return event.ICAO24
+}
+
+// readClose waits for the server's close frame and returns its status code
+func (c *wsClient) readClose(t *testing.T) int {
+	t.Helper()
+
+	for {
+		_, _, err := c.conn.ReadMessage()
+		var closeErr *websocket.CloseError
+		if errors.As(err, &closeErr) {
+			return closeErr.Code
+		}
+		if err != nil {
+			t.Fatalf("read close: %v", err)
+		}
+	}
+}
+
+// applyFilter sends a filter and waits until it is in effect: messages are
+// handled in order, so the error for a following invalid filter means the
+// first one has been handed to the hub
+func (c *wsClient) applyFilter(t *testing.T, filter string) {
+	t.Helper()
+
+	c.send(t, filter)
+	c.send(t, "not json")
+	messageType, payload := c.read(t)
+	if messageType != websocket.TextMessage || !strings.Contains(string(payload), `"error"`) {
+		t.Fatalf("got message %d %q, want the invalid filter error", messageType, payload)
+	}
+}
+
+func TestWebSocketStreamsFilteredEvents(t *testing.T) {
+	s := newTestServer(t,
+		&model.FlightEvent{ICAO24: "buf001", OriginCountry: "Germany"},
+		&model.FlightEvent{ICAO24: "buf002", OriginCountry: "France"},
+	)
+	server, hub, events := liveServer(t, s)
+	defer close(events)
+
+	client := dialWS(t, server, "")
+	for _, want := range []string{"buf001", "buf002"} {
+		if got := client.readEvent(t); got != want {
+			t.Errorf("buffered event = %s, want %s", got, want)
+		}
+	}
+
+	client.applyFilter(t, `{"icao24": ["ABC123", "def456"], "bbox": {"lamin": 45, "lomin": 5, "lamax": 48, "lomax": 10}}`)
+
+	events <- &model.FlightEvent{ICAO24: "abc123", Latitude: floatPtr(47.4), Longitude: floatPtr(8.5)}
+	events <- &model.FlightEvent{ICAO24: "zzz999", Latitude: floatPtr(47.4), Longitude: floatPtr(8.5)}
+	events <- &model.FlightEvent{ICAO24: "def456", Latitude: floatPtr(51.5), Longitude: floatPtr(-0.1)}
+	events <- &model.FlightEvent{ICAO24: "def456", Latitude: floatPtr(46.2), Longitude: floatPtr(6.1)}
+	if got := client.readEvent(t); got != "abc123" {
+		t.Errorf("first filtered event = %s, want abc123", got)
+	}
+	if got := client.readEvent(t); got != "def456" {
+		t.Errorf("second filtered event = %s, want def456 inside the box", got)
+	}
+
+	// An empty filter restores the full stream
+	client.applyFilter(t, `{}`)
+	events <- &model.FlightEvent{ICAO24: "zzz999"}
+	if got := client.readEvent(t); got != "zzz999" {
+		t.Errorf("event after clearing the filter = %s, want zzz999", got)
+	}
+
+	// Closing completes the handshake and releases the subscription
+	client.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	if code := client.readClose(t); code != websocket.CloseNormalClosure {
+		t.Errorf("reply to close = %d, want %d", code, websocket.CloseNormalClosure)
+	}
+	waitForSubscribers(t, hub, 0)
+}
+
+func TestWebSocketFilterDoesNotCountAsDrops(t *testing.T) {
+	s := newTestServer(t)
+	server, hub, events := liveServer(t, s)
+	defer close(events)
+	dropped := 0
+	hub.OnDropped(func() { dropped++ })
+
+	client := dialWS(t, server, "")
+	client.applyFilter(t, `{"icao24": ["abc123"]}`)
+
+	// Far more filtered-out events than the subscriber buffer holds
+	for i := 0; i < 2*subscriberBuffer; i++ {
+		events <- &model.FlightEvent{ICAO24: fmt.Sprintf("x%05d", i)}
+	}
+	events <- &model.FlightEvent{ICAO24: "abc123"}
+	if got := client.readEvent(t); got != "abc123" {
+		t.Errorf("event = %s, want abc123", got)
+	}
+	if dropped != 0 {
+		t.Errorf("hub counted %d drops for filtered-out events", dropped)
+	}
+}
+
+func TestWebSocketOriginCheck(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.SetCORS(CORSPolicy{AllowedOrigins: []string{"https://dashboard.example.com"}}); err != nil {
+		t.Fatal(err)
+	}
+	server, _, events := liveServer(t, s)
+	defer close(events)
+
+	// Allowed origins and non-browser clients without an Origin connect
+	dialWS(t, server, "https://dashboard.example.com")
+	dialWS(t, server, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Origin", "https://evil.example.com")
+	if w := serve(s, req); w.Code != http.StatusForbidden {
+		t.Errorf("status for a disallowed origin = %d, want 403", w.Code)
+	}
+}
+
+func TestWebSocketAnyOriginWithoutCORS(t *testing.T) {
+	server, _, events := liveServer(t, newTestServer(t))
+	defer close(events)
+
+	dialWS(t, server, "https://anywhere.example.com")
+}
+
+func TestWebSocketBacklogDoesNotOverflowSubscription(t *testing.T) {
+	// A backlog large enough to fill the socket buffers, so the handler is
+	// still sending it while live events arrive
+	backlog := make([]*model.FlightEvent, 20000)
+	for i := range backlog {
+		backlog[i] = &model.FlightEvent{ICAO24: fmt.Sprintf("b%05d", i), OriginCountry: strings.Repeat("x", 200)}
+	}
+	s := newTestServer(t, backlog...)
+	server, hub, events := liveServer(t, s)
+	defer close(events)
+	dropped := 0
+	hub.OnDropped(func() { dropped++ })
+
+	client := dialWS(t, server, "")
+	waitForSubscribers(t, hub, 1)
+	// Far more live events than the subscription holds, paced like the
+	// processor's output so the handler gets scheduled in between
+	live := 4 * subscriberBuffer
+	for i := 0; i < live; i++ {
+		if i%16 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		events <- &model.FlightEvent{ICAO24: fmt.Sprintf("l%05d", i)}
+	}
+	// A live event that is also in the buffer is only sent once
+	events <- backlog[len(backlog)-1]
+	events <- &model.FlightEvent{ICAO24: "last"}
+
+	for i := range backlog {
+		if got := client.readEvent(t); got != backlog[i].ICAO24 {
+			t.Fatalf("backlog event %d = %s, want %s", i, got, backlog[i].ICAO24)
+		}
+	}
+	for i := 0; i < live; i++ {
+		if got, want := client.readEvent(t), fmt.Sprintf("l%05d", i); got != want {
+			t.Fatalf("live event %d = %s, want %s", i, got, want)
+		}
+	}
+	if got := client.readEvent(t); got != "last" {
+		t.Errorf("event after the live ones = %s, want last without a duplicate", got)
+	}
+	if dropped != 0 {
+		t.Errorf("hub counted %d drops while the backlog was sent", dropped)
+	}
+}
+
+func TestWebSocketRejectsBinaryAndOversizedMessages(t *testing.T) {
+	server, _, events := liveServer(t, newTestServer(t))
+	defer close(events)
+
+	client := dialWS(t, server, "")
+	client.conn.WriteMessage(websocket.BinaryMessage, []byte("{}"))
+	if code := client.readClose(t); code != websocket.CloseUnsupportedData {
+		t.Errorf("close code for a binary message = %d, want %d", code, websocket.CloseUnsupportedData)
+	}
+
+	client = dialWS(t, server, "")
+	client.send(t, strings.Repeat(" ", wsMaxMessageSize+1))
+	if code := client.readClose(t); code != websocket.CloseMessageTooBig {
+		t.Errorf("close code for an oversized message = %d, want %d", code, websocket.CloseMessageTooBig)
+	}
+}
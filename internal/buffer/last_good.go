@@ -0,0 +1,43 @@
+package buffer
+
+import (
+	"sync/atomic"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// LastGood holds an immutable snapshot of the buffer taken after the most
+// recent successful poll. Readers always see a complete snapshot: it is
+// replaced with a single atomic pointer swap and never modified in place.
+type LastGood struct {
+	snapshot atomic.Pointer[lastGoodSnapshot]
+}
+
+type lastGoodSnapshot struct {
+	events  []*model.FlightEvent
+	takenAt time.Time
+}
+
+// NewLastGood creates an empty last-known-good holder
+func NewLastGood() *LastGood {
+	return &LastGood{}
+}
+
+// Store replaces the snapshot. The slice must not be modified afterwards.
+func (lg *LastGood) Store(events []*model.FlightEvent, takenAt time.Time) {
+	lg.snapshot.Store(&lastGoodSnapshot{events: events, takenAt: takenAt})
+}
+
+// Load returns the current snapshot and when it was taken, or false if no
+// snapshot has been stored yet
+func (lg *LastGood) Load() ([]*model.FlightEvent, time.Time, bool) {
+	if lg == nil {
+		return nil, time.Time{}, false
+	}
+	snap := lg.snapshot.Load()
+	if snap == nil {
+		return nil, time.Time{}, false
+	}
+	return snap.events, snap.takenAt, true
+}
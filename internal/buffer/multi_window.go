@@ -0,0 +1,91 @@
+package buffer
+
+import (
+	"sync"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// MultiWindowBuffer keeps an independent sliding window per origin country,
+// so per-country event rates can be tracked in a single process. Windows are
+// created lazily on the first event from each country.
+type MultiWindowBuffer struct {
+	windows    map[string]*SlidingWindowBuffer
+	windowSize time.Duration
+	maxSize    int
+	mu         sync.RWMutex // Guards the map only; each window has its own lock
+}
+
+// NewMultiWindowBuffer creates a per-country buffer whose windows each use
+// the given window size and maximum size
+func NewMultiWindowBuffer(windowSize time.Duration, maxSize int) *MultiWindowBuffer {
+	return &MultiWindowBuffer{
+		windows:    make(map[string]*SlidingWindowBuffer),
+		windowSize: windowSize,
+		maxSize:    maxSize,
+	}
+}
+
+// Push adds an event to the window for its origin country, creating the
+// window if needed
+func (mwb *MultiWindowBuffer) Push(event *model.FlightEvent) {
+	if event == nil {
+		return
+	}
+
+	mwb.window(event.OriginCountry).Push(event)
+}
+
+// window returns the window for a country, creating it on first use
+func (mwb *MultiWindowBuffer) window(country string) *SlidingWindowBuffer {
+	mwb.mu.RLock()
+	window, ok := mwb.windows[country]
+	mwb.mu.RUnlock()
+	if ok {
+		return window
+	}
+
+	mwb.mu.Lock()
+	defer mwb.mu.Unlock()
+
+	// Another goroutine may have created it while we waited for the lock
+	if window, ok := mwb.windows[country]; ok {
+		return window
+	}
+	window = NewSlidingWindowBuffer(mwb.windowSize, mwb.maxSize)
+	mwb.windows[country] = window
+	return window
+}
+
+// CountByCountry returns the number of events in each country's window.
+// Countries whose events have all expired are omitted.
+func (mwb *MultiWindowBuffer) CountByCountry() map[string]int {
+	mwb.mu.RLock()
+	windows := make(map[string]*SlidingWindowBuffer, len(mwb.windows))
+	for country, window := range mwb.windows {
+		windows[country] = window
+	}
+	mwb.mu.RUnlock()
+
+	counts := make(map[string]int, len(windows))
+	for country, window := range windows {
+		if n := window.Count(); n > 0 {
+			counts[country] = n
+		}
+	}
+	return counts
+}
+
+// GetAllForCountry returns the events in a country's window, or nil if no
+// events from that country have been seen
+func (mwb *MultiWindowBuffer) GetAllForCountry(country string) []*model.FlightEvent {
+	mwb.mu.RLock()
+	window, ok := mwb.windows[country]
+	mwb.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	return window.GetAll()
+}
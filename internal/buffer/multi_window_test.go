@@ -0,0 +1,47 @@
+package buffer
+
+import (
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+func countryEvent(icao24, country string, age time.Duration) *model.FlightEvent {
+	return &model.FlightEvent{ICAO24: icao24, OriginCountry: country, Timestamp: time.Now().Add(-age)}
+}
+
+func TestMultiWindowCountsCountriesIndependently(t *testing.T) {
+	mwb := NewMultiWindowBuffer(time.Minute, 2)
+	mwb.Push(countryEvent("a", "Germany", time.Second))
+	mwb.Push(countryEvent("b", "Germany", time.Second))
+	mwb.Push(countryEvent("c", "Germany", time.Second)) // evicts a from Germany only
+	mwb.Push(countryEvent("d", "France", time.Second))
+	mwb.Push(nil)
+
+	counts := mwb.CountByCountry()
+	if len(counts) != 2 || counts["Germany"] != 2 || counts["France"] != 1 {
+		t.Errorf("CountByCountry = %v, want Germany:2 France:1", counts)
+	}
+	if got := icaoList(mwb.GetAllForCountry("Germany")); !equalStrings(got, []string{"b", "c"}) {
+		t.Errorf("Germany = %v, want [b c]", got)
+	}
+	if got := mwb.GetAllForCountry("Spain"); got != nil {
+		t.Errorf("unknown country = %v, want nil", got)
+	}
+}
+
+func TestMultiWindowExpiresPerCountry(t *testing.T) {
+	mwb := NewMultiWindowBuffer(time.Minute, 10)
+	mwb.Push(countryEvent("a", "Germany", 2*time.Minute))
+	mwb.Push(countryEvent("b", "France", time.Second))
+	mwb.Push(countryEvent("c", "France", 2*time.Minute))
+
+	counts := mwb.CountByCountry()
+	if _, ok := counts["Germany"]; ok {
+		t.Errorf("Germany should be omitted once its events expire, got %v", counts)
+	}
+	if counts["France"] != 1 {
+		t.Errorf("France count = %d, want 1", counts["France"])
+	}
+}
@@ -0,0 +1,74 @@
+package buffer
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrBufferClosed is returned by PopBlocking once the buffer has been closed
+// and no events remain
+var ErrBufferClosed = errors.New("buffer closed")
+
+// PopBlocking removes and returns the oldest event, waiting for one to be
+// pushed if the buffer is empty. It returns ctx.Err() if the context is
+// cancelled first, or ErrBufferClosed once the buffer is closed and drained.
+func (rb *RingBuffer[T]) PopBlocking(ctx context.Context) (T, error) {
+	var zero T
+
+	for {
+		rb.mu.Lock()
+		if rb.count > 0 {
+			event := rb.takeSlot(rb.tail)
+			rb.tail = (rb.tail + 1) % rb.size
+			rb.count--
+			rb.isFull = false
+			rb.mu.Unlock()
+			return event, nil
+		}
+		if rb.closed {
+			rb.mu.Unlock()
+			return zero, ErrBufferClosed
+		}
+		if rb.notify == nil {
+			rb.notify = make(chan struct{})
+		}
+		wake := rb.notify
+		rb.mu.Unlock()
+
+		select {
+		case <-wake:
+			// Re-check: another waiter may have taken the event
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// wakeWaiters wakes every goroutine blocked in PopBlocking. The caller must
+// hold the lock.
+func (rb *RingBuffer[T]) wakeWaiters() {
+	if rb.notify != nil {
+		close(rb.notify)
+		rb.notify = nil
+	}
+}
+
+// Close wakes all PopBlocking waiters, which return ErrBufferClosed once the
+// remaining events are drained, and closes the overflow file if there is one.
+// Events already spilled stay on disk and are recovered by a buffer reopened
+// on the same path.
+func (rb *RingBuffer[T]) Close() error {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	rb.closed = true
+	rb.wakeWaiters()
+
+	if rb.overflow == nil {
+		return nil
+	}
+
+	err := rb.overflow.Close()
+	rb.overflow = nil
+	return err
+}
@@ -0,0 +1,93 @@
+package buffer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPopBlockingWakesOnPush(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+
+	type result struct {
+		value int
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := rb.PopBlocking(context.Background())
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		t.Fatalf("PopBlocking returned %v, %v before anything was pushed", r.value, r.err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	rb.Push(42)
+	select {
+	case r := <-done:
+		if r.err != nil || r.value != 42 {
+			t.Errorf("PopBlocking = %v, %v; want 42, nil", r.value, r.err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("PopBlocking was not woken by Push")
+	}
+	if !rb.IsEmpty() {
+		t.Error("the popped event should have been removed")
+	}
+}
+
+func TestPopBlockingReturnsBufferedEventImmediately(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	rb.Push(7)
+
+	value, err := rb.PopBlocking(context.Background())
+	if err != nil || value != 7 {
+		t.Errorf("PopBlocking = %v, %v; want 7, nil", value, err)
+	}
+}
+
+func TestPopBlockingCancellation(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := rb.PopBlocking(ctx)
+		done <- err
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("PopBlocking did not return after cancellation")
+	}
+}
+
+func TestPopBlockingWokenByClose(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := rb.PopBlocking(context.Background())
+		done <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	rb.Close()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrBufferClosed) {
+			t.Errorf("err = %v, want ErrBufferClosed", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("PopBlocking was not woken by Close")
+	}
+}
@@ -1,26 +1,36 @@
 package buffer
 
 import (
+	"os"
 	"sync"
 
 	"flight-event-throttler/internal/model"
 )
 
-// RingBuffer is a circular buffer for storing flight events
-type RingBuffer struct {
-	buffer   []*model.FlightEvent
+// RingBuffer is a circular buffer holding values of any type
+type RingBuffer[T any] struct {
+	buffer   []T
 	size     int
 	head     int
 	tail     int
 	count    int
 	mu       sync.RWMutex
 	isFull   bool
+	overflow *os.File      // Optional spill file for evicted events
+	spillErr error         // First spill failure since the last drain
+	notify   chan struct{} // Closed to wake PopBlocking waiters; nil when none are waiting
+	closed   bool
+	keyFn    func(T) string // Optional identity used by PushDedup
+	slots    map[string]int // Key to buffer index of its most recent entry
 }
 
+// FlightEventRingBuffer is the ring buffer used for flight events
+type FlightEventRingBuffer = RingBuffer[*model.FlightEvent]
+
 // NewRingBuffer creates a new ring buffer with the specified size
-func NewRingBuffer(size int) *RingBuffer {
-	return &RingBuffer{
-		buffer: make([]*model.FlightEvent, size),
+func NewRingBuffer[T any](size int) *RingBuffer[T] {
+	return &RingBuffer[T]{
+		buffer: make([]T, size),
 		size:   size,
 		head:   0,
 		tail:   0,
@@ -29,13 +39,29 @@ func NewRingBuffer(size int) *RingBuffer {
 	}
 }
 
+// NewFlightEventRingBuffer creates a new flight event ring buffer with the
+// specified size. Call SetKey(FlightEventKey) to enable PushDedup.
+func NewFlightEventRingBuffer(size int) *FlightEventRingBuffer {
+	return NewRingBuffer[*model.FlightEvent](size)
+}
+
 // Push adds a new event to the buffer
 // If the buffer is full, it overwrites the oldest event
-func (rb *RingBuffer) Push(event *model.FlightEvent) {
+func (rb *RingBuffer[T]) Push(event T) {
 	rb.mu.Lock()
 	defer rb.mu.Unlock()
 
-	rb.buffer[rb.head] = event
+	rb.push(event)
+}
+
+// push adds an event to the buffer. The caller must hold the lock.
+func (rb *RingBuffer[T]) push(event T) {
+	// Spill the unread event about to be overwritten instead of losing it
+	if rb.isFull && rb.overflow != nil {
+		rb.spill(rb.buffer[rb.head])
+	}
+
+	rb.storeSlot(rb.head, event)
 	rb.head = (rb.head + 1) % rb.size
 
 	if rb.isFull {
@@ -46,80 +72,103 @@ func (rb *RingBuffer) Push(event *model.FlightEvent) {
 			rb.isFull = true
 		}
 	}
+
+	rb.wakeWaiters()
 }
 
 // Pop removes and returns the oldest event from the buffer
-func (rb *RingBuffer) Pop() *model.FlightEvent {
+func (rb *RingBuffer[T]) Pop() T {
 	rb.mu.Lock()
 	defer rb.mu.Unlock()
 
-	if rb.count == 0 && !rb.isFull {
-		return nil
+	var zero T
+	if rb.count == 0 {
+		return zero
 	}
 
-	event := rb.buffer[rb.tail]
-	rb.buffer[rb.tail] = nil
+	event := rb.takeSlot(rb.tail)
 	rb.tail = (rb.tail + 1) % rb.size
 
-	if rb.isFull {
-		rb.isFull = false
-	} else {
-		rb.count--
-	}
+	// count tracks occupancy even when full, so it is always decremented
+	rb.count--
+	rb.isFull = false
 
 	return event
 }
 
 // PopBatch removes and returns up to n events from the buffer
-func (rb *RingBuffer) PopBatch(n int) []*model.FlightEvent {
+func (rb *RingBuffer[T]) PopBatch(n int) []T {
 	rb.mu.Lock()
 	defer rb.mu.Unlock()
 
-	availableCount := rb.Count()
-	if n > availableCount {
-		n = availableCount
+	// Read count directly: calling Count() here would re-acquire the lock
+	if n > rb.count {
+		n = rb.count
 	}
 
-	if n == 0 {
+	if n <= 0 {
 		return nil
 	}
 
-	events := make([]*model.FlightEvent, 0, n)
+	events := make([]T, 0, n)
 	for i := 0; i < n; i++ {
-		if rb.count == 0 && !rb.isFull {
-			break
-		}
-
-		event := rb.buffer[rb.tail]
-		rb.buffer[rb.tail] = nil
+		event := rb.takeSlot(rb.tail)
 		rb.tail = (rb.tail + 1) % rb.size
+		events = append(events, event)
+	}
 
-		if rb.isFull {
-			rb.isFull = false
-		} else {
-			rb.count--
-		}
+	rb.count -= n
+	rb.isFull = false
 
-		events = append(events, event)
+	return events
+}
+
+// PopBatchLIFO removes and returns up to n events from the buffer, newest first
+func (rb *RingBuffer[T]) PopBatchLIFO(n int) []T {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	// Derive the occupied span from the pointers: head == tail means empty unless full
+	available := (rb.head - rb.tail + rb.size) % rb.size
+	if rb.isFull {
+		available = rb.size
+	}
+	if n > available {
+		n = available
 	}
 
+	if n <= 0 {
+		return nil
+	}
+
+	events := make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		// Step head back to the most recently written slot, wrapping around
+		rb.head = (rb.head - 1 + rb.size) % rb.size
+		events = append(events, rb.takeSlot(rb.head))
+	}
+
+	rb.count = available - n
+	rb.isFull = false
+
 	return events
 }
 
 // Peek returns the oldest event without removing it
-func (rb *RingBuffer) Peek() *model.FlightEvent {
+func (rb *RingBuffer[T]) Peek() T {
 	rb.mu.RLock()
 	defer rb.mu.RUnlock()
 
-	if rb.count == 0 && !rb.isFull {
-		return nil
+	if rb.count == 0 {
+		var zero T
+		return zero
 	}
 
 	return rb.buffer[rb.tail]
 }
 
 // Count returns the number of events currently in the buffer
-func (rb *RingBuffer) Count() int {
+func (rb *RingBuffer[T]) Count() int {
 	rb.mu.RLock()
 	defer rb.mu.RUnlock()
 
@@ -130,7 +179,7 @@ func (rb *RingBuffer) Count() int {
 }
 
 // IsFull returns true if the buffer is full
-func (rb *RingBuffer) IsFull() bool {
+func (rb *RingBuffer[T]) IsFull() bool {
 	rb.mu.RLock()
 	defer rb.mu.RUnlock()
 
@@ -138,60 +187,125 @@ func (rb *RingBuffer) IsFull() bool {
 }
 
 // IsEmpty returns true if the buffer is empty
-func (rb *RingBuffer) IsEmpty() bool {
+func (rb *RingBuffer[T]) IsEmpty() bool {
 	rb.mu.RLock()
 	defer rb.mu.RUnlock()
 
-	return rb.count == 0 && !rb.isFull
+	return rb.count == 0
 }
 
 // Clear removes all events from the buffer
-func (rb *RingBuffer) Clear() {
+func (rb *RingBuffer[T]) Clear() {
 	rb.mu.Lock()
 	defer rb.mu.Unlock()
 
-	rb.buffer = make([]*model.FlightEvent, rb.size)
+	rb.buffer = make([]T, rb.size)
 	rb.head = 0
 	rb.tail = 0
 	rb.count = 0
 	rb.isFull = false
+	rb.reindex()
+
+	rb.wakeWaiters()
 }
 
-// GetAll returns all events in the buffer without removing them
-func (rb *RingBuffer) GetAll() []*model.FlightEvent {
+// GetAll returns all events in the buffer without removing them.
+// Empty (nil) slots are skipped so callers never see nil events.
+func (rb *RingBuffer[T]) GetAll() []T {
 	rb.mu.RLock()
 	defer rb.mu.RUnlock()
 
-	if rb.IsEmpty() {
+	if rb.count == 0 {
 		return nil
 	}
 
-	count := rb.Count()
-	events := make([]*model.FlightEvent, 0, count)
-
-	if rb.isFull {
-		// Buffer is full, read from tail to end, then from start to head
-		for i := rb.tail; i < rb.size; i++ {
-			events = append(events, rb.buffer[i])
-		}
-		for i := 0; i < rb.head; i++ {
-			events = append(events, rb.buffer[i])
+	all := rb.collect()
+	events := all[:0]
+	for _, event := range all {
+		if !isNil(event) {
+			events = append(events, event)
 		}
-	} else {
-		// Buffer is not full, read from tail to head
-		if rb.head > rb.tail {
-			for i := rb.tail; i < rb.head; i++ {
-				events = append(events, rb.buffer[i])
-			}
-		} else {
-			for i := rb.tail; i < rb.size; i++ {
-				events = append(events, rb.buffer[i])
-			}
-			for i := 0; i < rb.head; i++ {
-				events = append(events, rb.buffer[i])
+	}
+
+	return events
+}
+
+// Resize changes the buffer capacity without losing data. Events are kept in
+// oldest-to-newest order; when newSize is smaller than the number of buffered
+// events, the newest newSize events are kept and the oldest are dropped.
+// Non-positive sizes are ignored.
+func (rb *RingBuffer[T]) Resize(newSize int) {
+	if newSize <= 0 {
+		return
+	}
+
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	events := rb.collect()
+	if len(events) > newSize {
+		if rb.overflow != nil {
+			for _, event := range events[:len(events)-newSize] {
+				rb.spill(event)
 			}
 		}
+		events = events[len(events)-newSize:]
+	}
+
+	rb.buffer = make([]T, newSize)
+	copy(rb.buffer, events)
+	rb.size = newSize
+	rb.tail = 0
+	rb.head = len(events) % newSize
+	rb.count = len(events)
+	rb.isFull = len(events) == newSize
+	rb.reindex()
+}
+
+// collect returns the buffered events from oldest to newest. The caller must
+// hold the lock.
+func (rb *RingBuffer[T]) collect() []T {
+	// Derive the occupied span from the pointers: head == tail means empty unless full
+	n := (rb.head - rb.tail + rb.size) % rb.size
+	if rb.isFull {
+		n = rb.size
 	}
 
+	events := make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		events = append(events, rb.buffer[(rb.tail+i)%rb.size])
+	}
 	return events
 }
+
+// ForEach calls fn for each event from oldest to newest without copying the buffer.
+// Iteration stops early if fn returns false.
+func (rb *RingBuffer[T]) ForEach(fn func(event T) bool) {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+
+	// Derive the occupied span from the pointers: head == tail means empty unless full
+	n := (rb.head - rb.tail + rb.size) % rb.size
+	if rb.isFull {
+		n = rb.size
+	}
+
+	for i := 0; i < n; i++ {
+		if !fn(rb.buffer[(rb.tail+i)%rb.size]) {
+			return
+		}
+	}
+}
+
+// isNil reports whether v is a nil flight event or a nil interface value.
+// It is called for every slot GetAll copies, so it uses a type switch rather
+// than reflection; values of other types are never treated as nil.
+func isNil[T any](v T) bool {
+	switch value := any(v).(type) {
+	case nil:
+		return true
+	case *model.FlightEvent:
+		return value == nil
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package buffer
+
+import (
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRingBufferInt(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	if !rb.IsEmpty() {
+		t.Fatal("new buffer should be empty")
+	}
+
+	for i := 1; i <= 4; i++ {
+		rb.Push(i)
+	}
+
+	if !rb.IsFull() || rb.Count() != 3 {
+		t.Fatalf("count = %d, full = %v; want 3, true", rb.Count(), rb.IsFull())
+	}
+	if got := rb.GetAll(); !equalInts(got, []int{2, 3, 4}) {
+		t.Errorf("GetAll = %v, want [2 3 4]", got)
+	}
+	if got := rb.Peek(); got != 2 {
+		t.Errorf("Peek = %d, want 2", got)
+	}
+	if got := rb.Pop(); got != 2 {
+		t.Errorf("Pop = %d, want 2", got)
+	}
+	if got := rb.PopBatch(5); !equalInts(got, []int{3, 4}) {
+		t.Errorf("PopBatch = %v, want [3 4]", got)
+	}
+	if got := rb.Pop(); got != 0 {
+		t.Errorf("Pop on empty buffer = %d, want the zero value", got)
+	}
+
+	rb.Push(7)
+	rb.Clear()
+	if !rb.IsEmpty() || rb.GetAll() != nil {
+		t.Error("Clear should empty the buffer")
+	}
+}
+
+// reading is a custom element type used to show the buffer is reusable
+type reading struct {
+	Sensor string
+	Value  float64
+}
+
+func TestRingBufferCustomStruct(t *testing.T) {
+	rb := NewRingBuffer[reading](2)
+	rb.Push(reading{Sensor: "a", Value: 1})
+	rb.Push(reading{Sensor: "b", Value: 2})
+	rb.Push(reading{Sensor: "c", Value: 3})
+
+	all := rb.GetAll()
+	if len(all) != 2 || all[0].Sensor != "b" || all[1].Sensor != "c" {
+		t.Fatalf("GetAll = %+v, want b then c", all)
+	}
+
+	if got := rb.Pop(); got != (reading{Sensor: "b", Value: 2}) {
+		t.Errorf("Pop = %+v, want b", got)
+	}
+	rb.Pop()
+	if got := rb.Pop(); got != (reading{}) {
+		t.Errorf("Pop on empty buffer = %+v, want the zero value", got)
+	}
+}
+
+func TestPopBatchLIFOAfterWrapAround(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	for i := 1; i <= 6; i++ {
+		rb.Push(i)
+	}
+
+	// Buffer holds 3 4 5 6 with head wrapped past the end of the slice
+	if got := rb.PopBatchLIFO(3); !equalInts(got, []int{6, 5, 4}) {
+		t.Fatalf("PopBatchLIFO = %v, want [6 5 4]", got)
+	}
+	if rb.Count() != 1 || rb.IsFull() {
+		t.Fatalf("count = %d, full = %v; want 1, false", rb.Count(), rb.IsFull())
+	}
+
+	rb.Push(7)
+	rb.Push(8)
+	if got := rb.PopBatch(1); !equalInts(got, []int{3}) {
+		t.Errorf("PopBatch after LIFO = %v, want [3]", got)
+	}
+	if got := rb.PopBatchLIFO(10); !equalInts(got, []int{8, 7}) {
+		t.Errorf("PopBatchLIFO = %v, want [8 7]", got)
+	}
+	if !rb.IsEmpty() || rb.Count() != 0 {
+		t.Errorf("buffer should be empty, count = %d", rb.Count())
+	}
+}
+
+func TestResizeGrow(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	for i := 1; i <= 4; i++ {
+		rb.Push(i)
+	}
+
+	rb.Resize(5)
+	if rb.IsFull() || rb.Count() != 3 {
+		t.Fatalf("count = %d, full = %v; want 3, false", rb.Count(), rb.IsFull())
+	}
+	rb.Push(5)
+	rb.Push(6)
+	if got := rb.GetAll(); !equalInts(got, []int{2, 3, 4, 5, 6}) {
+		t.Errorf("GetAll = %v, want [2 3 4 5 6]", got)
+	}
+	if !rb.IsFull() {
+		t.Error("buffer should be full after filling the new capacity")
+	}
+}
+
+func TestResizeShrinkBelowCount(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	for i := 1; i <= 5; i++ {
+		rb.Push(i)
+	}
+
+	rb.Resize(2)
+	if got := rb.GetAll(); !equalInts(got, []int{4, 5}) {
+		t.Errorf("GetAll = %v, want the newest [4 5]", got)
+	}
+	if !rb.IsFull() || rb.Count() != 2 {
+		t.Errorf("count = %d, full = %v; want 2, true", rb.Count(), rb.IsFull())
+	}
+	if got := rb.Pop(); got != 4 {
+		t.Errorf("Pop = %d, want 4", got)
+	}
+}
+
+func TestResizeShrinkToCount(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	for i := 1; i <= 3; i++ {
+		rb.Push(i)
+	}
+
+	rb.Resize(3)
+	if got := rb.GetAll(); !equalInts(got, []int{1, 2, 3}) {
+		t.Errorf("GetAll = %v, want [1 2 3]", got)
+	}
+	if !rb.IsFull() || rb.Count() != 3 {
+		t.Errorf("count = %d, full = %v; want 3, true", rb.Count(), rb.IsFull())
+	}
+
+	rb.Push(4)
+	if got := rb.GetAll(); !equalInts(got, []int{2, 3, 4}) {
+		t.Errorf("GetAll after push = %v, want [2 3 4]", got)
+	}
+}
+
+func TestGetAllAfterPartialPopHasNoNils(t *testing.T) {
+	rb := NewFlightEventRingBuffer(4)
+	for _, icao24 := range []string{"a", "b", "c"} {
+		rb.Push(&model.FlightEvent{ICAO24: icao24})
+	}
+	rb.Pop()
+	rb.Pop()
+	for _, icao24 := range []string{"d", "e", "f"} {
+		rb.Push(&model.FlightEvent{ICAO24: icao24})
+	}
+
+	events := rb.GetAll()
+	for i, event := range events {
+		if event == nil {
+			t.Fatalf("GetAll returned nil at index %d", i)
+		}
+	}
+	if got := icaoList(events); !equalStrings(got, []string{"c", "d", "e", "f"}) {
+		t.Errorf("GetAll = %v, want [c d e f]", got)
+	}
+}
+
+func TestGetAllSkipsNilEvents(t *testing.T) {
+	rb := NewFlightEventRingBuffer(4)
+	rb.Push(&model.FlightEvent{ICAO24: "a"})
+	rb.Push(nil)
+	rb.Push(&model.FlightEvent{ICAO24: "b"})
+
+	if got := icaoList(rb.GetAll()); !equalStrings(got, []string{"a", "b"}) {
+		t.Errorf("GetAll = %v, want [a b]", got)
+	}
+}
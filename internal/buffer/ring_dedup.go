@@ -0,0 +1,91 @@
+package buffer
+
+import "flight-event-throttler/internal/model"
+
+// FlightEventKey identifies flight events by aircraft (ICAO24) for PushDedup
+func FlightEventKey(event *model.FlightEvent) string {
+	return event.ICAO24
+}
+
+// SetKey sets the identity used by PushDedup and rebuilds the key index for
+// the events already buffered. A nil key disables deduplication.
+func (rb *RingBuffer[T]) SetKey(keyFn func(T) string) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	rb.keyFn = keyFn
+	rb.reindex()
+}
+
+// PushDedup adds an event to the buffer, replacing the buffered entry with the
+// same key in place instead of appending a duplicate. Events with a new key are
+// pushed as usual, overwriting the oldest event when full. Without a key set
+// it behaves like Push.
+func (rb *RingBuffer[T]) PushDedup(event T) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if rb.keyFn != nil && !isNil(event) {
+		if slot, ok := rb.slots[rb.keyFn(event)]; ok {
+			rb.buffer[slot] = event
+			return
+		}
+	}
+
+	rb.push(event)
+}
+
+// storeSlot writes an event into a slot, keeping the key index in step with
+// whatever it overwrites. The caller must hold the lock.
+func (rb *RingBuffer[T]) storeSlot(slot int, event T) {
+	rb.unindexSlot(slot)
+	rb.buffer[slot] = event
+	if rb.keyFn != nil && !isNil(event) {
+		rb.slots[rb.keyFn(event)] = slot
+	}
+}
+
+// takeSlot empties a slot and returns what it held. The caller must hold the lock.
+func (rb *RingBuffer[T]) takeSlot(slot int) T {
+	var zero T
+	event := rb.buffer[slot]
+	rb.unindexSlot(slot)
+	rb.buffer[slot] = zero
+	return event
+}
+
+// unindexSlot drops the key of the event in a slot from the index, unless the
+// index already points at a newer entry. The caller must hold the lock.
+func (rb *RingBuffer[T]) unindexSlot(slot int) {
+	if rb.keyFn == nil || isNil(rb.buffer[slot]) {
+		return
+	}
+	key := rb.keyFn(rb.buffer[slot])
+	if indexed, ok := rb.slots[key]; ok && indexed == slot {
+		delete(rb.slots, key)
+	}
+}
+
+// reindex rebuilds the key index from the buffered events, oldest to newest,
+// so each key maps to its most recent entry. The caller must hold the lock.
+func (rb *RingBuffer[T]) reindex() {
+	if rb.keyFn == nil {
+		rb.slots = nil
+		return
+	}
+
+	rb.slots = make(map[string]int)
+
+	// Derive the occupied span from the pointers: head == tail means empty unless full
+	n := (rb.head - rb.tail + rb.size) % rb.size
+	if rb.isFull {
+		n = rb.size
+	}
+
+	for i := 0; i < n; i++ {
+		slot := (rb.tail + i) % rb.size
+		if !isNil(rb.buffer[slot]) {
+			rb.slots[rb.keyFn(rb.buffer[slot])] = slot
+		}
+	}
+}
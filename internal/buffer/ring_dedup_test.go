@@ -0,0 +1,54 @@
+package buffer
+
+import (
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+func newDedupBuffer(size int) *FlightEventRingBuffer {
+	rb := NewFlightEventRingBuffer(size)
+	rb.SetKey(FlightEventKey)
+	return rb
+}
+
+func TestPushDedupCountsDistinctAircraft(t *testing.T) {
+	rb := newDedupBuffer(10)
+	for i := 0; i < 5; i++ {
+		for _, icao24 := range []string{"a", "b", "c"} {
+			rb.PushDedup(&model.FlightEvent{ICAO24: icao24, Callsign: string(rune('0' + i))})
+		}
+	}
+
+	if got := rb.Count(); got != 3 {
+		t.Fatalf("Count = %d, want 3 distinct aircraft", got)
+	}
+	for _, event := range rb.GetAll() {
+		if event.Callsign != "4" {
+			t.Errorf("%s kept callsign %q, want the latest state 4", event.ICAO24, event.Callsign)
+		}
+	}
+}
+
+func TestPushDedupAfterEviction(t *testing.T) {
+	rb := newDedupBuffer(2)
+	rb.PushDedup(&model.FlightEvent{ICAO24: "a"})
+	rb.PushDedup(&model.FlightEvent{ICAO24: "b"})
+	rb.PushDedup(&model.FlightEvent{ICAO24: "c"}) // evicts a
+
+	// a was evicted, so it is appended again rather than replacing a stale slot
+	rb.PushDedup(&model.FlightEvent{ICAO24: "a"})
+	if got := icaoList(rb.GetAll()); !equalStrings(got, []string{"c", "a"}) {
+		t.Errorf("GetAll = %v, want [c a]", got)
+	}
+}
+
+func TestPushDedupWithoutKeyAppends(t *testing.T) {
+	rb := NewFlightEventRingBuffer(4)
+	rb.PushDedup(&model.FlightEvent{ICAO24: "a"})
+	rb.PushDedup(&model.FlightEvent{ICAO24: "a"})
+
+	if got := rb.Count(); got != 2 {
+		t.Errorf("Count = %d, want 2 without a key", got)
+	}
+}
@@ -0,0 +1,86 @@
+package buffer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// NewRingBufferWithOverflow creates a ring buffer that spills events to a
+// file instead of losing them: whenever Push would overwrite an unread event,
+// the evicted event is appended to overflowPath as a JSON line. Spilled events
+// are read back with DrainOverflow.
+func NewRingBufferWithOverflow[T any](size int, overflowPath string) (*RingBuffer[T], error) {
+	file, err := os.OpenFile(overflowPath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open overflow file: %w", err)
+	}
+
+	rb := NewRingBuffer[T](size)
+	rb.overflow = file
+	return rb, nil
+}
+
+// HasOverflow reports whether the buffer spills evicted events to a file
+func (rb *RingBuffer[T]) HasOverflow() bool {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+
+	return rb.overflow != nil
+}
+
+// spill appends an evicted event to the overflow file. The caller must hold
+// the lock. Only the first failure is kept; it is reported by DrainOverflow.
+func (rb *RingBuffer[T]) spill(event T) {
+	if isNil(event) {
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err == nil {
+		_, err = rb.overflow.Write(append(data, '\n'))
+	}
+	if err != nil && rb.spillErr == nil {
+		rb.spillErr = fmt.Errorf("failed to spill event to overflow file: %w", err)
+	}
+}
+
+// DrainOverflow reads back all spilled events in the order they were evicted
+// and truncates the overflow file. Any spill failure since the last drain is
+// returned alongside the events that were recovered.
+func (rb *RingBuffer[T]) DrainOverflow() ([]T, error) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if rb.overflow == nil {
+		return nil, nil
+	}
+
+	if _, err := rb.overflow.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read overflow file: %w", err)
+	}
+
+	var events []T
+	scanner := bufio.NewScanner(rb.overflow)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event T
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return events, fmt.Errorf("failed to decode overflow event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return events, fmt.Errorf("failed to read overflow file: %w", err)
+	}
+
+	if err := rb.overflow.Truncate(0); err != nil {
+		return events, fmt.Errorf("failed to truncate overflow file: %w", err)
+	}
+
+	spillErr := rb.spillErr
+	rb.spillErr = nil
+	return events, spillErr
+}
@@ -0,0 +1,54 @@
+package buffer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+func TestOverflowRecoversEvictedEventsInOrder(t *testing.T) {
+	rb, err := NewRingBufferWithOverflow[*model.FlightEvent](2, filepath.Join(t.TempDir(), "overflow.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, icao24 := range []string{"a", "b", "c", "d", "e"} {
+		rb.Push(&model.FlightEvent{ICAO24: icao24})
+	}
+
+	spilled, err := rb.DrainOverflow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := icaoList(spilled); !equalStrings(got, []string{"a", "b", "c"}) {
+		t.Errorf("spilled = %v, want [a b c]", got)
+	}
+	if got := icaoList(rb.GetAll()); !equalStrings(got, []string{"d", "e"}) {
+		t.Errorf("buffered = %v, want [d e]", got)
+	}
+
+	// Draining truncates the file
+	again, err := rb.DrainOverflow()
+	if err != nil || len(again) != 0 {
+		t.Errorf("second drain = %v, %v; want nothing", icaoList(again), err)
+	}
+
+	rb.Push(&model.FlightEvent{ICAO24: "f"})
+	spilled, _ = rb.DrainOverflow()
+	if got := icaoList(spilled); !equalStrings(got, []string{"d"}) {
+		t.Errorf("spilled after truncation = %v, want [d]", got)
+	}
+}
+
+func TestOverflowDisabledByDefault(t *testing.T) {
+	rb := NewFlightEventRingBuffer(1)
+	rb.Push(&model.FlightEvent{ICAO24: "a"})
+	rb.Push(&model.FlightEvent{ICAO24: "b"})
+
+	if rb.HasOverflow() {
+		t.Error("a plain ring buffer should not spill")
+	}
+	if spilled, err := rb.DrainOverflow(); spilled != nil || err != nil {
+		t.Errorf("DrainOverflow = %v, %v; want nil, nil", spilled, err)
+	}
+}
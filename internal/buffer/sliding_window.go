@@ -1,8 +1,10 @@
 package buffer
 
 import (
+	"sort"
 	"sync"
 	"time"
+	"unsafe"
 
 	"flight-event-throttler/internal/model"
 )
@@ -12,12 +14,47 @@ type SlidingWindowBuffer struct {
 	events        []*timestampedEvent
 	windowSize    time.Duration
 	maxSize       int
+	maxBytes      int64 // Optional memory cap; 0 disables it
+	currentBytes  int64 // Running estimate of memory held by events
 	mu            sync.RWMutex
 }
 
 type timestampedEvent struct {
 	event     *model.FlightEvent
 	timestamp time.Time
+	size      int64
+}
+
+// Fixed per-entry overhead: the event struct, its wrapper, and the slice slot
+var (
+	eventStructSize   = int64(unsafe.Sizeof(model.FlightEvent{}))
+	wrapperStructSize = int64(unsafe.Sizeof(timestampedEvent{}))
+	pointerSize       = int64(unsafe.Sizeof(uintptr(0)))
+	float64Size       = int64(unsafe.Sizeof(float64(0)))
+)
+
+// estimateEventSize approximates the memory retained by a buffered event
+func estimateEventSize(event *model.FlightEvent) int64 {
+	size := wrapperStructSize + pointerSize
+	if event == nil {
+		return size
+	}
+
+	size += eventStructSize
+	size += int64(len(event.ICAO24) + len(event.Callsign) + len(event.OriginCountry))
+	size += int64(len(event.Registration) + len(event.AircraftType))
+
+	for _, f := range []*float64{event.Longitude, event.Latitude, event.BaroAltitude, event.Velocity,
+		event.TrueTrack, event.VerticalRate, event.GeoAltitude} {
+		if f != nil {
+			size += float64Size
+		}
+	}
+	if event.Squawk != nil {
+		size += int64(len(*event.Squawk)) + int64(unsafe.Sizeof(""))
+	}
+
+	return size
 }
 
 // NewSlidingWindowBuffer creates a new sliding window buffer
@@ -29,7 +66,9 @@ func NewSlidingWindowBuffer(windowSize time.Duration, maxSize int) *SlidingWindo
 	}
 }
 
-// Push adds a new event to the buffer with current timestamp
+// Push adds a new event to the buffer, keyed by the event's own timestamp
+// or the current time if the event has none. Events are kept in timestamp
+// order regardless of the order they are pushed in.
 func (swb *SlidingWindowBuffer) Push(event *model.FlightEvent) {
 	swb.mu.Lock()
 	defer swb.mu.Unlock()
@@ -37,20 +76,89 @@ func (swb *SlidingWindowBuffer) Push(event *model.FlightEvent) {
 	// Remove expired events
 	swb.removeExpired()
 
+	timestamp := time.Now()
+	if event != nil && !event.Timestamp.IsZero() {
+		timestamp = event.Timestamp
+	}
+
 	// Add new event
 	te := &timestampedEvent{
 		event:     event,
-		timestamp: time.Now(),
+		timestamp: timestamp,
+		size:      estimateEventSize(event),
 	}
 
-	swb.events = append(swb.events, te)
+	swb.insert(te)
+	swb.currentBytes += te.size
+
+	swb.enforceLimits()
+}
 
+// insert places an entry at its position in timestamp order (must be called
+// with lock held). Event timestamps can arrive out of order, but expiry and
+// the size caps rely on the oldest entries being at the front.
+func (swb *SlidingWindowBuffer) insert(te *timestampedEvent) {
+	n := len(swb.events)
+	if n == 0 || !te.timestamp.Before(swb.events[n-1].timestamp) {
+		swb.events = append(swb.events, te)
+		return
+	}
+
+	// Insert after any entries with the same timestamp to keep arrival order
+	i := sort.Search(n, func(i int) bool {
+		return swb.events[i].timestamp.After(te.timestamp)
+	})
+	swb.events = append(swb.events, nil)
+	copy(swb.events[i+1:], swb.events[i:])
+	swb.events[i] = te
+}
+
+// enforceLimits drops the oldest events beyond the size and memory caps (must be called with lock held)
+func (swb *SlidingWindowBuffer) enforceLimits() {
 	// If we exceed max size, remove oldest events
 	if len(swb.events) > swb.maxSize {
-		swb.events = swb.events[len(swb.events)-swb.maxSize:]
+		swb.dropOldest(len(swb.events) - swb.maxSize)
+	}
+
+	// If we exceed the memory cap, remove oldest events even if still within the window
+	if swb.maxBytes > 0 {
+		n := 0
+		for bytes := swb.currentBytes; bytes > swb.maxBytes && n < len(swb.events)-1; n++ {
+			bytes -= swb.events[n].size
+		}
+		swb.dropOldest(n)
 	}
 }
 
+// SetMaxBytes sets an estimated memory cap for the buffer. When exceeded, the
+// oldest events are dropped regardless of age. A value of 0 disables the cap.
+func (swb *SlidingWindowBuffer) SetMaxBytes(maxBytes int64) {
+	swb.mu.Lock()
+	defer swb.mu.Unlock()
+
+	swb.maxBytes = maxBytes
+}
+
+// SizeBytes returns the estimated memory held by buffered events
+func (swb *SlidingWindowBuffer) SizeBytes() int64 {
+	swb.mu.RLock()
+	defer swb.mu.RUnlock()
+
+	return swb.currentBytes
+}
+
+// dropOldest removes the n oldest events and updates the size estimate (must be called with lock held)
+func (swb *SlidingWindowBuffer) dropOldest(n int) {
+	if n <= 0 {
+		return
+	}
+
+	for _, te := range swb.events[:n] {
+		swb.currentBytes -= te.size
+	}
+	swb.events = swb.events[n:]
+}
+
 // removeExpired removes events outside the time window (must be called with lock held)
 func (swb *SlidingWindowBuffer) removeExpired() {
 	if len(swb.events) == 0 {
@@ -59,20 +167,15 @@ func (swb *SlidingWindowBuffer) removeExpired() {
 
 	cutoffTime := time.Now().Add(-swb.windowSize)
 
-	// Find the first non-expired event
-	firstValid := 0
-	for i, te := range swb.events {
-		if te.timestamp.After(cutoffTime) {
-			firstValid = i
-			break
-		}
-		firstValid = i + 1
-	}
+	// insert keeps events sorted by timestamp even when they arrive out of
+	// order, so binary search for the first non-expired event instead of
+	// scanning from the front
+	firstValid := sort.Search(len(swb.events), func(i int) bool {
+		return swb.events[i].timestamp.After(cutoffTime)
+	})
 
 	// Remove expired events
-	if firstValid > 0 {
-		swb.events = swb.events[firstValid:]
-	}
+	swb.dropOldest(firstValid)
 }
 
 // GetAll returns all events within the time window
@@ -132,7 +235,7 @@ func (swb *SlidingWindowBuffer) PopBatch(n int) []*model.FlightEvent {
 	}
 
 	// Remove the popped events
-	swb.events = swb.events[n:]
+	swb.dropOldest(n)
 
 	return events
 }
@@ -146,7 +249,10 @@ func (swb *SlidingWindowBuffer) Count() int {
 	return len(swb.events)
 }
 
-// CountInLastDuration returns the number of events in a specific duration
+// CountInLastDuration returns the number of events in a specific duration.
+// Events may be pushed slightly out of order (e.g. replayed historical data),
+// so every buffered event is checked rather than stopping at the first older
+// one; the cost is O(n) in the number of buffered events.
 func (swb *SlidingWindowBuffer) CountInLastDuration(duration time.Duration) int {
 	swb.mu.RLock()
 	defer swb.mu.RUnlock()
@@ -154,11 +260,9 @@ func (swb *SlidingWindowBuffer) CountInLastDuration(duration time.Duration) int
 	cutoffTime := time.Now().Add(-duration)
 	count := 0
 
-	for i := len(swb.events) - 1; i >= 0; i-- {
-		if swb.events[i].timestamp.After(cutoffTime) {
+	for _, te := range swb.events {
+		if te.timestamp.After(cutoffTime) {
 			count++
-		} else {
-			break
 		}
 	}
 
@@ -180,6 +284,7 @@ func (swb *SlidingWindowBuffer) Clear() {
 	defer swb.mu.Unlock()
 
 	swb.events = make([]*timestampedEvent, 0, swb.maxSize)
+	swb.currentBytes = 0
 }
 
 // GetEventsInRange returns events within a specific time range
@@ -196,3 +301,18 @@ func (swb *SlidingWindowBuffer) GetEventsInRange(start, end time.Time) []*model.
 
 	return events
 }
+
+// ForEach calls fn for each event within the time window from oldest to newest
+// without copying the buffer. Iteration stops early if fn returns false.
+func (swb *SlidingWindowBuffer) ForEach(fn func(event *model.FlightEvent) bool) {
+	swb.mu.Lock()
+	defer swb.mu.Unlock()
+
+	swb.removeExpired()
+
+	for _, te := range swb.events {
+		if !fn(te.event) {
+			return
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package buffer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// snapshotEntry is the serialized form of a buffered event and the timestamp
+// it is windowed by
+type snapshotEntry struct {
+	Event     *model.FlightEvent `json:"event"`
+	Timestamp time.Time          `json:"timestamp"`
+}
+
+// Snapshot serializes the events currently in the window, oldest first, so
+// they can be restored after a restart with RestoreSnapshot
+func (swb *SlidingWindowBuffer) Snapshot() ([]byte, error) {
+	swb.mu.Lock()
+	defer swb.mu.Unlock()
+
+	swb.removeExpired()
+
+	entries := make([]snapshotEntry, 0, len(swb.events))
+	for _, te := range swb.events {
+		entries = append(entries, snapshotEntry{Event: te.event, Timestamp: te.timestamp})
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sliding window snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// RestoreSnapshot replaces the buffer contents with a snapshot taken by
+// Snapshot. Entries are ordered by timestamp, and entries that have expired
+// since the snapshot was taken are dropped. The size and memory caps apply as
+// usual, keeping the newest entries.
+func (swb *SlidingWindowBuffer) RestoreSnapshot(data []byte) error {
+	var entries []snapshotEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to decode sliding window snapshot: %w", err)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	swb.mu.Lock()
+	defer swb.mu.Unlock()
+
+	swb.events = make([]*timestampedEvent, 0, swb.maxSize)
+	swb.currentBytes = 0
+
+	for _, entry := range entries {
+		te := &timestampedEvent{
+			event:     entry.Event,
+			timestamp: entry.Timestamp,
+			size:      estimateEventSize(entry.Event),
+		}
+		swb.events = append(swb.events, te)
+		swb.currentBytes += te.size
+	}
+
+	swb.removeExpired()
+	swb.enforceLimits()
+
+	return nil
+}
+
+// gzipMagic is the header every gzip stream starts with
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// SaveSnapshotFile writes a snapshot to path atomically via a temporary file
+// and rename. The snapshot is gzip-compressed when compress is set or the path
+// ends in ".gz".
+func (swb *SlidingWindowBuffer) SaveSnapshotFile(path string, compress bool) error {
+	data, err := swb.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	if compress || strings.HasSuffix(path, ".gz") {
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		if _, err := gz.Write(data); err != nil {
+			return fmt.Errorf("failed to compress snapshot: %w", err)
+		}
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("failed to compress snapshot: %w", err)
+		}
+		data = compressed.Bytes()
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace snapshot file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSnapshotFile restores a snapshot written by SaveSnapshotFile. Compressed
+// snapshots are detected from their gzip header, so turning compression on or
+// off doesn't strand a snapshot written with the previous setting.
+func (swb *SlidingWindowBuffer) LoadSnapshotFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if bytes.HasPrefix(data, gzipMagic) {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to decompress snapshot: %w", err)
+		}
+		defer gz.Close()
+
+		if data, err = io.ReadAll(gz); err != nil {
+			return fmt.Errorf("failed to decompress snapshot: %w", err)
+		}
+	}
+
+	return swb.RestoreSnapshot(data)
+}
@@ -0,0 +1,148 @@
+package buffer
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+func TestSnapshotRestoreRoundTrip(t *testing.T) {
+	src := NewSlidingWindowBuffer(time.Minute, 10)
+	src.Push(eventAt("b", 20*time.Second))
+	src.Push(eventAt("a", 30*time.Second))
+	src.Push(eventAt("c", 10*time.Second))
+
+	data, err := src.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	dst := NewSlidingWindowBuffer(time.Minute, 10)
+	if err := dst.RestoreSnapshot(data); err != nil {
+		t.Fatalf("RestoreSnapshot: %v", err)
+	}
+	if got := icaoList(dst.GetAll()); !equalStrings(got, []string{"a", "b", "c"}) {
+		t.Errorf("restored %v, want [a b c]", got)
+	}
+}
+
+func TestRestoreSnapshotPrunesExpired(t *testing.T) {
+	now := time.Now()
+	entries := []snapshotEntry{
+		{Event: &model.FlightEvent{ICAO24: "fresh"}, Timestamp: now.Add(-10 * time.Second)},
+		{Event: &model.FlightEvent{ICAO24: "stale"}, Timestamp: now.Add(-5 * time.Minute)},
+		{Event: &model.FlightEvent{ICAO24: "newest"}, Timestamp: now.Add(-time.Second)},
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	swb := NewSlidingWindowBuffer(time.Minute, 10)
+	if err := swb.RestoreSnapshot(data); err != nil {
+		t.Fatalf("RestoreSnapshot: %v", err)
+	}
+	if got := icaoList(swb.GetAll()); !equalStrings(got, []string{"fresh", "newest"}) {
+		t.Errorf("restored %v, want [fresh newest] in timestamp order without the expired entry", got)
+	}
+}
+
+func TestRestoreSnapshotRejectsInvalidData(t *testing.T) {
+	swb := NewSlidingWindowBuffer(time.Minute, 10)
+	swb.Push(eventAt("a", time.Second))
+
+	if err := swb.RestoreSnapshot([]byte("not json")); err == nil {
+		t.Fatal("expected an error for an invalid snapshot")
+	}
+	if swb.Count() != 1 {
+		t.Errorf("a failed restore should leave the buffer untouched, count = %d", swb.Count())
+	}
+}
+
+func TestSnapshotFileCompressedRoundTrip(t *testing.T) {
+	src := NewSlidingWindowBuffer(time.Minute, 10)
+	src.Push(eventAt("a", 30*time.Second))
+	src.Push(eventAt("b", 20*time.Second))
+	src.Push(eventAt("c", 10*time.Second))
+	want := src.GetAll()
+
+	path := filepath.Join(t.TempDir(), "window.json")
+	if err := src.SaveSnapshotFile(path, true); err != nil {
+		t.Fatalf("SaveSnapshotFile: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	if !bytes.HasPrefix(data, gzipMagic) {
+		t.Fatalf("snapshot is not gzip-compressed")
+	}
+
+	dst := NewSlidingWindowBuffer(time.Minute, 10)
+	if err := dst.LoadSnapshotFile(path); err != nil {
+		t.Fatalf("LoadSnapshotFile: %v", err)
+	}
+
+	got := dst.GetAll()
+	if len(got) != len(want) {
+		t.Fatalf("restored %d events, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].ICAO24 != want[i].ICAO24 {
+			t.Errorf("event %d = %s, want %s", i, got[i].ICAO24, want[i].ICAO24)
+		}
+		if !got[i].Timestamp.Equal(want[i].Timestamp) {
+			t.Errorf("event %d timestamp = %v, want %v", i, got[i].Timestamp, want[i].Timestamp)
+		}
+	}
+}
+
+func TestSnapshotFileCompressedByExtension(t *testing.T) {
+	src := NewSlidingWindowBuffer(time.Minute, 10)
+	src.Push(eventAt("a", time.Second))
+
+	path := filepath.Join(t.TempDir(), "window.json.gz")
+	if err := src.SaveSnapshotFile(path, false); err != nil {
+		t.Fatalf("SaveSnapshotFile: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	if !bytes.HasPrefix(data, gzipMagic) {
+		t.Errorf("snapshot with a .gz path is not gzip-compressed")
+	}
+}
+
+func TestSnapshotFileUncompressed(t *testing.T) {
+	src := NewSlidingWindowBuffer(time.Minute, 10)
+	src.Push(eventAt("a", time.Second))
+
+	path := filepath.Join(t.TempDir(), "window.json")
+	if err := src.SaveSnapshotFile(path, false); err != nil {
+		t.Fatalf("SaveSnapshotFile: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("[")) {
+		t.Errorf("uncompressed snapshot should be plain JSON, got %q", data[:min(len(data), 10)])
+	}
+
+	dst := NewSlidingWindowBuffer(time.Minute, 10)
+	if err := dst.LoadSnapshotFile(path); err != nil {
+		t.Fatalf("LoadSnapshotFile: %v", err)
+	}
+	if dst.Count() != 1 {
+		t.Errorf("restored %d events, want 1", dst.Count())
+	}
+}
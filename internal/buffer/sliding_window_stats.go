@@ -0,0 +1,55 @@
+package buffer
+
+import (
+	"math"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// AltitudeStats returns the minimum, maximum, and mean barometric altitude of
+// the events in the window, along with the number of events that reported
+// one. Events without an altitude are skipped; when none report one, all
+// values are zero.
+func (swb *SlidingWindowBuffer) AltitudeStats() (min, max, mean float64, count int) {
+	return swb.fieldStats(func(event *model.FlightEvent) *float64 { return event.BaroAltitude })
+}
+
+// VelocityStats returns the minimum, maximum, and mean velocity of the events
+// in the window, along with the number of events that reported one. Events
+// without a velocity are skipped; when none report one, all values are zero.
+func (swb *SlidingWindowBuffer) VelocityStats() (min, max, mean float64, count int) {
+	return swb.fieldStats(func(event *model.FlightEvent) *float64 { return event.Velocity })
+}
+
+// fieldStats computes min, max, and mean of an optional field in a single
+// pass under the read lock, without copying events out of the window
+func (swb *SlidingWindowBuffer) fieldStats(field func(event *model.FlightEvent) *float64) (min, max, mean float64, count int) {
+	swb.mu.RLock()
+	defer swb.mu.RUnlock()
+
+	// Expired events may still be held until the next write; skip them here
+	cutoffTime := time.Now().Add(-swb.windowSize)
+
+	min, max = math.Inf(1), math.Inf(-1)
+	sum := 0.0
+	for _, te := range swb.events {
+		if te.event == nil || !te.timestamp.After(cutoffTime) {
+			continue
+		}
+		value := field(te.event)
+		if value == nil {
+			continue
+		}
+
+		min = math.Min(min, *value)
+		max = math.Max(max, *value)
+		sum += *value
+		count++
+	}
+
+	if count == 0 {
+		return 0, 0, 0, 0
+	}
+	return min, max, sum / float64(count), count
+}
@@ -0,0 +1,53 @@
+package buffer
+
+import (
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestAltitudeAndVelocityStatsSkipNil(t *testing.T) {
+	swb := NewSlidingWindowBuffer(time.Minute, 10)
+	now := time.Now()
+	swb.Push(&model.FlightEvent{ICAO24: "a", Timestamp: now, BaroAltitude: floatPtr(1000), Velocity: floatPtr(200)})
+	swb.Push(&model.FlightEvent{ICAO24: "b", Timestamp: now, BaroAltitude: floatPtr(3000)})
+	swb.Push(&model.FlightEvent{ICAO24: "c", Timestamp: now, Velocity: floatPtr(100)})
+	swb.Push(&model.FlightEvent{ICAO24: "d", Timestamp: now, BaroAltitude: floatPtr(2000)})
+	swb.Push(&model.FlightEvent{ICAO24: "e", Timestamp: now})
+
+	min, max, mean, count := swb.AltitudeStats()
+	if min != 1000 || max != 3000 || mean != 2000 || count != 3 {
+		t.Errorf("AltitudeStats = %v, %v, %v, %d; want 1000, 3000, 2000, 3", min, max, mean, count)
+	}
+
+	min, max, mean, count = swb.VelocityStats()
+	if min != 100 || max != 200 || mean != 150 || count != 2 {
+		t.Errorf("VelocityStats = %v, %v, %v, %d; want 100, 200, 150, 2", min, max, mean, count)
+	}
+}
+
+func TestStatsWithOnlyNilFields(t *testing.T) {
+	swb := NewSlidingWindowBuffer(time.Minute, 10)
+	swb.Push(&model.FlightEvent{ICAO24: "a"})
+	swb.Push(&model.FlightEvent{ICAO24: "b"})
+
+	if min, max, mean, count := swb.AltitudeStats(); count != 0 || min != 0 || max != 0 || mean != 0 {
+		t.Errorf("AltitudeStats = %v, %v, %v, %d; want all zero", min, max, mean, count)
+	}
+	if _, _, _, count := swb.VelocityStats(); count != 0 {
+		t.Errorf("VelocityStats count = %d, want 0", count)
+	}
+}
+
+func TestStatsSkipExpiredEvents(t *testing.T) {
+	swb := NewSlidingWindowBuffer(time.Minute, 10)
+	swb.Push(&model.FlightEvent{ICAO24: "old", Timestamp: time.Now().Add(-2 * time.Minute), BaroAltitude: floatPtr(9000)})
+	swb.Push(&model.FlightEvent{ICAO24: "new", Timestamp: time.Now(), BaroAltitude: floatPtr(1000)})
+
+	if _, max, _, count := swb.AltitudeStats(); count != 1 || max != 1000 {
+		t.Errorf("AltitudeStats max = %v, count = %d; want 1000, 1", max, count)
+	}
+}
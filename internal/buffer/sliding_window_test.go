@@ -0,0 +1,215 @@
+package buffer
+
+import (
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// eventAt returns an event with the given ICAO24 stamped at now minus age
+func eventAt(icao24 string, age time.Duration) *model.FlightEvent {
+	return &model.FlightEvent{ICAO24: icao24, Timestamp: time.Now().Add(-age)}
+}
+
+// icaoList returns the ICAO24 of each event in order
+func icaoList(events []*model.FlightEvent) []string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ICAO24)
+	}
+	return ids
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSlidingWindowPushKeepsTimestampOrder(t *testing.T) {
+	swb := NewSlidingWindowBuffer(time.Minute, 10)
+
+	swb.Push(eventAt("c", 10*time.Second))
+	swb.Push(eventAt("a", 30*time.Second))
+	swb.Push(eventAt("d", 5*time.Second))
+	swb.Push(eventAt("b", 20*time.Second))
+
+	got := icaoList(swb.GetAll())
+	want := []string{"a", "b", "c", "d"}
+	if !equalStrings(got, want) {
+		t.Errorf("GetAll() = %v, want %v", got, want)
+	}
+}
+
+func TestSlidingWindowEqualTimestampsKeepArrivalOrder(t *testing.T) {
+	swb := NewSlidingWindowBuffer(time.Minute, 10)
+	ts := time.Now().Add(-10 * time.Second)
+
+	swb.Push(&model.FlightEvent{ICAO24: "late", Timestamp: ts.Add(time.Second)})
+	swb.Push(&model.FlightEvent{ICAO24: "first", Timestamp: ts})
+	swb.Push(&model.FlightEvent{ICAO24: "second", Timestamp: ts})
+
+	got := icaoList(swb.GetAll())
+	want := []string{"first", "second", "late"}
+	if !equalStrings(got, want) {
+		t.Errorf("GetAll() = %v, want %v", got, want)
+	}
+}
+
+func TestSlidingWindowMaxSizeDropsOldestTimestamp(t *testing.T) {
+	swb := NewSlidingWindowBuffer(time.Minute, 2)
+
+	swb.Push(eventAt("new", 1*time.Second))
+	swb.Push(eventAt("old", 30*time.Second))
+	swb.Push(eventAt("mid", 10*time.Second))
+
+	got := icaoList(swb.GetAll())
+	want := []string{"mid", "new"}
+	if !equalStrings(got, want) {
+		t.Errorf("GetAll() = %v, want %v", got, want)
+	}
+}
+
+func TestSlidingWindowExpiresOutOfOrderEvents(t *testing.T) {
+	swb := NewSlidingWindowBuffer(time.Minute, 10)
+
+	swb.Push(eventAt("fresh1", 10*time.Second))
+	swb.Push(eventAt("fresh2", 5*time.Second))
+	// Arrives last but is already past the cutoff
+	swb.Push(eventAt("expired", 2*time.Minute))
+	// Arrives late but is still inside the window
+	swb.Push(eventAt("late", 50*time.Second))
+
+	got := icaoList(swb.GetAll())
+	want := []string{"late", "fresh1", "fresh2"}
+	if !equalStrings(got, want) {
+		t.Errorf("GetAll() = %v, want %v", got, want)
+	}
+}
+
+func BenchmarkRemoveExpired(b *testing.B) {
+	const n = 200000
+	now := time.Now()
+
+	// Half the events fall before the one-minute cutoff
+	events := make([]*timestampedEvent, n)
+	for i := range events {
+		age := 2*time.Minute - time.Duration(i)*(2*time.Minute)/n
+		events[i] = &timestampedEvent{event: &model.FlightEvent{}, timestamp: now.Add(-age)}
+	}
+
+	swb := NewSlidingWindowBuffer(time.Minute, n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		swb.events = append(swb.events[:0:0], events...)
+		b.StartTimer()
+
+		swb.removeExpired()
+	}
+}
+
+func TestCountInLastDurationOutOfOrder(t *testing.T) {
+	swb := NewSlidingWindowBuffer(time.Minute, 10)
+	for _, age := range []time.Duration{5, 40, 12, 50, 2, 30} {
+		swb.Push(eventAt("x", age*time.Second))
+	}
+
+	// 5s, 12s and 2s fall within the last 20 seconds
+	if got := swb.CountInLastDuration(20 * time.Second); got != 3 {
+		t.Errorf("CountInLastDuration(20s) = %d, want 3", got)
+	}
+	if got := swb.CountInLastDuration(time.Minute); got != 6 {
+		t.Errorf("CountInLastDuration(1m) = %d, want 6", got)
+	}
+}
+
+func TestSlidingWindowMaxBytesDropsOldestTimestamp(t *testing.T) {
+	size := estimateEventSize(eventAt("aaa111", 0))
+	swb := NewSlidingWindowBuffer(time.Minute, 100)
+	swb.SetMaxBytes(3 * size)
+
+	// Pushed newest first, so the cap must drop by timestamp, not arrival
+	for i, icao24 := range []string{"eee555", "ddd444", "ccc333", "bbb222", "aaa111"} {
+		swb.Push(eventAt(icao24, time.Duration(i+1)*time.Second))
+	}
+
+	if got, want := icaoList(swb.GetAll()), []string{"ccc333", "ddd444", "eee555"}; !equalStrings(got, want) {
+		t.Errorf("events = %v, want the newest %v", got, want)
+	}
+	if swb.SizeBytes() != 3*size {
+		t.Errorf("SizeBytes() = %d, want %d", swb.SizeBytes(), 3*size)
+	}
+}
+
+func TestSlidingWindowMaxBytesKeepsNewestEvent(t *testing.T) {
+	size := estimateEventSize(eventAt("aaa111", 0))
+	swb := NewSlidingWindowBuffer(time.Minute, 100)
+	swb.SetMaxBytes(size / 2)
+
+	swb.Push(eventAt("aaa111", 2*time.Second))
+	swb.Push(eventAt("bbb222", time.Second))
+
+	// A cap below a single event still keeps the newest one
+	if got := icaoList(swb.GetAll()); !equalStrings(got, []string{"bbb222"}) {
+		t.Errorf("events = %v, want [bbb222]", got)
+	}
+}
+
+func TestSlidingWindowMaxBytesZeroDisablesCap(t *testing.T) {
+	swb := NewSlidingWindowBuffer(time.Minute, 100)
+	swb.SetMaxBytes(1)
+	swb.SetMaxBytes(0)
+
+	for _, icao24 := range []string{"aaa111", "bbb222", "ccc333"} {
+		swb.Push(eventAt(icao24, time.Second))
+	}
+	if swb.Count() != 3 {
+		t.Errorf("Count() = %d, want 3 with the cap disabled", swb.Count())
+	}
+}
+
+func TestSlidingWindowSizeBytesTracksRemovals(t *testing.T) {
+	size := estimateEventSize(eventAt("aaa111", 0))
+	swb := NewSlidingWindowBuffer(time.Minute, 100)
+
+	swb.Push(eventAt("aaa111", 2*time.Minute))
+	swb.Push(eventAt("bbb222", 3*time.Second))
+	swb.Push(eventAt("ccc333", 2*time.Second))
+	swb.Push(eventAt("ddd444", time.Second))
+
+	// aaa111 is outside the window and expires
+	if swb.Count() != 3 || swb.SizeBytes() != 3*size {
+		t.Errorf("after expiry: Count() = %d, SizeBytes() = %d; want 3 and %d", swb.Count(), swb.SizeBytes(), 3*size)
+	}
+
+	swb.PopBatch(1)
+	if swb.SizeBytes() != 2*size {
+		t.Errorf("after PopBatch: SizeBytes() = %d, want %d", swb.SizeBytes(), 2*size)
+	}
+
+	swb.Clear()
+	if swb.SizeBytes() != 0 {
+		t.Errorf("after Clear: SizeBytes() = %d, want 0", swb.SizeBytes())
+	}
+}
+
+func TestEstimateEventSizeGrowsWithFields(t *testing.T) {
+	bare := &model.FlightEvent{ICAO24: "abc123"}
+	lat, squawk := 47.4, "7700"
+	full := &model.FlightEvent{ICAO24: "abc123", Callsign: "SWR123", Latitude: &lat, Squawk: &squawk}
+
+	if estimateEventSize(full) <= estimateEventSize(bare) {
+		t.Errorf("estimate for a full event (%d) should exceed a bare one (%d)", estimateEventSize(full), estimateEventSize(bare))
+	}
+	if estimateEventSize(nil) >= estimateEventSize(bare) {
+		t.Errorf("estimate for nil (%d) should be below a bare event (%d)", estimateEventSize(nil), estimateEventSize(bare))
+	}
+}
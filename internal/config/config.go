@@ -1,20 +1,32 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Server    ServerConfig    `yaml:"server"`
-	OpenSky   OpenSkyConfig   `yaml:"opensky"`
-	RateLimit RateLimitConfig `yaml:"rate_limit"`
-	Buffer    BufferConfig    `yaml:"buffer"`
-	Logging   LoggingConfig   `yaml:"logging"`
+	Server     ServerConfig     `yaml:"server"`
+	OpenSky    OpenSkyConfig    `yaml:"opensky"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	Buffer     BufferConfig     `yaml:"buffer"`
+	Logging    LoggingConfig    `yaml:"logging"`
+	Enrichment EnrichmentConfig `yaml:"enrichment"`
+	Shutdown   ShutdownConfig   `yaml:"shutdown"`
+	Metrics    MetricsConfig    `yaml:"metrics"`
+	Event      EventConfig      `yaml:"event"`
+	Autoscale  AutoscaleConfig  `yaml:"autoscale"`
+	Redaction  RedactionConfig  `yaml:"redaction"`
+	Alerts     AlertsConfig     `yaml:"alerts"`
+	Watchdog   WatchdogConfig   `yaml:"watchdog"`
+	Sink       SinkConfig       `yaml:"sink"`
 }
 
 type ServerConfig struct {
@@ -22,53 +34,222 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `yaml:"read_timeout"`
 	WriteTimeout time.Duration `yaml:"write_timeout"`
 	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	BasePath     string        `yaml:"base_path"` // Optional route prefix, e.g. "/throttler"
+	EnableAdmin  bool          `yaml:"enable_admin"` // Enables endpoints exposing all buffered data
+	AdminToken   string        `yaml:"admin_token"`  // Shared secret for token-guarded endpoints such as /metrics/reset
+	MaxEventsLimit int         `yaml:"max_events_limit"` // Largest page size /events will return
+	MaxRequestBytes int64      `yaml:"max_request_bytes"` // Largest request body accepted by ingest endpoints
+	CORS         CORSConfig    `yaml:"cors"`
+	ClientRateLimit ClientRateLimitConfig `yaml:"client_rate_limit"`
+	Ingest       IngestConfig  `yaml:"ingest"`
+}
+
+// IngestConfig controls accepting events pushed by external producers
+type IngestConfig struct {
+	Enabled bool `yaml:"enabled"` // Accept events pushed with POST /events; requires admin_token
+}
+
+// ClientRateLimitConfig limits how fast each client IP may call the API
+type ClientRateLimitConfig struct {
+	RequestsPerSecond int `yaml:"requests_per_second"` // Per client IP; 0 disables the limit
+	Burst             int `yaml:"burst"`
+	TrustForwarded    bool `yaml:"trust_forwarded"` // Identify clients by X-Forwarded-For; only behind a proxy that sets it
+}
+
+// CORSConfig controls which browser origins may call the API
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins"`   // Origins allowed to call the API; "*" allows any; empty disables CORS
+	AllowCredentials bool          `yaml:"allow_credentials"` // Allow cookies and auth headers; not allowed with "*"
+	MaxAge           time.Duration `yaml:"max_age"`           // How long browsers may cache a preflight
 }
 
 type OpenSkyConfig struct {
-	BaseURL       string        `yaml:"base_url"`
-	PollInterval  time.Duration `yaml:"poll_interval"`
-	RequestTimeout time.Duration `yaml:"request_timeout"`
-	Username      string        `yaml:"username"`
-	Password      string        `yaml:"password"`
+	BaseURL               string        `yaml:"base_url"`
+	PollInterval          time.Duration `yaml:"poll_interval"`
+	RequestTimeout        time.Duration `yaml:"request_timeout"`
+	Username              string        `yaml:"username"`
+	Password              string        `yaml:"password"`
+	MaxConcurrentRequests int           `yaml:"max_concurrent_requests"`
+	SkipIfBusy            bool          `yaml:"skip_if_busy"`     // Skip ticks while a fetch is still running
+	DropNullIsland        bool          `yaml:"drop_null_island"` // Treat exact (0, 0) positions as missing
+	WarmupPolls           int           `yaml:"warmup_polls"`     // Rapid polls on startup; 0 disables warmup
+	WarmupInterval        time.Duration `yaml:"warmup_interval"`
+	WarmupTargetFill      float64       `yaml:"warmup_target_fill"` // Stop warmup at this buffer utilization percent
+	RetryMaxAttempts      int           `yaml:"retry_max_attempts"` // Total attempts per fetch; 1 disables retries
+	RetryBaseDelay        time.Duration `yaml:"retry_base_delay"`   // Backoff before the first retry, doubled each retry
+	BoundingBox           *BoundingBoxConfig `yaml:"bounding_box"` // Poll only this region; nil polls the full feed
+	CacheTTL              time.Duration `yaml:"cache_ttl"` // Reuse identical state responses this long; 0 disables
+}
+
+// BoundingBoxConfig is a geographic region in decimal degrees
+type BoundingBoxConfig struct {
+	LaMin float64 `yaml:"lamin"`
+	LoMin float64 `yaml:"lomin"`
+	LaMax float64 `yaml:"lamax"`
+	LoMax float64 `yaml:"lomax"`
 }
 
 type RateLimitConfig struct {
-	EventsPerSecond int           `yaml:"events_per_second"`
-	BurstSize       int           `yaml:"burst_size"`
-	WindowDuration  time.Duration `yaml:"window_duration"`
+	Algorithm           string        `yaml:"algorithm"` // "token_bucket" or "leaky_bucket"
+	EventsPerSecond     int           `yaml:"events_per_second"`
+	BurstSize           int           `yaml:"burst_size"`
+	WindowDuration      time.Duration `yaml:"window_duration"`
+	PerAircraftInterval time.Duration `yaml:"per_aircraft_interval"` // Min interval between updates per aircraft; 0 disables
+	PerAircraftTracked  int           `yaml:"per_aircraft_tracked"`  // Max aircraft tracked by the per-aircraft throttle
+	PerAircraftRate     int           `yaml:"per_aircraft_rate"`     // Token-bucket events/sec per aircraft; 0 disables
+	PerAircraftBurst    int           `yaml:"per_aircraft_burst"`    // Burst size of each per-aircraft bucket
+	Adaptive            AdaptiveConfig `yaml:"adaptive"`
+	EmergencySquawks    []string      `yaml:"emergency_squawks"` // Squawks that bypass rate limits; empty disables
+}
+
+// AdaptiveConfig lowers the global rate limit as the buffer fills
+type AdaptiveConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	MinRate  int           `yaml:"min_rate"` // Events/sec applied at a full buffer
+	MaxRate  int           `yaml:"max_rate"` // Events/sec applied at an empty buffer
+	Interval time.Duration `yaml:"interval"` // How often utilization is checked
 }
 
 type BufferConfig struct {
-	Type       string `yaml:"type"` // "ring" or "sliding_window"
-	Size       int    `yaml:"size"`
-	BatchSize  int    `yaml:"batch_size"`
+	Type          string        `yaml:"type"` // "ring" or "sliding_window"
+	Size          int           `yaml:"size"`
+	BatchSize     int           `yaml:"batch_size"`
 	FlushInterval time.Duration `yaml:"flush_interval"`
+	MaxBytes      int64         `yaml:"max_bytes"`     // Optional sliding window memory cap; 0 disables it
+	OverflowPath  string        `yaml:"overflow_path"` // Optional ring buffer spill file for evicted events
+	Dedup         bool          `yaml:"dedup"`         // Keep only the latest event per aircraft in the ring buffer
+	SnapshotPath  string        `yaml:"snapshot_path"` // Optional sliding window snapshot saved on shutdown and restored on startup
+	PersistCompress bool        `yaml:"persist_compress"` // Gzip the snapshot file
 }
 
 type LoggingConfig struct {
-	Level string `yaml:"level"` // "DEBUG", "INFO", "ERROR"
+	Level               string        `yaml:"level"`  // "TRACE", "DEBUG", "INFO", "WARN", "ERROR" (case-insensitive)
+	Format              string        `yaml:"format"` // "text" or "json"
+	File                string        `yaml:"file"`   // Append logs to this file instead of stdout/stderr
+	DropSummaryInterval time.Duration `yaml:"drop_summary_interval"`
+}
+
+type EnrichmentConfig struct {
+	AircraftDB string `yaml:"aircraft_db"` // Optional path to an aircraft metadata CSV
+}
+
+type ShutdownConfig struct {
+	SummaryPath      string `yaml:"summary_path"`       // Optional JSON run summary written on shutdown
+	MaxDroppedEvents int64  `yaml:"max_dropped_events"` // Exit non-zero above this many drops; 0 disables
+}
+
+type MetricsConfig struct {
+	Pushgateway PushgatewayConfig `yaml:"pushgateway"`
+	Persist     bool              `yaml:"persist"`      // Restore counters on startup and save them on shutdown
+	PersistPath string            `yaml:"persist_path"` // File used when persistence is enabled
+
+	APILatencyBuckets []int64 `yaml:"api_latency_buckets"` // Histogram bucket upper bounds in milliseconds
+	RateWindow        time.Duration `yaml:"rate_window"`   // Span averaged by events_per_second_smoothed and recent rates
 }
 
+type PushgatewayConfig struct {
+	URL      string            `yaml:"url"` // Pushgateway base URL; empty disables pushing
+	Job      string            `yaml:"job"`
+	Interval time.Duration     `yaml:"interval"`
+	Labels   map[string]string `yaml:"labels"` // Grouping labels added to the push URL
+}
+
+type EventConfig struct {
+	TimestampSource string        `yaml:"timestamp_source"` // "ingest", "last_contact", or "time_position"
+	MaxPast         time.Duration `yaml:"max_past"`         // Reject events older than this; 0 disables
+	MaxFuture       time.Duration `yaml:"max_future"`       // Reject events newer than now plus this; 0 disables
+}
+
+type AutoscaleConfig struct {
+	BufferWeight     float64 `yaml:"buffer_weight"`
+	ThroughputWeight float64 `yaml:"throughput_weight"`
+	DropWeight       float64 `yaml:"drop_weight"`
+}
+
+type RedactionConfig struct {
+	Rules []RedactionRule `yaml:"rules"`
+}
+
+type RedactionRule struct {
+	ICAO24Ranges []string `yaml:"icao24_ranges"` // Inclusive hex ranges, e.g. "ae0000-afffff"
+	Countries    []string `yaml:"countries"`     // Exact origin country names
+	Fields       []string `yaml:"fields"`        // Fields to blank, e.g. "latitude", "longitude"
+}
+
+type WatchdogConfig struct {
+	StallThreshold time.Duration `yaml:"stall_threshold"` // No processed events for this long is a stall; 0 disables
+	Action         string        `yaml:"action"`          // "log", "restart_poller", or "mark_unready"
+}
+
+type AlertsConfig struct {
+	Interval   time.Duration `yaml:"interval"`
+	WebhookURL string        `yaml:"webhook_url"` // Alerts are logged when empty
+	Rules      []AlertRule   `yaml:"rules"`
+}
+
+type AlertRule struct {
+	Name      string        `yaml:"name"`
+	Metric    string        `yaml:"metric"`    // "drop_rate", "events_per_second", "buffer_utilization", "api_error_rate"
+	Operator  string        `yaml:"operator"`  // ">" or "<"
+	Threshold float64       `yaml:"threshold"` // Value that must be crossed to fire
+	Clear     float64       `yaml:"clear"`     // Value that must be crossed back to resolve; defaults to threshold
+	For       time.Duration `yaml:"for"`       // How long the condition must hold before firing
+}
+
+type SinkConfig struct {
+	BatchSize     int                `yaml:"batch_size"`
+	FlushInterval time.Duration      `yaml:"flush_interval"`
+	QueueSize     int                `yaml:"queue_size"`    // Events each output may fall behind before dropping
+	PartitionKey  string             `yaml:"partition_key"` // "icao24", "country", or "region"
+	Outputs       []SinkOutputConfig `yaml:"outputs"`
+}
+
+type SinkOutputConfig struct {
+	Name           string        `yaml:"name"`
+	Type           string        `yaml:"type"`             // "file" or "http"
+	Path           string        `yaml:"path"`             // NDJSON file to append to, for "file"
+	URL            string        `yaml:"url"`              // Endpoint to POST batches to, for "http"
+	Retries        int           `yaml:"retries"`          // Retries after a failed write
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay"` // Backoff before the first retry, doubled for each further retry
+	DLQ            string        `yaml:"dlq"`              // NDJSON file for batches that exhaust their retries; dropped when empty
+}
+
+// Load reads a single config file; see LoadLayered
 func Load(configPath string) (*Config, error) {
+	return LoadLayered(configPath)
+}
+
+// LoadLayered reads the given config files in order on top of the defaults,
+// so a committed base config can be combined with local or secret overrides.
+// Each file only overrides the fields it sets: nested sections are merged,
+// while lists are replaced wholesale. Empty paths are skipped. Environment
+// variables are applied last and the merged result is validated.
+func LoadLayered(configPaths ...string) (*Config, error) {
 	config := &Config{}
 
 	// Set defaults
 	config.setDefaults()
 
-	// Load from file if provided
-	if configPath != "" {
+	// Load each file in order, later files winning on set fields
+	for _, configPath := range configPaths {
+		if configPath == "" {
+			continue
+		}
+
 		data, err := os.ReadFile(configPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+			return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
 		}
 
-		if err := yaml.Unmarshal(data, config); err != nil {
-			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		if err := decodeConfig(configPath, data, config); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", configPath, err)
 		}
 	}
 
 	// Override with environment variables
-	config.loadFromEnv()
+	if err := config.loadFromEnv(); err != nil {
+		return nil, err
+	}
 
 	// Validate configuration
 	if err := config.validate(); err != nil {
@@ -78,33 +259,113 @@ func Load(configPath string) (*Config, error) {
 	return config, nil
 }
 
+// decodeConfig decodes a config file into config, choosing the format from
+// the file extension. JSON is a subset of YAML, so once a .json file has been
+// checked to be valid JSON it is decoded by the YAML decoder too; both formats
+// share the same field names, duration syntax and merge behavior.
+func decodeConfig(configPath string, data []byte, config *Config) error {
+	switch ext := strings.ToLower(filepath.Ext(configPath)); ext {
+	case ".yaml", ".yml":
+	case ".json":
+		var raw json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported config file extension %q (expected .yaml, .yml or .json)", ext)
+	}
+
+	return yaml.Unmarshal(data, config)
+}
+
 func (c *Config) setDefaults() {
 	c.Server.Port = 8080
 	c.Server.ReadTimeout = 15 * time.Second
 	c.Server.WriteTimeout = 15 * time.Second
 	c.Server.IdleTimeout = 60 * time.Second
+	c.Server.MaxEventsLimit = 5000
+	c.Server.CORS.MaxAge = 10 * time.Minute
+	c.Server.ClientRateLimit.Burst = 20
+	c.Server.MaxRequestBytes = 10 << 20
 
 	c.OpenSky.BaseURL = "https://opensky-network.org/api"
 	c.OpenSky.PollInterval = 10 * time.Second
 	c.OpenSky.RequestTimeout = 30 * time.Second
+	c.OpenSky.MaxConcurrentRequests = 2
+	c.OpenSky.WarmupInterval = 10 * time.Second
+	c.OpenSky.WarmupTargetFill = 50
+	c.OpenSky.RetryMaxAttempts = 3
+	c.OpenSky.RetryBaseDelay = 1 * time.Second
 
 	c.RateLimit.EventsPerSecond = 100
 	c.RateLimit.BurstSize = 200
 	c.RateLimit.WindowDuration = 1 * time.Second
+	c.RateLimit.PerAircraftTracked = 50000
+	c.RateLimit.Algorithm = "token_bucket"
+	c.RateLimit.PerAircraftBurst = 5
+	c.RateLimit.Adaptive.MinRate = 10
+	c.RateLimit.Adaptive.MaxRate = 100
+	c.RateLimit.Adaptive.Interval = 5 * time.Second
+	c.RateLimit.EmergencySquawks = []string{"7500", "7600", "7700"}
 
 	c.Buffer.Type = "ring"
 	c.Buffer.Size = 10000
 	c.Buffer.BatchSize = 100
 	c.Buffer.FlushInterval = 5 * time.Second
 
+	c.Event.TimestampSource = "ingest"
+
+	c.Alerts.Interval = 10 * time.Second
+
+	c.Sink.BatchSize = 100
+	c.Sink.FlushInterval = 1 * time.Second
+	c.Sink.QueueSize = 10000
+	c.Sink.PartitionKey = "icao24"
+
+	c.Watchdog.StallThreshold = 60 * time.Second
+	c.Watchdog.Action = "log"
+
+	c.Autoscale.BufferWeight = 0.5
+	c.Autoscale.ThroughputWeight = 0.3
+	c.Autoscale.DropWeight = 0.2
+
 	c.Logging.Level = "INFO"
+	c.Logging.Format = "text"
+	c.Logging.DropSummaryInterval = 10 * time.Second
+
+	c.Metrics.Pushgateway.Job = "flight_event_throttler"
+	c.Metrics.Pushgateway.Interval = 15 * time.Second
+	c.Metrics.PersistPath = "metrics_state.json"
+	c.Metrics.APILatencyBuckets = []int64{10, 50, 100, 250, 500, 1000, 2500}
+	c.Metrics.RateWindow = 10 * time.Second
 }
 
-func (c *Config) loadFromEnv() {
-	if port := os.Getenv("PORT"); port != "" {
-		if p, err := strconv.Atoi(port); err == nil {
-			c.Server.Port = p
-		}
+// loadFromEnv overrides settings from environment variables. A numeric or
+// duration variable that doesn't parse is an error rather than being ignored,
+// so a typo can't silently leave the file value in place.
+func (c *Config) loadFromEnv() error {
+	if err := envInt("PORT", &c.Server.Port); err != nil {
+		return err
+	}
+
+	if basePath := os.Getenv("SERVER_BASE_PATH"); basePath != "" {
+		c.Server.BasePath = basePath
+	}
+
+	if err := envDuration("SERVER_READ_TIMEOUT", &c.Server.ReadTimeout); err != nil {
+		return err
+	}
+
+	if err := envDuration("SERVER_WRITE_TIMEOUT", &c.Server.WriteTimeout); err != nil {
+		return err
+	}
+
+	if err := envDuration("SERVER_IDLE_TIMEOUT", &c.Server.IdleTimeout); err != nil {
+		return err
+	}
+
+	if adminToken := os.Getenv("ADMIN_TOKEN"); adminToken != "" {
+		c.Server.AdminToken = adminToken
 	}
 
 	if baseURL := os.Getenv("OPENSKY_BASE_URL"); baseURL != "" {
@@ -119,25 +380,68 @@ func (c *Config) loadFromEnv() {
 		c.OpenSky.Password = password
 	}
 
+	if err := envDuration("OPENSKY_POLL_INTERVAL", &c.OpenSky.PollInterval); err != nil {
+		return err
+	}
+
+	if err := envDuration("OPENSKY_REQUEST_TIMEOUT", &c.OpenSky.RequestTimeout); err != nil {
+		return err
+	}
+
+	if pushURL := os.Getenv("PUSHGATEWAY_URL"); pushURL != "" {
+		c.Metrics.Pushgateway.URL = pushURL
+	}
+
 	if logLevel := os.Getenv("LOG_LEVEL"); logLevel != "" {
 		c.Logging.Level = logLevel
 	}
 
-	if rps := os.Getenv("RATE_LIMIT_RPS"); rps != "" {
-		if r, err := strconv.Atoi(rps); err == nil {
-			c.RateLimit.EventsPerSecond = r
-		}
+	if err := envInt("RATE_LIMIT_RPS", &c.RateLimit.EventsPerSecond); err != nil {
+		return err
+	}
+
+	if err := envInt("RATE_LIMIT_BURST", &c.RateLimit.BurstSize); err != nil {
+		return err
 	}
 
 	if bufferType := os.Getenv("BUFFER_TYPE"); bufferType != "" {
 		c.Buffer.Type = bufferType
 	}
 
-	if bufferSize := os.Getenv("BUFFER_SIZE"); bufferSize != "" {
-		if s, err := strconv.Atoi(bufferSize); err == nil {
-			c.Buffer.Size = s
-		}
+	if err := envInt("BUFFER_SIZE", &c.Buffer.Size); err != nil {
+		return err
 	}
+
+	return nil
+}
+
+// envInt sets *dst from the named environment variable when it is set
+func envInt(name string, dst *int) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("environment variable %s must be an integer, got %q", name, value)
+	}
+	*dst = parsed
+	return nil
+}
+
+// envDuration sets *dst from the named environment variable, a Go duration
+// such as "30s", when it is set
+func envDuration(name string, dst *time.Duration) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("environment variable %s must be a duration such as \"30s\", got %q", name, value)
+	}
+	*dst = parsed
+	return nil
 }
 
 func (c *Config) validate() error {
@@ -145,25 +449,300 @@ func (c *Config) validate() error {
 		return fmt.Errorf("server port must be between 1 and 65535")
 	}
 
+	if c.Server.BasePath != "" && !strings.HasPrefix(c.Server.BasePath, "/") {
+		return fmt.Errorf("server base path must start with '/'")
+	}
+
+	if c.Server.MaxEventsLimit < 1 {
+		return fmt.Errorf("server max events limit must be at least 1")
+	}
+
+	// Combining "*" with credentials is rejected by api.Server.SetCORS
+	for _, origin := range c.Server.CORS.AllowedOrigins {
+		if origin == "" {
+			return fmt.Errorf("cors allowed origins cannot be empty")
+		}
+	}
+
+	if c.Server.CORS.MaxAge < 0 {
+		return fmt.Errorf("cors max age cannot be negative")
+	}
+
+	if c.Server.ClientRateLimit.RequestsPerSecond < 0 {
+		return fmt.Errorf("client rate limit requests per second cannot be negative")
+	}
+
+	if c.Server.ClientRateLimit.RequestsPerSecond > 0 && c.Server.ClientRateLimit.Burst < 1 {
+		return fmt.Errorf("client rate limit burst must be at least 1")
+	}
+
+	if c.Server.Ingest.Enabled && c.Server.AdminToken == "" {
+		return fmt.Errorf("server ingest requires an admin token")
+	}
+
+	if c.Server.MaxRequestBytes < 1 {
+		return fmt.Errorf("server max request bytes must be at least 1")
+	}
+
 	if c.OpenSky.BaseURL == "" {
 		return fmt.Errorf("opensky base URL cannot be empty")
 	}
 
+	if c.OpenSky.MaxConcurrentRequests < 1 {
+		return fmt.Errorf("max concurrent requests must be at least 1")
+	}
+
+	if minInterval := c.minPollInterval(); c.OpenSky.PollInterval < minInterval {
+		access := "anonymous"
+		if c.hasOpenSkyCredentials() {
+			access = "authenticated"
+		}
+		return fmt.Errorf("poll interval %v is below the %v minimum for %s OpenSky access; shorter intervals get rate limited", c.OpenSky.PollInterval, minInterval, access)
+	}
+
+	if c.OpenSky.WarmupPolls < 0 {
+		return fmt.Errorf("warmup polls cannot be negative")
+	}
+
+	if c.OpenSky.WarmupPolls > 0 && c.OpenSky.WarmupInterval < c.minPollInterval() {
+		return fmt.Errorf("warmup interval must be at least %v to respect OpenSky rate limits", c.minPollInterval())
+	}
+
+	if c.OpenSky.RetryMaxAttempts < 1 {
+		return fmt.Errorf("retry max attempts must be at least 1")
+	}
+
+	if c.OpenSky.RetryBaseDelay < 0 {
+		return fmt.Errorf("retry base delay cannot be negative")
+	}
+
+	if c.OpenSky.CacheTTL < 0 {
+		return fmt.Errorf("cache TTL cannot be negative")
+	}
+
+	if box := c.OpenSky.BoundingBox; box != nil {
+		if box.LaMin < -90 || box.LaMax > 90 || box.LoMin < -180 || box.LoMax > 180 {
+			return fmt.Errorf("bounding box must lie within [-90, 90] latitude and [-180, 180] longitude")
+		}
+		if box.LaMin >= box.LaMax || box.LoMin >= box.LoMax {
+			return fmt.Errorf("bounding box minimums must be less than maximums")
+		}
+	}
+
 	if c.RateLimit.EventsPerSecond < 1 {
 		return fmt.Errorf("events per second must be at least 1")
 	}
 
+	if c.RateLimit.PerAircraftInterval < 0 {
+		return fmt.Errorf("per-aircraft interval cannot be negative")
+	}
+
+	if c.Logging.Format != "text" && c.Logging.Format != "json" {
+		return fmt.Errorf("logging format must be 'text' or 'json'")
+	}
+
+	if c.RateLimit.Algorithm != "token_bucket" && c.RateLimit.Algorithm != "leaky_bucket" {
+		return fmt.Errorf("rate limit algorithm must be 'token_bucket' or 'leaky_bucket'")
+	}
+
+	if c.RateLimit.Adaptive.Enabled {
+		if c.RateLimit.Algorithm != "token_bucket" {
+			return fmt.Errorf("adaptive rate limiting requires the token_bucket algorithm")
+		}
+		if c.RateLimit.Adaptive.MinRate < 1 {
+			return fmt.Errorf("adaptive min rate must be at least 1")
+		}
+		if c.RateLimit.Adaptive.MaxRate < c.RateLimit.Adaptive.MinRate {
+			return fmt.Errorf("adaptive max rate must be at least the min rate")
+		}
+		if c.RateLimit.Adaptive.Interval <= 0 {
+			return fmt.Errorf("adaptive interval must be positive")
+		}
+	}
+
+	for _, code := range c.RateLimit.EmergencySquawks {
+		if len(code) != 4 || strings.Trim(code, "01234567") != "" {
+			return fmt.Errorf("invalid emergency squawk %q: must be four octal digits", code)
+		}
+	}
+
+	if c.RateLimit.PerAircraftRate < 0 {
+		return fmt.Errorf("per-aircraft rate cannot be negative")
+	}
+
+	if c.RateLimit.PerAircraftRate > 0 && c.RateLimit.PerAircraftBurst < 1 {
+		return fmt.Errorf("per-aircraft burst must be at least 1")
+	}
+
+	if c.RateLimit.PerAircraftInterval > 0 && c.RateLimit.PerAircraftTracked < 1 {
+		return fmt.Errorf("per-aircraft tracked must be at least 1")
+	}
+
 	if c.Buffer.Type != "ring" && c.Buffer.Type != "sliding_window" {
 		return fmt.Errorf("buffer type must be 'ring' or 'sliding_window'")
 	}
 
+	if c.Buffer.OverflowPath != "" && c.Buffer.Type != "ring" {
+		return fmt.Errorf("buffer overflow path is only supported for the ring buffer")
+	}
+
+	if c.Buffer.SnapshotPath != "" && c.Buffer.Type != "sliding_window" {
+		return fmt.Errorf("buffer snapshot path is only supported for the sliding window buffer")
+	}
+
+	if c.Buffer.Dedup && c.Buffer.Type != "ring" {
+		return fmt.Errorf("buffer dedup is only supported for the ring buffer")
+	}
+
 	if c.Buffer.Size < 1 {
 		return fmt.Errorf("buffer size must be at least 1")
 	}
 
-	if c.Logging.Level != "DEBUG" && c.Logging.Level != "INFO" && c.Logging.Level != "ERROR" {
-		return fmt.Errorf("log level must be 'DEBUG', 'INFO', or 'ERROR'")
+	if c.Buffer.MaxBytes < 0 {
+		return fmt.Errorf("buffer max bytes cannot be negative")
+	}
+
+	switch c.Event.TimestampSource {
+	case "ingest", "last_contact", "time_position":
+	default:
+		return fmt.Errorf("event timestamp source must be 'ingest', 'last_contact', or 'time_position'")
 	}
 
+	if c.Event.MaxPast < 0 || c.Event.MaxFuture < 0 {
+		return fmt.Errorf("event max past and max future cannot be negative")
+	}
+
+	if c.Autoscale.BufferWeight < 0 || c.Autoscale.ThroughputWeight < 0 || c.Autoscale.DropWeight < 0 {
+		return fmt.Errorf("autoscale weights cannot be negative")
+	}
+
+	if len(c.Metrics.APILatencyBuckets) == 0 {
+		return fmt.Errorf("at least one API latency bucket is required")
+	}
+	for i, bound := range c.Metrics.APILatencyBuckets {
+		if bound <= 0 || (i > 0 && bound <= c.Metrics.APILatencyBuckets[i-1]) {
+			return fmt.Errorf("API latency buckets must be positive and strictly increasing")
+		}
+	}
+
+	if c.Metrics.RateWindow < time.Second {
+		return fmt.Errorf("metrics rate window must be at least 1s")
+	}
+
+	if c.Watchdog.StallThreshold < 0 {
+		return fmt.Errorf("watchdog stall threshold cannot be negative")
+	}
+
+	if c.Watchdog.Action != "log" && c.Watchdog.Action != "restart_poller" && c.Watchdog.Action != "mark_unready" {
+		return fmt.Errorf("watchdog action must be 'log', 'restart_poller', or 'mark_unready'")
+	}
+
+	if len(c.Alerts.Rules) > 0 && c.Alerts.Interval <= 0 {
+		return fmt.Errorf("alerts interval must be positive")
+	}
+
+	if err := c.Sink.validate(); err != nil {
+		return err
+	}
+
+	switch strings.ToUpper(c.Logging.Level) {
+	case "TRACE", "DEBUG", "INFO", "WARN", "ERROR":
+	default:
+		return fmt.Errorf("log level must be 'TRACE', 'DEBUG', 'INFO', 'WARN', or 'ERROR'")
+	}
+
+	if c.Shutdown.MaxDroppedEvents < 0 {
+		return fmt.Errorf("max dropped events cannot be negative")
+	}
+
+	if c.Metrics.Pushgateway.URL != "" {
+		if c.Metrics.Pushgateway.Job == "" {
+			return fmt.Errorf("pushgateway job cannot be empty")
+		}
+		if c.Metrics.Pushgateway.Interval <= 0 {
+			return fmt.Errorf("pushgateway interval must be positive")
+		}
+	}
+
+	if c.Metrics.Persist && c.Metrics.PersistPath == "" {
+		return fmt.Errorf("metrics persist path cannot be empty when persistence is enabled")
+	}
+
+	if c.Logging.DropSummaryInterval <= 0 {
+		return fmt.Errorf("drop summary interval must be positive")
+	}
+
+	return nil
+}
+
+// Shortest poll intervals accepted by validate. They are conservative
+// compared to OpenSky's documented limits (5s anonymous, 1s authenticated)
+// and are variables so tests can lower them.
+var (
+	MinAnonymousPollInterval     = 10 * time.Second
+	MinAuthenticatedPollInterval = 5 * time.Second
+)
+
+// minPollInterval returns the shortest interval OpenSky tolerates between polls,
+// which is lower for authenticated clients
+func (c *Config) minPollInterval() time.Duration {
+	if c.hasOpenSkyCredentials() {
+		return MinAuthenticatedPollInterval
+	}
+	return MinAnonymousPollInterval
+}
+
+// hasOpenSkyCredentials reports whether both OpenSky credentials are set
+func (c *Config) hasOpenSkyCredentials() bool {
+	return c.OpenSky.Username != "" && c.OpenSky.Password != ""
+}
+
+func (s *SinkConfig) validate() error {
+	if len(s.Outputs) == 0 {
+		return nil
+	}
+	if s.BatchSize < 1 {
+		return fmt.Errorf("sink batch size must be at least 1")
+	}
+	if s.FlushInterval <= 0 {
+		return fmt.Errorf("sink flush interval must be positive")
+	}
+	if s.QueueSize < 1 {
+		return fmt.Errorf("sink queue size must be at least 1")
+	}
+	if s.PartitionKey != "icao24" && s.PartitionKey != "country" && s.PartitionKey != "region" {
+		return fmt.Errorf("sink partition key must be 'icao24', 'country', or 'region'")
+	}
+
+	names := make(map[string]bool, len(s.Outputs))
+	for _, output := range s.Outputs {
+		if output.Name == "" {
+			return fmt.Errorf("sink output name cannot be empty")
+		}
+		if names[output.Name] {
+			return fmt.Errorf("duplicate sink output %q", output.Name)
+		}
+		names[output.Name] = true
+
+		switch output.Type {
+		case "file":
+			if output.Path == "" {
+				return fmt.Errorf("sink output %q needs a path", output.Name)
+			}
+		case "http":
+			if !strings.HasPrefix(output.URL, "http://") && !strings.HasPrefix(output.URL, "https://") {
+				return fmt.Errorf("sink output %q needs an http or https url", output.Name)
+			}
+		default:
+			return fmt.Errorf("sink output %q type must be 'file' or 'http'", output.Name)
+		}
+
+		if output.Retries < 0 {
+			return fmt.Errorf("sink output %q retries cannot be negative", output.Name)
+		}
+		if output.RetryBaseDelay < 0 {
+			return fmt.Errorf("sink output %q retry base delay cannot be negative", output.Name)
+		}
+	}
 	return nil
 }
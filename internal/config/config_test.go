@@ -0,0 +1,426 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// configEnv lists the environment variables loadFromEnv reads
+var configEnv = []string{
+	"PORT", "SERVER_BASE_PATH", "SERVER_READ_TIMEOUT", "SERVER_WRITE_TIMEOUT", "SERVER_IDLE_TIMEOUT",
+	"ADMIN_TOKEN", "OPENSKY_BASE_URL", "OPENSKY_USERNAME", "OPENSKY_PASSWORD",
+	"OPENSKY_POLL_INTERVAL", "OPENSKY_REQUEST_TIMEOUT", "PUSHGATEWAY_URL", "LOG_LEVEL",
+	"RATE_LIMIT_RPS", "RATE_LIMIT_BURST", "BUFFER_TYPE", "BUFFER_SIZE",
+}
+
+// clearConfigEnv unsets the config environment variables for the test, so
+// the host environment can't leak into the loaded config
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+
+	for _, name := range configEnv {
+		t.Setenv(name, "")
+	}
+}
+
+// writeConfig writes content to a file called name in a temporary directory
+// and returns its path
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadYAMLAndJSONAreEquivalent(t *testing.T) {
+	clearConfigEnv(t)
+
+	yamlPath := writeConfig(t, "config.yaml", `
+server:
+  port: 9090
+  base_path: /throttler
+  cors:
+    allowed_origins: ["https://ops.example.com"]
+opensky:
+  poll_interval: 15s
+  bounding_box:
+    lamin: 45.8
+    lomin: 5.9
+    lamax: 47.8
+    lomax: 10.5
+rate_limit:
+  events_per_second: 50
+  emergency_squawks: ["7700"]
+buffer:
+  type: sliding_window
+  size: 500
+logging:
+  level: DEBUG
+`)
+	jsonPath := writeConfig(t, "config.json", `{
+  "server": {
+    "port": 9090,
+    "base_path": "/throttler",
+    "cors": {"allowed_origins": ["https://ops.example.com"]}
+  },
+  "opensky": {
+    "poll_interval": "15s",
+    "bounding_box": {"lamin": 45.8, "lomin": 5.9, "lamax": 47.8, "lomax": 10.5}
+  },
+  "rate_limit": {"events_per_second": 50, "emergency_squawks": ["7700"]},
+  "buffer": {"type": "sliding_window", "size": 500},
+  "logging": {"level": "DEBUG"}
+}`)
+
+	fromYAML, err := Load(yamlPath)
+	if err != nil {
+		t.Fatalf("Load(yaml): %v", err)
+	}
+	fromJSON, err := Load(jsonPath)
+	if err != nil {
+		t.Fatalf("Load(json): %v", err)
+	}
+
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Errorf("YAML and JSON configs differ:\nyaml: %+v\njson: %+v", fromYAML, fromJSON)
+	}
+
+	// Set fields are decoded and unset ones keep their defaults
+	if fromJSON.OpenSky.PollInterval != 15*time.Second {
+		t.Errorf("poll interval = %v, want 15s", fromJSON.OpenSky.PollInterval)
+	}
+	if fromJSON.Buffer.Type != "sliding_window" || fromJSON.Buffer.Size != 500 {
+		t.Errorf("buffer = %s/%d, want sliding_window/500", fromJSON.Buffer.Type, fromJSON.Buffer.Size)
+	}
+	if fromJSON.RateLimit.BurstSize != 200 {
+		t.Errorf("burst size = %d, want the default 200", fromJSON.RateLimit.BurstSize)
+	}
+}
+
+func TestLoadRejectsUnsupportedExtension(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfig(t, "config.toml", "[server]\nport = 9090\n")
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), `unsupported config file extension ".toml"`) {
+		t.Errorf("Load(.toml) error = %v, want unsupported extension", err)
+	}
+}
+
+func TestLoadRejectsInvalidJSON(t *testing.T) {
+	clearConfigEnv(t)
+
+	// Valid YAML, but not JSON
+	path := writeConfig(t, "config.json", "server:\n  port: 9090\n")
+	if _, err := Load(path); err == nil {
+		t.Error("Load accepted a .json file that isn't JSON")
+	}
+}
+
+func TestValidatePollIntervalMinimum(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials bool
+		interval    time.Duration
+		wantErr     string
+	}{
+		{"anonymous at minimum", false, 10 * time.Second, ""},
+		{"anonymous too short", false, 5 * time.Second, "below the 10s minimum for anonymous"},
+		{"authenticated at minimum", true, 5 * time.Second, ""},
+		{"authenticated too short", true, 2 * time.Second, "below the 5s minimum for authenticated"},
+	}
+	for _, tt := range tests {
+		cfg := &Config{}
+		cfg.setDefaults()
+		cfg.OpenSky.PollInterval = tt.interval
+		if tt.credentials {
+			cfg.OpenSky.Username, cfg.OpenSky.Password = "user", "pass"
+		}
+
+		err := cfg.validate()
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: validate() = %v, want nil", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: validate() = %v, want error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidatePollIntervalMinimumOverridable(t *testing.T) {
+	defer func(anonymous time.Duration) { MinAnonymousPollInterval = anonymous }(MinAnonymousPollInterval)
+	MinAnonymousPollInterval = time.Millisecond
+
+	cfg := &Config{}
+	cfg.setDefaults()
+	cfg.OpenSky.PollInterval = 10 * time.Millisecond
+	if err := cfg.validate(); err != nil {
+		t.Errorf("validate() with a lowered minimum = %v, want nil", err)
+	}
+}
+
+func TestLoadFromEnvOverrides(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("PORT", "9090")
+	t.Setenv("SERVER_READ_TIMEOUT", "5s")
+	t.Setenv("SERVER_WRITE_TIMEOUT", "7s")
+	t.Setenv("SERVER_IDLE_TIMEOUT", "2m")
+	t.Setenv("OPENSKY_POLL_INTERVAL", "30s")
+	t.Setenv("OPENSKY_REQUEST_TIMEOUT", "1500ms")
+	t.Setenv("RATE_LIMIT_RPS", "40")
+	t.Setenv("RATE_LIMIT_BURST", "80")
+	t.Setenv("BUFFER_SIZE", "250")
+
+	// Environment variables win over the file
+	path := writeConfig(t, "config.yaml", "opensky:\n  poll_interval: 20s\nrate_limit:\n  burst_size: 10\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.Server.Port != 9090 {
+		t.Errorf("port = %d, want 9090", cfg.Server.Port)
+	}
+	if cfg.Server.ReadTimeout != 5*time.Second || cfg.Server.WriteTimeout != 7*time.Second || cfg.Server.IdleTimeout != 2*time.Minute {
+		t.Errorf("server timeouts = %v/%v/%v, want 5s/7s/2m0s", cfg.Server.ReadTimeout, cfg.Server.WriteTimeout, cfg.Server.IdleTimeout)
+	}
+	if cfg.OpenSky.PollInterval != 30*time.Second {
+		t.Errorf("poll interval = %v, want 30s", cfg.OpenSky.PollInterval)
+	}
+	if cfg.OpenSky.RequestTimeout != 1500*time.Millisecond {
+		t.Errorf("request timeout = %v, want 1.5s", cfg.OpenSky.RequestTimeout)
+	}
+	if cfg.RateLimit.EventsPerSecond != 40 || cfg.RateLimit.BurstSize != 80 {
+		t.Errorf("rate limit = %d/s burst %d, want 40/s burst 80", cfg.RateLimit.EventsPerSecond, cfg.RateLimit.BurstSize)
+	}
+	if cfg.Buffer.Size != 250 {
+		t.Errorf("buffer size = %d, want 250", cfg.Buffer.Size)
+	}
+}
+
+func TestLoadFromEnvRejectsUnparsableValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"PORT", "80a"},
+		{"RATE_LIMIT_RPS", "fast"},
+		{"RATE_LIMIT_BURST", "1.5"},
+		{"BUFFER_SIZE", "10k"},
+		{"OPENSKY_POLL_INTERVAL", "30"},
+		{"OPENSKY_REQUEST_TIMEOUT", "soon"},
+		{"SERVER_READ_TIMEOUT", "5 seconds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv(t)
+			t.Setenv(tt.name, tt.value)
+
+			cfg := &Config{}
+			cfg.setDefaults()
+			err := cfg.loadFromEnv()
+			if err == nil || !strings.Contains(err.Error(), tt.name) {
+				t.Errorf("loadFromEnv() with %s=%q = %v, want an error naming the variable", tt.name, tt.value, err)
+			}
+		})
+	}
+}
+
+func TestValidateIngestRequiresAdminToken(t *testing.T) {
+	cfg := &Config{}
+	cfg.setDefaults()
+	cfg.Server.Ingest.Enabled = true
+
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), "admin token") {
+		t.Errorf("validate() without an admin token = %v, want an admin token error", err)
+	}
+
+	cfg.Server.AdminToken = "secret"
+	if err := cfg.validate(); err != nil {
+		t.Errorf("validate() with an admin token = %v, want nil", err)
+	}
+}
+
+func TestValidateSinkOutputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  SinkOutputConfig
+		wantErr string
+	}{
+		{"file", SinkOutputConfig{Name: "archive", Type: "file", Path: "events.ndjson", Retries: 3, DLQ: "dlq.ndjson"}, ""},
+		{"http", SinkOutputConfig{Name: "ingest", Type: "http", URL: "https://example.com/events"}, ""},
+		{"missing name", SinkOutputConfig{Type: "file", Path: "events.ndjson"}, "name cannot be empty"},
+		{"unknown type", SinkOutputConfig{Name: "queue", Type: "kafka"}, "type must be"},
+		{"file without path", SinkOutputConfig{Name: "archive", Type: "file"}, "needs a path"},
+		{"http without scheme", SinkOutputConfig{Name: "ingest", Type: "http", URL: "example.com"}, "http or https url"},
+		{"negative retries", SinkOutputConfig{Name: "archive", Type: "file", Path: "events.ndjson", Retries: -1}, "retries cannot be negative"},
+		{"negative delay", SinkOutputConfig{Name: "archive", Type: "file", Path: "events.ndjson", RetryBaseDelay: -time.Second}, "retry base delay cannot be negative"},
+	}
+	for _, tt := range tests {
+		cfg := &Config{}
+		cfg.setDefaults()
+		cfg.Sink.Outputs = []SinkOutputConfig{tt.output}
+
+		err := cfg.validate()
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: validate() = %v, want nil", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: validate() = %v, want error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	cfg := &Config{}
+	cfg.setDefaults()
+	output := SinkOutputConfig{Name: "archive", Type: "file", Path: "events.ndjson"}
+	cfg.Sink.Outputs = []SinkOutputConfig{output, output}
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("validate() with duplicate outputs = %v, want a duplicate error", err)
+	}
+
+	cfg.Sink.Outputs = []SinkOutputConfig{output}
+	cfg.Sink.PartitionKey = "callsign"
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), "partition key") {
+		t.Errorf("validate() with partition key callsign = %v, want a partition key error", err)
+	}
+}
+
+func TestLoadLayeredMergesNestedSections(t *testing.T) {
+	clearConfigEnv(t)
+
+	base := writeConfig(t, "base.yaml", `
+server:
+  port: 9090
+  base_path: /throttler
+opensky:
+  poll_interval: 20s
+  bounding_box:
+    lamin: 45.8
+    lomin: 5.9
+    lamax: 47.8
+    lomax: 10.5
+metrics:
+  pushgateway:
+    url: http://push.example.com
+    job: throttler
+    labels:
+      env: prod
+      region: eu
+`)
+	override := writeConfig(t, "override.yaml", `
+server:
+  port: 9191
+opensky:
+  bounding_box:
+    lamax: 48.5
+metrics:
+  pushgateway:
+    labels:
+      region: us
+`)
+
+	cfg, err := LoadLayered(base, override)
+	if err != nil {
+		t.Fatalf("LoadLayered: %v", err)
+	}
+
+	// Fields the override sets win; its siblings keep the base values
+	if cfg.Server.Port != 9191 || cfg.Server.BasePath != "/throttler" {
+		t.Errorf("server = %d %q, want 9191 /throttler", cfg.Server.Port, cfg.Server.BasePath)
+	}
+	if cfg.OpenSky.PollInterval != 20*time.Second {
+		t.Errorf("poll interval = %v, want 20s from the base", cfg.OpenSky.PollInterval)
+	}
+	want := BoundingBoxConfig{LaMin: 45.8, LoMin: 5.9, LaMax: 48.5, LoMax: 10.5}
+	if box := cfg.OpenSky.BoundingBox; box == nil || *box != want {
+		t.Errorf("bounding box = %+v, want %+v", box, want)
+	}
+
+	// Maps merge key by key
+	labels := cfg.Metrics.Pushgateway.Labels
+	if len(labels) != 2 || labels["env"] != "prod" || labels["region"] != "us" {
+		t.Errorf("pushgateway labels = %v, want env=prod region=us", labels)
+	}
+	if cfg.Metrics.Pushgateway.Job != "throttler" {
+		t.Errorf("pushgateway job = %q, want throttler from the base", cfg.Metrics.Pushgateway.Job)
+	}
+}
+
+func TestLoadLayeredOverridesScalarsAndReplacesLists(t *testing.T) {
+	clearConfigEnv(t)
+
+	base := writeConfig(t, "base.yaml", `
+rate_limit:
+  events_per_second: 50
+  burst_size: 120
+  emergency_squawks: ["7500", "7600", "7700"]
+watchdog:
+  stall_threshold: 30s
+`)
+	override := writeConfig(t, "override.json", `{
+  "rate_limit": {"events_per_second": 80, "emergency_squawks": ["7700"]},
+  "watchdog": {"stall_threshold": "0s"}
+}`)
+
+	cfg, err := LoadLayered(base, override)
+	if err != nil {
+		t.Fatalf("LoadLayered: %v", err)
+	}
+
+	if cfg.RateLimit.EventsPerSecond != 80 || cfg.RateLimit.BurstSize != 120 {
+		t.Errorf("rate limit = %d/s burst %d, want 80/s from the override and burst 120 from the base",
+			cfg.RateLimit.EventsPerSecond, cfg.RateLimit.BurstSize)
+	}
+	// A zero set explicitly still overrides
+	if cfg.Watchdog.StallThreshold != 0 {
+		t.Errorf("stall threshold = %v, want 0s from the override", cfg.Watchdog.StallThreshold)
+	}
+	if !reflect.DeepEqual(cfg.RateLimit.EmergencySquawks, []string{"7700"}) {
+		t.Errorf("emergency squawks = %v, want the override's list only", cfg.RateLimit.EmergencySquawks)
+	}
+	// Unset everywhere keeps the default
+	if cfg.Buffer.Size != 10000 {
+		t.Errorf("buffer size = %d, want the default 10000", cfg.Buffer.Size)
+	}
+}
+
+func TestLoadLayeredAbsentLayers(t *testing.T) {
+	clearConfigEnv(t)
+
+	defaults := &Config{}
+	defaults.setDefaults()
+
+	// No files, or only empty paths, yield the defaults
+	for _, paths := range [][]string{nil, {""}, {"", ""}} {
+		cfg, err := LoadLayered(paths...)
+		if err != nil {
+			t.Fatalf("LoadLayered(%q): %v", paths, err)
+		}
+		if !reflect.DeepEqual(cfg, defaults) {
+			t.Errorf("LoadLayered(%q) differs from the defaults", paths)
+		}
+	}
+
+	// An empty path between files is skipped
+	base := writeConfig(t, "base.yaml", "server:\n  port: 9090\n")
+	cfg, err := LoadLayered(base, "")
+	if err != nil {
+		t.Fatalf("LoadLayered(base, \"\"): %v", err)
+	}
+	if cfg.Server.Port != 9090 {
+		t.Errorf("port = %d, want 9090 from the base", cfg.Server.Port)
+	}
+
+	// A listed file that doesn't exist is an error naming it
+	missing := filepath.Join(t.TempDir(), "local.yaml")
+	if _, err := LoadLayered(base, missing); err == nil || !strings.Contains(err.Error(), missing) {
+		t.Errorf("LoadLayered with a missing file = %v, want an error naming %s", err, missing)
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import "net/url"
+
+// RedactedValue replaces secrets in a redacted config
+const RedactedValue = "***"
+
+// Redacted returns a copy of the config with secrets replaced by
+// RedactedValue, safe to log or serve for debugging. Unset secrets stay
+// empty so it is still visible whether they were configured.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redacted.Server.AdminToken = redactSecret(c.Server.AdminToken)
+	redacted.OpenSky.Password = redactSecret(c.OpenSky.Password)
+	redacted.OpenSky.BaseURL = redactURL(c.OpenSky.BaseURL)
+	redacted.Metrics.Pushgateway.URL = redactURL(c.Metrics.Pushgateway.URL)
+	// Webhook URLs typically embed their credential in the path
+	redacted.Alerts.WebhookURL = redactSecret(c.Alerts.WebhookURL)
+	redacted.Sink.Outputs = make([]SinkOutputConfig, len(c.Sink.Outputs))
+	for i, output := range c.Sink.Outputs {
+		output.URL = redactURL(output.URL)
+		redacted.Sink.Outputs[i] = output
+	}
+	return &redacted
+}
+
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return RedactedValue
+}
+
+// redactURL hides the password of a URL with user info, as url.URL.Redacted
+// does
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return parsed.Redacted()
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+
+	"flight-event-throttler/pkg/logger"
+)
+
+// Watcher reloads the config files when the process receives SIGHUP. Only
+// settings that can change while running are applied, through the OnReload
+// callbacks: the rate limit and the log level. Other changes are logged as
+// requiring a restart and ignored.
+type Watcher struct {
+	paths  []string
+	logger logger.Interface
+
+	mu       sync.Mutex
+	current  *Config // Settings in effect, including applied reloads
+	onReload []func(previous, next *Config)
+}
+
+// NewWatcher creates a watcher for the config files current was loaded from
+func NewWatcher(current *Config, log logger.Interface, paths ...string) *Watcher {
+	return &Watcher{
+		paths:   paths,
+		logger:  log,
+		current: current,
+	}
+}
+
+// OnReload registers a callback that applies the live settings of a reloaded
+// config, given the settings in effect before the reload. Callbacks run in
+// registration order and must be registered before Run is called.
+func (w *Watcher) OnReload(fn func(previous, next *Config)) {
+	w.onReload = append(w.onReload, fn)
+}
+
+// Current returns the settings in effect
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.current
+}
+
+// Run reloads the config on every SIGHUP until ctx is cancelled
+func (w *Watcher) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			w.logger.Info("Received SIGHUP, reloading config")
+			if err := w.Reload(); err != nil {
+				w.logger.Error("Config reload failed, keeping current config: %v", err)
+			}
+		}
+	}
+}
+
+// Reload re-reads and validates the config files, then applies the live
+// settings. An invalid config is rejected as a whole and nothing is applied.
+func (w *Watcher) Reload() error {
+	next, err := LoadLayered(w.paths...)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, field := range restartRequired(w.current, next) {
+		w.logger.Warn("Config change to %s requires a restart and was not applied", field)
+	}
+
+	// Everything but the live settings stays as it was until a restart
+	applied := *w.current
+	applied.RateLimit.EventsPerSecond = next.RateLimit.EventsPerSecond
+	applied.RateLimit.BurstSize = next.RateLimit.BurstSize
+	applied.Logging.Level = next.Logging.Level
+
+	for _, fn := range w.onReload {
+		fn(w.current, &applied)
+	}
+	w.current = &applied
+
+	w.logger.Info("Config reloaded: %d events/sec, burst size %d, log level %s",
+		applied.RateLimit.EventsPerSecond, applied.RateLimit.BurstSize, strings.ToUpper(applied.Logging.Level))
+	return nil
+}
+
+// restartRequired lists the settings, as section.field, that differ between
+// current and next and can't be applied live
+func restartRequired(current, next *Config) []string {
+	pending := *next
+	pending.RateLimit.EventsPerSecond = current.RateLimit.EventsPerSecond
+	pending.RateLimit.BurstSize = current.RateLimit.BurstSize
+	pending.Logging.Level = current.Logging.Level
+
+	var changed []string
+	currentValue, pendingValue := reflect.ValueOf(*current), reflect.ValueOf(pending)
+	for i := 0; i < currentValue.NumField(); i++ {
+		section := currentValue.Type().Field(i)
+		a, b := currentValue.Field(i), pendingValue.Field(i)
+		if reflect.DeepEqual(a.Interface(), b.Interface()) {
+			continue
+		}
+
+		if a.Kind() != reflect.Struct {
+			changed = append(changed, yamlName(section))
+			continue
+		}
+		for j := 0; j < a.NumField(); j++ {
+			if !reflect.DeepEqual(a.Field(j).Interface(), b.Field(j).Interface()) {
+				changed = append(changed, yamlName(section)+"."+yamlName(a.Type().Field(j)))
+			}
+		}
+	}
+	return changed
+}
+
+// yamlName returns the config file key of a struct field
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
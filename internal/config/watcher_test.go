@@ -0,0 +1,82 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"flight-event-throttler/internal/processor"
+	"flight-event-throttler/pkg/logger"
+)
+
+// reloadFixture loads the config at path and returns a watcher for it whose
+// reloads are applied to a rate limiter, as main does
+func reloadFixture(t *testing.T, path string) (*Watcher, *processor.RateLimiter, *bytes.Buffer) {
+	t.Helper()
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	var logs bytes.Buffer
+	limiter := processor.NewRateLimiter(cfg.RateLimit.EventsPerSecond, cfg.RateLimit.BurstSize)
+	w := NewWatcher(cfg, logger.NewWithWriter("INFO", &logs), path)
+	w.OnReload(func(previous, next *Config) {
+		limiter.UpdateLimit(next.RateLimit.EventsPerSecond, next.RateLimit.BurstSize)
+	})
+	return w, limiter, &logs
+}
+
+func TestReloadUpdatesRateLimiter(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfig(t, "config.yaml", "rate_limit:\n  events_per_second: 100\n  burst_size: 200\nbuffer:\n  size: 1000\n")
+	w, limiter, logs := reloadFixture(t, path)
+
+	changed := "rate_limit:\n  events_per_second: 25\n  burst_size: 50\nbuffer:\n  size: 5000\nlogging:\n  level: DEBUG\n"
+	if err := os.WriteFile(path, []byte(changed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	if rate, burst := limiter.GetLimit(); rate != 25 || burst != 50 {
+		t.Errorf("limiter = %d/s burst %d, want 25/s burst 50", rate, burst)
+	}
+
+	current := w.Current()
+	if current.RateLimit.EventsPerSecond != 25 || current.Logging.Level != "DEBUG" {
+		t.Errorf("live settings not applied: rate %d, level %s", current.RateLimit.EventsPerSecond, current.Logging.Level)
+	}
+	// The buffer can't be resized while running
+	if current.Buffer.Size != 1000 {
+		t.Errorf("buffer size = %d, want 1000 until a restart", current.Buffer.Size)
+	}
+	if !strings.Contains(logs.String(), "buffer.size requires a restart") {
+		t.Errorf("restart-only change not logged:\n%s", logs.String())
+	}
+}
+
+func TestReloadRejectsInvalidConfig(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfig(t, "config.yaml", "rate_limit:\n  events_per_second: 100\n  burst_size: 200\n")
+	w, limiter, _ := reloadFixture(t, path)
+
+	if err := os.WriteFile(path, []byte("rate_limit:\n  events_per_second: 0\n  burst_size: 50\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reload(); err == nil {
+		t.Fatal("Reload accepted events_per_second: 0")
+	}
+
+	if rate, burst := limiter.GetLimit(); rate != 100 || burst != 200 {
+		t.Errorf("limiter = %d/s burst %d after a rejected reload, want 100/s burst 200", rate, burst)
+	}
+	if w.Current().RateLimit.BurstSize != 200 {
+		t.Errorf("burst size = %d after a rejected reload, want 200", w.Current().RateLimit.BurstSize)
+	}
+}
@@ -0,0 +1,120 @@
+package enrichment
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"flight-event-throttler/internal/model"
+)
+
+// aircraftInfo holds the metadata looked up for a single aircraft
+type aircraftInfo struct {
+	registration string
+	aircraftType string
+}
+
+// AircraftDB maps ICAO24 addresses to aircraft metadata
+type AircraftDB struct {
+	aircraft map[string]aircraftInfo
+}
+
+// LoadAircraftDB loads an aircraft metadata CSV (e.g., the OpenSky aircraft database).
+// The file must have a header row containing at least "icao24", plus "registration"
+// and/or "typecode" columns.
+func LoadAircraftDB(path string) (*AircraftDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open aircraft database: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read aircraft database header: %w", err)
+	}
+
+	icaoIdx, regIdx, typeIdx := -1, -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "icao24":
+			icaoIdx = i
+		case "registration":
+			regIdx = i
+		case "typecode":
+			typeIdx = i
+		}
+	}
+
+	if icaoIdx < 0 {
+		return nil, fmt.Errorf("aircraft database is missing an icao24 column")
+	}
+
+	db := &AircraftDB{
+		aircraft: make(map[string]aircraftInfo),
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse aircraft database: %w", err)
+		}
+
+		icao24 := strings.ToLower(strings.TrimSpace(field(record, icaoIdx)))
+		if icao24 == "" {
+			continue
+		}
+
+		info := aircraftInfo{
+			registration: strings.TrimSpace(field(record, regIdx)),
+			aircraftType: strings.TrimSpace(field(record, typeIdx)),
+		}
+		if info.registration == "" && info.aircraftType == "" {
+			continue
+		}
+
+		db.aircraft[icao24] = info
+	}
+
+	return db, nil
+}
+
+// field returns the value at index i, or an empty string if the column is absent
+func field(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
+
+// Count returns the number of aircraft in the database
+func (db *AircraftDB) Count() int {
+	return len(db.aircraft)
+}
+
+// Enrich populates the registration and aircraft type of an event.
+// Events for unknown aircraft are left unchanged and false is returned.
+func (db *AircraftDB) Enrich(event *model.FlightEvent) bool {
+	if db == nil || event == nil {
+		return false
+	}
+
+	info, ok := db.aircraft[strings.ToLower(event.ICAO24)]
+	if !ok {
+		return false
+	}
+
+	event.Registration = info.registration
+	event.AircraftType = info.aircraftType
+	return true
+}
@@ -0,0 +1,135 @@
+package enrichment
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+// writeDB writes a CSV aircraft database and returns its path
+func writeDB(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "aircraft.csv")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAircraftDB(t *testing.T) {
+	tests := []struct {
+		name     string
+		csv      string
+		wantErr  string
+		count    int
+		icao24   string
+		wantReg  string
+		wantType string
+	}{
+		{
+			name:  "opensky layout",
+			csv:   "icao24,registration,manufacturername,typecode\n3c6444,D-AIBD,Airbus,A319\n4ca7b5,EI-DCL,Boeing,B738\n",
+			count: 2, icao24: "4ca7b5", wantReg: "EI-DCL", wantType: "B738",
+		},
+		{
+			name:  "header mapped by name in any order and case",
+			csv:   " TypeCode ,Registration,ICAO24\nA319,D-AIBD,3c6444\n",
+			count: 1, icao24: "3c6444", wantReg: "D-AIBD", wantType: "A319",
+		},
+		{
+			name:  "missing typecode column",
+			csv:   "icao24,registration\n3c6444,D-AIBD\n",
+			count: 1, icao24: "3c6444", wantReg: "D-AIBD",
+		},
+		{
+			name:  "missing registration column",
+			csv:   "icao24,typecode\n3c6444,A319\n",
+			count: 1, icao24: "3c6444", wantType: "A319",
+		},
+		{
+			name:  "short rows and quoted fields",
+			csv:   "icao24,registration,typecode\n3c6444\n4ca7b5,\"EI-DCL\"\n39de4f,F-HBXA,\"E190\"\n",
+			count: 2, icao24: "39de4f", wantReg: "F-HBXA", wantType: "E190",
+		},
+		{
+			name:  "rows without icao24 or metadata are skipped",
+			csv:   "icao24,registration,typecode\n,D-AIBD,A319\n3c6444,,\n  ,,\n4ca7b5,EI-DCL,B738\n",
+			count: 1, icao24: "4ca7b5", wantReg: "EI-DCL", wantType: "B738",
+		},
+		{
+			// The OpenSky database has stray quotes, so they are read leniently
+			name:  "malformed quotes",
+			csv:   "icao24,registration,typecode\n3c6444,D-\"AIBD,A319\n4ca7b5,\"EI-DCL\"x,B738\n",
+			count: 2, icao24: "3c6444", wantReg: "D-\"AIBD", wantType: "A319",
+		},
+		{
+			name:    "header without icao24",
+			csv:     "registration,typecode\nD-AIBD,A319\n",
+			wantErr: "missing an icao24 column",
+		},
+		{
+			name:    "empty file",
+			csv:     "",
+			wantErr: "failed to read aircraft database header",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := LoadAircraftDB(writeDB(t, tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadAircraftDB() = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadAircraftDB() = %v", err)
+			}
+
+			if db.Count() != tt.count {
+				t.Errorf("Count() = %d, want %d", db.Count(), tt.count)
+			}
+			event := &model.FlightEvent{ICAO24: tt.icao24}
+			if !db.Enrich(event) {
+				t.Fatalf("Enrich(%s) found nothing", tt.icao24)
+			}
+			if event.Registration != tt.wantReg || event.AircraftType != tt.wantType {
+				t.Errorf("Enrich(%s) = %q/%q, want %q/%q", tt.icao24, event.Registration, event.AircraftType, tt.wantReg, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestLoadAircraftDBMissingFile(t *testing.T) {
+	if _, err := LoadAircraftDB(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("LoadAircraftDB() of a missing file should fail")
+	}
+}
+
+func TestEnrichMatchesICAO24CaseInsensitively(t *testing.T) {
+	db, err := LoadAircraftDB(writeDB(t, "icao24,registration,typecode\nABC123,N123AB,B77W\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, icao24 := range []string{"abc123", "ABC123", "aBc123"} {
+		event := &model.FlightEvent{ICAO24: icao24}
+		if !db.Enrich(event) || event.Registration != "N123AB" || event.AircraftType != "B77W" {
+			t.Errorf("Enrich(%s) = %q/%q, want N123AB/B77W", icao24, event.Registration, event.AircraftType)
+		}
+	}
+
+	unknown := &model.FlightEvent{ICAO24: "def456", Registration: "KEEP"}
+	if db.Enrich(unknown) || unknown.Registration != "KEEP" {
+		t.Errorf("Enrich of an unknown aircraft changed it: %+v", unknown)
+	}
+
+	var nilDB *AircraftDB
+	if nilDB.Enrich(&model.FlightEvent{ICAO24: "abc123"}) {
+		t.Error("a nil database should enrich nothing")
+	}
+}
@@ -0,0 +1,64 @@
+package fetcher
+
+import (
+	"sync"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// responseCache holds parsed state responses keyed by request URL
+type responseCache struct {
+	ttl     time.Duration
+	entries map[string]cacheEntry
+	mu      sync.Mutex
+}
+
+type cacheEntry struct {
+	response *model.OpenSkyResponse
+	expires  time.Time
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// get returns the cached response for url if it has not expired
+func (rc *responseCache) get(url string, now time.Time) (*model.OpenSkyResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[url]
+	if !ok || !now.Before(entry.expires) {
+		return nil, false
+	}
+	return entry.response, true
+}
+
+// put caches response for url, dropping any expired entries so the cache
+// only ever holds the URLs fetched within the last TTL
+func (rc *responseCache) put(url string, response *model.OpenSkyResponse, now time.Time) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for key, entry := range rc.entries {
+		if !now.Before(entry.expires) {
+			delete(rc.entries, key)
+		}
+	}
+	rc.entries[url] = cacheEntry{response: response, expires: now.Add(rc.ttl)}
+}
+
+// SetCacheTTL caches parsed state responses in memory for ttl, keyed by
+// request URL, so repeated identical requests are served without calling
+// OpenSky. A ttl of 0 disables the cache.
+func (c *OpenSkyClient) SetCacheTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		c.cache = nil
+		return
+	}
+	c.cache = newResponseCache(ttl)
+}
@@ -0,0 +1,63 @@
+package fetcher
+
+import (
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+func TestConvertNullIslandDropped(t *testing.T) {
+	client, m := newTestClient(t, "")
+	client.SetDropNullIsland(true)
+
+	events := client.ConvertToFlightEvents(&model.OpenSkyResponse{
+		States: [][]interface{}{stateRowAt("abc123", 0.0, 0.0)},
+	})
+
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	if events[0].Longitude != nil || events[0].Latitude != nil {
+		t.Errorf("position = (%v, %v), want both nil", events[0].Longitude, events[0].Latitude)
+	}
+	if got := m.GetNullIslandCorrected(); got != 1 {
+		t.Errorf("null island corrections = %d, want 1", got)
+	}
+}
+
+func TestConvertNullIslandKeptWhenDisabled(t *testing.T) {
+	client, m := newTestClient(t, "")
+
+	events := client.ConvertToFlightEvents(&model.OpenSkyResponse{
+		States: [][]interface{}{stateRowAt("abc123", 0.0, 0.0)},
+	})
+
+	if len(events) != 1 || events[0].Longitude == nil || events[0].Latitude == nil {
+		t.Fatalf("expected the (0, 0) position to be kept when the option is off")
+	}
+	if got := m.GetNullIslandCorrected(); got != 0 {
+		t.Errorf("null island corrections = %d, want 0", got)
+	}
+}
+
+func TestConvertNullIslandOnlyExactZero(t *testing.T) {
+	client, m := newTestClient(t, "")
+	client.SetDropNullIsland(true)
+
+	events := client.ConvertToFlightEvents(&model.OpenSkyResponse{
+		States: [][]interface{}{
+			stateRowAt("lon000", 0.0, 51.5),
+			stateRowAt("lat000", -0.1, 0.0),
+			stateRowAt("near00", 0.0001, 0.0),
+		},
+	})
+
+	for _, event := range events {
+		if event.Longitude == nil || event.Latitude == nil {
+			t.Errorf("%s: position was cleared, want it kept", event.ICAO24)
+		}
+	}
+	if got := m.GetNullIslandCorrected(); got != 0 {
+		t.Errorf("null island corrections = %d, want 0", got)
+	}
+}
@@ -1,11 +1,19 @@
 package fetcher
 
 import (
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"flight-event-throttler/internal/metrics"
@@ -15,28 +23,96 @@ import (
 
 // OpenSkyClient is a client for fetching data from OpenSky Network API
 type OpenSkyClient struct {
-	baseURL    string
-	httpClient *http.Client
-	username   string
-	password   string
-	logger     *logger.Logger
-	metrics    *metrics.Metrics
+	baseURL          string
+	httpClient       *http.Client
+	username         string
+	password         string
+	logger           logger.Interface
+	metrics          *metrics.Metrics
+	requestSem       chan struct{}  // Bounds concurrent in-flight requests; nil means unbounded
+	skipIfBusy       bool           // Skip poll ticks while the previous fetch is still running
+	dropNullIsland   bool           // Treat exact (0, 0) coordinates as missing
+	retryMaxAttempts int            // Total attempts per fetch, including the first
+	retryBaseDelay   time.Duration  // Backoff before the first retry; doubles each retry
+	backoffUntil     atomic.Int64   // Unix nanoseconds before which polls are skipped after a 429
+	lastStatesTime   atomic.Int64   // Time field of the last polled response, to detect repeats
+	cache            *responseCache // Optional cache of parsed state responses; nil disables it
 }
 
 // NewOpenSkyClient creates a new OpenSky API client
-func NewOpenSkyClient(baseURL string, timeout time.Duration, username, password string, log *logger.Logger, m *metrics.Metrics) *OpenSkyClient {
+func NewOpenSkyClient(baseURL string, timeout time.Duration, username, password string, log logger.Interface, m *metrics.Metrics) *OpenSkyClient {
 	return &OpenSkyClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
-		username: username,
-		password: password,
-		logger:   log,
-		metrics:  m,
+		username:         username,
+		password:         password,
+		logger:           log,
+		metrics:          m,
+		retryMaxAttempts: 1,
 	}
 }
 
+// SetRetry configures how fetches recover from transient failures: up to
+// maxAttempts tries in total, backing off exponentially from baseDelay with
+// jitter. A maxAttempts below 2 disables retries.
+func (c *OpenSkyClient) SetRetry(maxAttempts int, baseDelay time.Duration) {
+	c.retryMaxAttempts = maxAttempts
+	c.retryBaseDelay = baseDelay
+}
+
+// SetMaxConcurrentRequests bounds the number of simultaneous in-flight requests
+// across all of the client's methods. A value below 1 removes the bound.
+func (c *OpenSkyClient) SetMaxConcurrentRequests(n int) {
+	if n < 1 {
+		c.requestSem = nil
+		return
+	}
+	c.requestSem = make(chan struct{}, n)
+}
+
+// acquireSlot waits for a free request slot or until the context is cancelled
+func (c *OpenSkyClient) acquireSlot(ctx context.Context) error {
+	if c.requestSem == nil {
+		return nil
+	}
+
+	select {
+	case c.requestSem <- struct{}{}:
+		if c.metrics != nil {
+			c.metrics.IncrementAPIInFlight()
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseSlot frees a request slot acquired with acquireSlot
+func (c *OpenSkyClient) releaseSlot() {
+	if c.requestSem == nil {
+		return
+	}
+
+	<-c.requestSem
+	if c.metrics != nil {
+		c.metrics.DecrementAPIInFlight()
+	}
+}
+
+// SetSkipIfBusy enables non-overlapping polling: fetches run in the background and
+// ticks that arrive while the previous fetch is still running are skipped
+func (c *OpenSkyClient) SetSkipIfBusy(enabled bool) {
+	c.skipIfBusy = enabled
+}
+
+// SetDropNullIsland treats exact (0.0, 0.0) "null island" coordinates as missing,
+// since they indicate bad position data rather than real traffic
+func (c *OpenSkyClient) SetDropNullIsland(enabled bool) {
+	c.dropNullIsland = enabled
+}
+
 // FetchAllStates fetches all current flight states from OpenSky API
 func (c *OpenSkyClient) FetchAllStates(ctx context.Context) (*model.OpenSkyResponse, error) {
 	url := fmt.Sprintf("%s/states/all", c.baseURL)
@@ -50,15 +126,197 @@ func (c *OpenSkyClient) FetchStatesByBoundingBox(ctx context.Context, lamin, lom
 	return c.fetchStates(ctx, url)
 }
 
+// FetchFlightTrack fetches the track of the aircraft identified by icao24 for
+// the flight ongoing at Unix time t. A t of 0 requests the live track.
+func (c *OpenSkyClient) FetchFlightTrack(ctx context.Context, icao24 string, t int64) (*model.FlightTrack, error) {
+	query := url.Values{}
+	query.Set("icao24", strings.ToLower(icao24))
+	query.Set("time", strconv.FormatInt(t, 10))
+
+	var trackResp model.OpenSkyTrackResponse
+	if err := c.fetchJSON(ctx, fmt.Sprintf("%s/tracks/all?%s", c.baseURL, query.Encode()), &trackResp); err != nil {
+		return nil, err
+	}
+
+	track := c.ConvertToFlightTrack(&trackResp)
+	c.logger.Debug("Fetched track for %s with %d waypoints", icao24, len(track.Waypoints))
+
+	return track, nil
+}
+
+// maxFlightsWindow is the longest begin/end interval OpenSky accepts for
+// airport arrival and departure queries
+const maxFlightsWindow = 7 * 24 * time.Hour
+
+// FetchArrivalsByAirport fetches flights that arrived at the airport with the
+// given ICAO code between the begin and end Unix times
+func (c *OpenSkyClient) FetchArrivalsByAirport(ctx context.Context, airportICAO string, begin, end int64) ([]*model.FlightConnection, error) {
+	if airportICAO == "" {
+		return nil, fmt.Errorf("airport ICAO code is required")
+	}
+	if end <= begin {
+		return nil, fmt.Errorf("end (%d) must be after begin (%d)", end, begin)
+	}
+	if window := time.Duration(end-begin) * time.Second; window > maxFlightsWindow {
+		return nil, fmt.Errorf("time window of %v exceeds OpenSky's limit of %v", window, maxFlightsWindow)
+	}
+
+	query := url.Values{}
+	query.Set("airport", strings.ToUpper(airportICAO))
+	query.Set("begin", strconv.FormatInt(begin, 10))
+	query.Set("end", strconv.FormatInt(end, 10))
+
+	var flights []*model.FlightConnection
+	if err := c.fetchJSON(ctx, fmt.Sprintf("%s/flights/arrival?%s", c.baseURL, query.Encode()), &flights); err != nil {
+		return nil, err
+	}
+
+	// OpenSky pads callsigns to eight characters
+	for _, flight := range flights {
+		if flight != nil {
+			flight.Callsign = strings.TrimSpace(flight.Callsign)
+		}
+	}
+
+	c.logger.Debug("Fetched %d arrivals for %s", len(flights), airportICAO)
+
+	return flights, nil
+}
+
 // fetchStates is the internal method to fetch states from a given URL
 func (c *OpenSkyClient) fetchStates(ctx context.Context, url string) (*model.OpenSkyResponse, error) {
+	if c.cache != nil {
+		if cached, ok := c.cache.get(url, time.Now()); ok {
+			if c.metrics != nil {
+				c.metrics.IncrementAPICacheHits()
+			}
+			c.logger.Debug("Serving %d cached flight states for %s", len(cached.States), url)
+			return cached, nil
+		}
+		if c.metrics != nil {
+			c.metrics.IncrementAPICacheMisses()
+		}
+	}
+
+	var openSkyResp model.OpenSkyResponse
+	if err := c.fetchJSON(ctx, url, &openSkyResp); err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Fetched %d flight states from OpenSky API", len(openSkyResp.States))
+
+	if c.cache != nil {
+		c.cache.put(url, &openSkyResp, time.Now())
+	}
+
+	return &openSkyResp, nil
+}
+
+// fetchJSON requests url and decodes the JSON response into out. Network
+// errors and 5xx/429 responses are retried with exponential backoff; other
+// failures are returned immediately.
+func (c *OpenSkyClient) fetchJSON(ctx context.Context, url string, out interface{}) error {
+	attempts := c.retryMaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			delay := c.retryDelay(attempt - 1)
+			c.logger.Warn("Retrying OpenSky request in %v (attempt %d/%d): %v", delay, attempt, attempts, lastErr)
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("retry aborted: %w", ctx.Err())
+			case <-time.After(delay):
+			}
+		}
+
+		retryable, err := c.fetchJSONOnce(ctx, url, out)
+		if err == nil {
+			return nil
+		}
+		if !retryable || ctx.Err() != nil {
+			return err
+		}
+		lastErr = err
+	}
+
+	return fmt.Errorf("giving up after %d attempts: %w", attempts, lastErr)
+}
+
+// retryDelay returns the backoff before the given retry (1-based): the base
+// delay doubled per retry, with jitter picking a point in its upper half
+func (c *OpenSkyClient) retryDelay(retry int) time.Duration {
+	delay := c.retryBaseDelay << (retry - 1)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// BackoffUntil returns when the most recent Retry-After from OpenSky expires,
+// or the zero time if it never sent one
+func (c *OpenSkyClient) BackoffUntil() time.Time {
+	until := c.backoffUntil.Load()
+	if until == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, until)
+}
+
+// setBackoffUntil records when OpenSky allows requests again
+func (c *OpenSkyClient) setBackoffUntil(until time.Time) {
+	c.backoffUntil.Store(until.UnixNano())
+	if c.metrics != nil {
+		c.metrics.SetAPIBackoffUntil(until)
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either as delay seconds
+// or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return time.Time{}, false
+		}
+		return now.Add(time.Duration(seconds) * time.Second), true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return at, true
+	}
+	return time.Time{}, false
+}
+
+// isRetryableStatus reports whether a response status indicates a transient failure
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// fetchJSONOnce performs a single request, reporting whether a failure is
+// worth retrying
+func (c *OpenSkyClient) fetchJSONOnce(ctx context.Context, url string, out interface{}) (bool, error) {
+	// Wait for a free request slot so concurrent calls share the client's quota
+	if err := c.acquireSlot(ctx); err != nil {
+		return false, fmt.Errorf("failed waiting for request slot: %w", err)
+	}
+	defer c.releaseSlot()
+
 	startTime := time.Now()
 
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		c.logger.Error("Failed to create request: %v", err)
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Add basic auth if credentials are provided
@@ -69,6 +327,9 @@ func (c *OpenSkyClient) fetchStates(ctx context.Context, url string) (*model.Ope
 	// Set headers
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "flight-event-throttler/1.0")
+	// Setting this ourselves turns off the transport's transparent
+	// decompression, so readBody handles the encoding and can count both sizes
+	req.Header.Set("Accept-Encoding", "gzip")
 
 	// Increment API request metric
 	if c.metrics != nil {
@@ -82,7 +343,7 @@ func (c *OpenSkyClient) fetchStates(ctx context.Context, url string) (*model.Ope
 		if c.metrics != nil {
 			c.metrics.IncrementAPIErrors()
 		}
-		return nil, fmt.Errorf("failed to fetch data: %w", err)
+		return true, fmt.Errorf("failed to fetch data: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -98,35 +359,94 @@ func (c *OpenSkyClient) fetchStates(ctx context.Context, url string) (*model.Ope
 		if c.metrics != nil {
 			c.metrics.IncrementAPIErrors()
 		}
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+
+		// When rate limited, wait as long as OpenSky asks instead of retrying sooner
+		if resp.StatusCode == http.StatusTooManyRequests {
+			if until, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				c.setBackoffUntil(until)
+				c.logger.Warn("OpenSky rate limit hit, backing off until %s", until.Format(time.RFC3339))
+				return false, fmt.Errorf("API returned status %d, retry after %s", resp.StatusCode, until.Format(time.RFC3339))
+			}
+		}
+
+		return isRetryableStatus(resp.StatusCode), fmt.Errorf("API returned status %d", resp.StatusCode)
 	}
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Read response body; a connection reset mid-body is as transient as one before it
+	body, err := c.readBody(resp)
 	if err != nil {
 		c.logger.Error("Failed to read response body: %v", err)
 		if c.metrics != nil {
 			c.metrics.IncrementAPIErrors()
 		}
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return true, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Parse JSON response
-	var openSkyResp model.OpenSkyResponse
-	if err := json.Unmarshal(body, &openSkyResp); err != nil {
+	if err := json.Unmarshal(body, out); err != nil {
 		c.logger.Error("Failed to parse JSON response: %v", err)
 		if c.metrics != nil {
 			c.metrics.IncrementAPIErrors()
 		}
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		return false, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
-	c.logger.Debug("Fetched %d flight states from OpenSky API in %dms", len(openSkyResp.States), latency)
+	c.logger.Debug("OpenSky request to %s completed in %dms", url, latency)
 
-	return &openSkyResp, nil
+	return false, nil
+}
+
+// readBody reads the response body, decompressing it according to its
+// Content-Encoding, and records the on-the-wire and decoded sizes
+func (c *OpenSkyClient) readBody(resp *http.Response) ([]byte, error) {
+	wire := &countingReader{r: resp.Body}
+
+	var body io.Reader = wire
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip":
+		gz, err := gzip.NewReader(wire)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		defer gz.Close()
+		body = gz
+	case "deflate":
+		zr, err := zlib.NewReader(wire)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deflate body: %w", err)
+		}
+		defer zr.Close()
+		body = zr
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.metrics != nil {
+		c.metrics.RecordAPIResponseBytes(wire.n, int64(len(data)))
+	}
+	return data, nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
 }
 
-// ConvertToFlightEvents converts OpenSky states to FlightEvent structs
+// ConvertToFlightEvents converts OpenSky states to FlightEvent structs. Rows
+// longer than the documented 17 fields are accepted and the extra fields
+// ignored; JSON nulls leave the corresponding fields unset. Rows that are too
+// short, lack an ICAO24 address, or hold a value of the wrong type are skipped
+// and counted as malformed.
 func (c *OpenSkyClient) ConvertToFlightEvents(response *model.OpenSkyResponse) []*model.FlightEvent {
 	if response == nil || len(response.States) == 0 {
 		return nil
@@ -138,93 +458,93 @@ func (c *OpenSkyClient) ConvertToFlightEvents(response *model.OpenSkyResponse) [
 		// OpenSky API returns state as array, need to map to struct
 		// State format: [icao24, callsign, origin_country, time_position, last_contact,
 		//                longitude, latitude, baro_altitude, on_ground, velocity,
-		//                true_track, vertical_rate, sensors, geo_altitude, squawk, spi, position_source]
+		//                true_track, vertical_rate, sensors, geo_altitude, squawk, spi, position_source,
+		//                category (newer responses only)]
 
 		if len(state) < 17 {
-			c.logger.Debug("Skipping incomplete state data")
+			c.skipMalformedState("truncated row with %d fields", len(state))
 			continue
 		}
 
+		row := &stateRow{values: state}
 		event := &model.FlightEvent{}
 
 		// Extract ICAO24 (index 0)
-		if icao24, ok := state[0].(string); ok {
-			event.ICAO24 = icao24
-		}
+		event.ICAO24 = row.str(0)
 
 		// Extract Callsign (index 1)
-		if callsign, ok := state[1].(string); ok {
-			event.Callsign = callsign
-		}
+		event.Callsign = row.str(1)
 
 		// Extract Origin Country (index 2)
-		if country, ok := state[2].(string); ok {
-			event.OriginCountry = country
-		}
+		event.OriginCountry = row.str(2)
 
 		// Extract Time Position (index 3)
-		if timePos, ok := state[3].(float64); ok {
-			event.TimePosition = int64(timePos)
+		if timePos := row.float(3); timePos != nil {
+			event.TimePosition = int64(*timePos)
 		}
 
 		// Extract Last Contact (index 4)
-		if lastContact, ok := state[4].(float64); ok {
-			event.LastContact = int64(lastContact)
+		if lastContact := row.float(4); lastContact != nil {
+			event.LastContact = int64(*lastContact)
 		}
 
 		// Extract Longitude (index 5)
-		if lon, ok := state[5].(float64); ok {
-			event.Longitude = &lon
-		}
+		event.Longitude = row.float(5)
 
 		// Extract Latitude (index 6)
-		if lat, ok := state[6].(float64); ok {
-			event.Latitude = &lat
-		}
+		event.Latitude = row.float(6)
 
 		// Extract Baro Altitude (index 7)
-		if alt, ok := state[7].(float64); ok {
-			event.BaroAltitude = &alt
-		}
+		event.BaroAltitude = row.float(7)
 
 		// Extract On Ground (index 8)
-		if onGround, ok := state[8].(bool); ok {
-			event.OnGround = onGround
-		}
+		event.OnGround = row.boolean(8)
 
 		// Extract Velocity (index 9)
-		if vel, ok := state[9].(float64); ok {
-			event.Velocity = &vel
-		}
+		event.Velocity = row.float(9)
 
 		// Extract True Track (index 10)
-		if track, ok := state[10].(float64); ok {
-			event.TrueTrack = &track
-		}
+		event.TrueTrack = row.float(10)
 
 		// Extract Vertical Rate (index 11)
-		if vr, ok := state[11].(float64); ok {
-			event.VerticalRate = &vr
-		}
+		event.VerticalRate = row.float(11)
+
+		// Extract Sensors (index 12), the IDs of receivers that contributed
+		event.Sensors = row.ints(12)
 
 		// Extract Geo Altitude (index 13)
-		if geoAlt, ok := state[13].(float64); ok {
-			event.GeoAltitude = &geoAlt
-		}
+		event.GeoAltitude = row.float(13)
 
 		// Extract Squawk (index 14)
-		if squawk, ok := state[14].(string); ok {
+		if squawk := row.str(14); squawk != "" {
 			event.Squawk = &squawk
 		}
 
 		// Extract SPI (index 15)
-		if spi, ok := state[15].(bool); ok {
-			event.Spi = spi
-		}
+		event.Spi = row.boolean(15)
 
 		// Extract Position Source (index 16)
-		if posSource, ok := state[16].(float64); ok {
-			event.PositionSource = int(posSource)
+		if posSource := row.float(16); posSource != nil {
+			event.PositionSource = int(*posSource)
+		}
+
+		if row.malformed {
+			c.skipMalformedState("unexpected value type in row for %q", event.ICAO24)
+			continue
+		}
+		if event.ICAO24 == "" {
+			c.skipMalformedState("row without an ICAO24 address")
+			continue
+		}
+
+		// Treat "null island" (0, 0) as missing position data
+		if c.dropNullIsland && event.Longitude != nil && event.Latitude != nil &&
+			*event.Longitude == 0 && *event.Latitude == 0 {
+			event.Longitude = nil
+			event.Latitude = nil
+			if c.metrics != nil {
+				c.metrics.IncrementNullIslandCorrected()
+			}
 		}
 
 		events = append(events, event)
@@ -235,12 +555,214 @@ func (c *OpenSkyClient) ConvertToFlightEvents(response *model.OpenSkyResponse) [
 	return events
 }
 
+// skipMalformedState logs and counts a state row that could not be converted
+func (c *OpenSkyClient) skipMalformedState(format string, args ...interface{}) {
+	c.logger.Debug("Skipping malformed state: "+format, args...)
+	if c.metrics != nil {
+		c.metrics.IncrementMalformedStates()
+	}
+}
+
+// stateRow reads typed fields from a raw OpenSky state row. JSON nulls read
+// as the zero value; any other value of an unexpected type marks the row as
+// malformed.
+type stateRow struct {
+	values    []interface{}
+	malformed bool
+}
+
+func (r *stateRow) float(i int) *float64 {
+	switch v := r.values[i].(type) {
+	case nil:
+		return nil
+	case float64:
+		return &v
+	default:
+		r.malformed = true
+		return nil
+	}
+}
+
+func (r *stateRow) str(i int) string {
+	switch v := r.values[i].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		r.malformed = true
+		return ""
+	}
+}
+
+func (r *stateRow) boolean(i int) bool {
+	switch v := r.values[i].(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	default:
+		r.malformed = true
+		return false
+	}
+}
+
+func (r *stateRow) ints(i int) []int {
+	switch v := r.values[i].(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		ids := make([]int, 0, len(v))
+		for _, item := range v {
+			id, ok := item.(float64)
+			if !ok {
+				r.malformed = true
+				return nil
+			}
+			ids = append(ids, int(id))
+		}
+		return ids
+	default:
+		r.malformed = true
+		return nil
+	}
+}
+
+// ConvertToFlightTrack converts an OpenSky track response to a FlightTrack
+func (c *OpenSkyClient) ConvertToFlightTrack(response *model.OpenSkyTrackResponse) *model.FlightTrack {
+	if response == nil {
+		return nil
+	}
+
+	track := &model.FlightTrack{
+		ICAO24:    response.ICAO24,
+		StartTime: int64(response.StartTime),
+		EndTime:   int64(response.EndTime),
+		Waypoints: make([]model.Waypoint, 0, len(response.Path)),
+	}
+	if response.Callsign != nil {
+		track.Callsign = strings.TrimSpace(*response.Callsign)
+	}
+
+	for _, point := range response.Path {
+		// Path format: [time, latitude, longitude, baro_altitude, true_track, on_ground]
+		if len(point) < 6 {
+			c.logger.Debug("Skipping incomplete track waypoint")
+			continue
+		}
+
+		var waypoint model.Waypoint
+
+		if t, ok := point[0].(float64); ok {
+			waypoint.Time = int64(t)
+		}
+
+		if lat, ok := point[1].(float64); ok {
+			waypoint.Latitude = &lat
+		}
+
+		if lon, ok := point[2].(float64); ok {
+			waypoint.Longitude = &lon
+		}
+
+		if alt, ok := point[3].(float64); ok {
+			waypoint.BaroAltitude = &alt
+		}
+
+		if heading, ok := point[4].(float64); ok {
+			waypoint.TrueTrack = &heading
+		}
+
+		if onGround, ok := point[5].(bool); ok {
+			waypoint.OnGround = onGround
+		}
+
+		track.Waypoints = append(track.Waypoints, waypoint)
+	}
+
+	return track
+}
+
+// Warmup performs up to polls rapid fetches spaced by interval, starting immediately,
+// so the buffer fills quickly after startup. It stops early once done reports true.
+func (c *OpenSkyClient) Warmup(ctx context.Context, polls int, interval time.Duration, done func() bool, callback func([]*model.FlightEvent)) {
+	if polls < 1 {
+		return
+	}
+
+	c.logger.Info("Starting warmup: up to %d polls every %v", polls, interval)
+
+	for i := 0; i < polls; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+
+		c.poll(ctx, c.FetchAllStates, callback)
+
+		if done != nil && done() {
+			c.logger.Info("Warmup target reached after %d polls", i+1)
+			return
+		}
+	}
+
+	c.logger.Info("Warmup finished after %d polls", polls)
+}
+
+// statesFetcher fetches one set of flight states for a poll
+type statesFetcher func(ctx context.Context) (*model.OpenSkyResponse, error)
+
 // PollContinuously polls the OpenSky API at regular intervals
 func (c *OpenSkyClient) PollContinuously(ctx context.Context, interval time.Duration, callback func([]*model.FlightEvent)) {
+	c.logger.Info("Starting continuous polling of OpenSky API every %v", interval)
+	c.pollLoop(ctx, interval, c.FetchAllStates, callback)
+}
+
+// ValidateBoundingBox checks that a bounding box is well-formed: minimums
+// below maximums, latitudes within [-90, 90] and longitudes within [-180, 180]
+func ValidateBoundingBox(lamin, lomin, lamax, lomax float64) error {
+	if lamin < -90 || lamax > 90 {
+		return fmt.Errorf("latitudes must be within [-90, 90]")
+	}
+	if lomin < -180 || lomax > 180 {
+		return fmt.Errorf("longitudes must be within [-180, 180]")
+	}
+	if lamin >= lamax {
+		return fmt.Errorf("lamin (%.4f) must be less than lamax (%.4f)", lamin, lamax)
+	}
+	if lomin >= lomax {
+		return fmt.Errorf("lomin (%.4f) must be less than lomax (%.4f)", lomin, lomax)
+	}
+	return nil
+}
+
+// PollBoundingBox polls the OpenSky API at regular intervals like
+// PollContinuously, but only for states within the given bounding box. The box
+// is validated once before polling starts.
+func (c *OpenSkyClient) PollBoundingBox(ctx context.Context, interval time.Duration, lamin, lomin, lamax, lomax float64, callback func([]*model.FlightEvent)) error {
+	if err := ValidateBoundingBox(lamin, lomin, lamax, lomax); err != nil {
+		return fmt.Errorf("invalid bounding box: %w", err)
+	}
+
+	c.logger.Info("Starting polling of OpenSky API every %v within [%.4f, %.4f] x [%.4f, %.4f]",
+		interval, lamin, lamax, lomin, lomax)
+	c.pollLoop(ctx, interval, func(ctx context.Context) (*model.OpenSkyResponse, error) {
+		return c.FetchStatesByBoundingBox(ctx, lamin, lomin, lamax, lomax)
+	}, callback)
+	return nil
+}
+
+// pollLoop calls fetch every interval until ctx is cancelled
+func (c *OpenSkyClient) pollLoop(ctx context.Context, interval time.Duration, fetch statesFetcher, callback func([]*model.FlightEvent)) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	c.logger.Info("Starting continuous polling of OpenSky API every %v", interval)
+	var busy atomic.Bool
+	var wg sync.WaitGroup
+	defer wg.Wait()
 
 	for {
 		select {
@@ -248,16 +770,56 @@ func (c *OpenSkyClient) PollContinuously(ctx context.Context, interval time.Dura
 			c.logger.Info("Stopping OpenSky polling")
 			return
 		case <-ticker.C:
-			response, err := c.FetchAllStates(ctx)
-			if err != nil {
-				c.logger.Error("Failed to fetch states during polling: %v", err)
+			if !c.skipIfBusy {
+				c.poll(ctx, fetch, callback)
 				continue
 			}
 
-			events := c.ConvertToFlightEvents(response)
-			if len(events) > 0 && callback != nil {
-				callback(events)
+			// Skip this tick rather than stacking requests behind a slow fetch
+			if !busy.CompareAndSwap(false, true) {
+				c.logger.Warn("Skipping poll: previous fetch still running")
+				if c.metrics != nil {
+					c.metrics.IncrementPollsSkipped()
+				}
+				continue
 			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer busy.Store(false)
+				c.poll(ctx, fetch, callback)
+			}()
+		}
+	}
+}
+
+// poll performs a single fetch and hands the converted events to the callback
+func (c *OpenSkyClient) poll(ctx context.Context, fetch statesFetcher, callback func([]*model.FlightEvent)) {
+	// Honor the last Retry-After rather than hammering a rate-limited API
+	if until := c.BackoffUntil(); time.Now().Before(until) {
+		c.logger.Debug("Skipping poll: rate limited by OpenSky until %s", until.Format(time.RFC3339))
+		return
+	}
+
+	response, err := fetch(ctx)
+	if err != nil {
+		c.logger.Error("Failed to fetch states during polling: %v", err)
+		return
+	}
+
+	// OpenSky only advances its snapshot time every few seconds, so a response
+	// with the same time as the last one carries data we already processed
+	if previous := c.lastStatesTime.Swap(response.Time); response.Time != 0 && response.Time == previous {
+		c.logger.Debug("Skipping poll: OpenSky snapshot time %d unchanged", response.Time)
+		if c.metrics != nil {
+			c.metrics.IncrementStalePollsSkipped()
 		}
+		return
+	}
+
+	events := c.ConvertToFlightEvents(response)
+	if len(events) > 0 && callback != nil {
+		callback(events)
 	}
 }
@@ -0,0 +1,845 @@
+package fetcher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/metrics"
+	"flight-event-throttler/internal/model"
+	"flight-event-throttler/pkg/logger"
+)
+
+// newTestClient returns a client pointed at baseURL with its own metrics
+func newTestClient(t *testing.T, baseURL string) (*OpenSkyClient, *metrics.Metrics) {
+	t.Helper()
+
+	m := metrics.NewMetrics()
+	t.Cleanup(m.Close)
+
+	return NewOpenSkyClient(baseURL, 0, "", "", logger.NewWithWriter("error", io.Discard), m), m
+}
+
+// stateRowAt builds a 17-field OpenSky state row for icao24 at lon/lat
+func stateRowAt(icao24 string, lon, lat interface{}) []interface{} {
+	return []interface{}{
+		icao24, "TEST123 ", "Testland", 1700000000.0, 1700000001.0,
+		lon, lat, 10000.0, false, 250.0,
+		90.0, 0.0, nil, 10100.0, "1000", false, 0.0,
+	}
+}
+
+// statesBody is a minimal /states/all response with a single aircraft
+const statesBody = `{"time": 1700000000, "states": [["abc123", "TEST123 ", "Testland", 1700000000, 1700000001, 8.5, 47.4, 10000, false, 250, 90, 0, null, 10100, "1000", false, 0]]}`
+
+// failingServer responds with status to the first failures requests and with
+// statesBody afterwards, counting every request
+func failingServer(t *testing.T, failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		io.WriteString(w, statesBody)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestFetchRetriesTransientFailures(t *testing.T) {
+	server, requests := failingServer(t, 2, http.StatusServiceUnavailable)
+	client, m := newTestClient(t, server.URL)
+	client.SetRetry(3, time.Millisecond)
+
+	response, err := client.FetchAllStates(context.Background())
+	if err != nil {
+		t.Fatalf("FetchAllStates: %v", err)
+	}
+	if len(response.States) != 1 {
+		t.Errorf("got %d states, want 1", len(response.States))
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("server saw %d requests, want 3", got)
+	}
+	if got := m.GetAPIErrors(); got != 2 {
+		t.Errorf("API errors = %d, want 2", got)
+	}
+}
+
+func TestFetchGivesUpAfterMaxAttempts(t *testing.T) {
+	server, requests := failingServer(t, 5, http.StatusBadGateway)
+	client, _ := newTestClient(t, server.URL)
+	client.SetRetry(2, time.Millisecond)
+
+	if _, err := client.FetchAllStates(context.Background()); err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server saw %d requests, want 2", got)
+	}
+}
+
+func TestFetchDoesNotRetryClientErrors(t *testing.T) {
+	server, requests := failingServer(t, 1, http.StatusNotFound)
+	client, _ := newTestClient(t, server.URL)
+	client.SetRetry(3, time.Millisecond)
+
+	if _, err := client.FetchAllStates(context.Background()); err == nil {
+		t.Fatal("expected an error for a 404")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+}
+
+func TestFetchRetryAbortsOnCancel(t *testing.T) {
+	server, requests := failingServer(t, 5, http.StatusServiceUnavailable)
+	client, _ := newTestClient(t, server.URL)
+	client.SetRetry(5, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := client.FetchAllStates(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Time
+		ok    bool
+	}{
+		{"120", now.Add(2 * time.Minute), true},
+		{"Mon, 01 Jan 2024 12:05:00 GMT", now.Add(5 * time.Minute), true},
+		{"", time.Time{}, false},
+		{"-5", time.Time{}, false},
+		{"soon", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPollWaitsForRetryAfter(t *testing.T) {
+	for _, retryAfter := range []string{"60", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)} {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		client, m := newTestClient(t, server.URL)
+		client.SetRetry(3, time.Millisecond)
+
+		client.poll(context.Background(), client.FetchAllStates, nil)
+		client.poll(context.Background(), client.FetchAllStates, nil)
+		server.Close()
+
+		if got := requests.Load(); got != 1 {
+			t.Errorf("Retry-After %q: server saw %d requests, want 1 (no retries or polls during backoff)", retryAfter, got)
+		}
+		until := client.BackoffUntil()
+		if wait := time.Until(until); wait < 50*time.Second || wait > 61*time.Second {
+			t.Errorf("Retry-After %q: backoff until %v, want about a minute from now", retryAfter, until)
+		}
+		if got := m.GetAPIBackoffUntil(); got != until.Unix() {
+			t.Errorf("Retry-After %q: metrics backoff = %d, want %d", retryAfter, got, until.Unix())
+		}
+	}
+}
+
+func TestPollResumesAfterBackoff(t *testing.T) {
+	server, requests := failingServer(t, 0, http.StatusOK)
+	client, _ := newTestClient(t, server.URL)
+	client.setBackoffUntil(time.Now().Add(-time.Second))
+
+	polled := 0
+	client.poll(context.Background(), client.FetchAllStates, func(events []*model.FlightEvent) { polled += len(events) })
+	if requests.Load() != 1 || polled != 1 {
+		t.Errorf("requests = %d, events = %d; want a normal poll once the backoff has passed", requests.Load(), polled)
+	}
+}
+
+// fixtureServer serves the named testdata file for requests to path,
+// capturing the query of the last request
+func fixtureServer(t *testing.T, path, fixture string) (*httptest.Server, *atomic.Value) {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var query atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		query.Store(r.URL.Query())
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &query
+}
+
+func TestFetchFlightTrack(t *testing.T) {
+	server, query := fixtureServer(t, "/tracks/all", "track.json")
+	client, _ := newTestClient(t, server.URL)
+
+	track, err := client.FetchFlightTrack(context.Background(), "3C4B26", 1689193028)
+	if err != nil {
+		t.Fatalf("FetchFlightTrack: %v", err)
+	}
+
+	params := query.Load().(url.Values)
+	if params.Get("icao24") != "3c4b26" || params.Get("time") != "1689193028" {
+		t.Errorf("query = %v, want icao24=3c4b26 and time=1689193028", params)
+	}
+	if track.ICAO24 != "3c4b26" || track.Callsign != "DLH9LF" || track.StartTime != 1689193028 || track.EndTime != 1689196208 {
+		t.Errorf("track = %+v", track)
+	}
+
+	// The truncated last waypoint is skipped
+	if len(track.Waypoints) != 3 {
+		t.Fatalf("got %d waypoints, want 3", len(track.Waypoints))
+	}
+	first := track.Waypoints[0]
+	if first.Time != 1689193028 || *first.Latitude != 50.0334 || *first.Longitude != 8.5706 || !first.OnGround {
+		t.Errorf("first waypoint = %+v", first)
+	}
+	if *track.Waypoints[1].BaroAltitude != 914 || *track.Waypoints[1].TrueTrack != 248 {
+		t.Errorf("second waypoint = %+v", track.Waypoints[1])
+	}
+	if track.Waypoints[2].BaroAltitude != nil || track.Waypoints[2].TrueTrack != nil {
+		t.Errorf("nulls should decode as nil, got %+v", track.Waypoints[2])
+	}
+}
+
+func TestFetchArrivalsByAirport(t *testing.T) {
+	server, query := fixtureServer(t, "/flights/arrival", "arrivals.json")
+	client, _ := newTestClient(t, server.URL)
+
+	flights, err := client.FetchArrivalsByAirport(context.Background(), "eddf", 1689188000, 1689195000)
+	if err != nil {
+		t.Fatalf("FetchArrivalsByAirport: %v", err)
+	}
+
+	params := query.Load().(url.Values)
+	if params.Get("airport") != "EDDF" || params.Get("begin") != "1689188000" || params.Get("end") != "1689195000" {
+		t.Errorf("query = %v", params)
+	}
+	if len(flights) != 2 {
+		t.Fatalf("got %d flights, want 2", len(flights))
+	}
+	want := model.FlightConnection{ICAO24: "4b1814", FirstSeen: 1689188400, EstDepartureAirport: "LSZH", LastSeen: 1689192000, EstArrivalAirport: "EDDF", Callsign: "SWR18A"}
+	if *flights[0] != want {
+		t.Errorf("first flight = %+v, want %+v", *flights[0], want)
+	}
+	if flights[1].EstDepartureAirport != "" || flights[1].Callsign != "DLH4AB" {
+		t.Errorf("second flight = %+v, want no departure airport and a trimmed callsign", *flights[1])
+	}
+}
+
+func TestFetchArrivalsValidation(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+	client, _ := newTestClient(t, server.URL)
+
+	tests := []struct {
+		name       string
+		airport    string
+		begin, end int64
+	}{
+		{"missing airport", "", 0, 3600},
+		{"end before begin", "EDDF", 3600, 0},
+		{"empty window", "EDDF", 3600, 3600},
+		{"window too long", "EDDF", 0, int64((8 * 24 * time.Hour).Seconds())},
+	}
+	for _, tt := range tests {
+		if _, err := client.FetchArrivalsByAirport(context.Background(), tt.airport, tt.begin, tt.end); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("invalid queries reached the server %d times", got)
+	}
+}
+
+func TestConvertAcceptsEighteenFieldRows(t *testing.T) {
+	client, m := newTestClient(t, "")
+	row := append(stateRowAt("abc123", 8.5, 47.4), 4.0) // Trailing category field
+
+	events := client.ConvertToFlightEvents(&model.OpenSkyResponse{States: [][]interface{}{row}})
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	if events[0].ICAO24 != "abc123" || *events[0].Longitude != 8.5 || *events[0].Latitude != 47.4 {
+		t.Errorf("event = %+v", events[0])
+	}
+	if got := m.GetMalformedStates(); got != 0 {
+		t.Errorf("malformed states = %d, want 0", got)
+	}
+}
+
+func TestConvertLeavesNullFieldsNil(t *testing.T) {
+	client, m := newTestClient(t, "")
+	row := stateRowAt("abc123", nil, nil)
+	row[7], row[9], row[11], row[13], row[14] = nil, nil, nil, nil, nil
+
+	events := client.ConvertToFlightEvents(&model.OpenSkyResponse{States: [][]interface{}{row}})
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	event := events[0]
+	if event.Longitude != nil || event.Latitude != nil || event.BaroAltitude != nil || event.Velocity != nil ||
+		event.VerticalRate != nil || event.GeoAltitude != nil || event.Squawk != nil {
+		t.Errorf("null fields should stay nil, got %+v", event)
+	}
+	if event.TrueTrack == nil || *event.TrueTrack != 90 {
+		t.Errorf("true_track = %v, want 90", event.TrueTrack)
+	}
+	if got := m.GetMalformedStates(); got != 0 {
+		t.Errorf("malformed states = %d, want 0", got)
+	}
+}
+
+func TestConvertSkipsMalformedRows(t *testing.T) {
+	client, m := newTestClient(t, "")
+	wrongType := stateRowAt("bad001", 8.5, 47.4)
+	wrongType[7] = "high"
+	noICAO := stateRowAt("", 8.5, 47.4)
+
+	events := client.ConvertToFlightEvents(&model.OpenSkyResponse{States: [][]interface{}{
+		stateRowAt("abc123", 8.5, 47.4)[:10], // Truncated
+		wrongType,
+		noICAO,
+		stateRowAt("good01", 8.5, 47.4),
+	}})
+
+	if len(events) != 1 || events[0].ICAO24 != "good01" {
+		t.Errorf("events = %+v, want only good01", events)
+	}
+	if got := m.GetMalformedStates(); got != 3 {
+		t.Errorf("malformed states = %d, want 3", got)
+	}
+}
+
+func TestPollBoundingBoxPassesQuery(t *testing.T) {
+	queries := make(chan url.Values, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.Query()
+		io.WriteString(w, statesBody)
+	}))
+	defer server.Close()
+	client, _ := newTestClient(t, server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan int, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.PollBoundingBox(ctx, 10*time.Millisecond, 45.8, 5.9, 47.8, 10.5, func(batch []*model.FlightEvent) {
+			events <- len(batch)
+		})
+	}()
+
+	select {
+	case query := <-queries:
+		want := map[string]string{"lamin": "45.8000", "lomin": "5.9000", "lamax": "47.8000", "lomax": "10.5000"}
+		for key, value := range want {
+			if got := query.Get(key); got != value {
+				t.Errorf("%s = %q, want %q", key, got, value)
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no request was made")
+	}
+	select {
+	case n := <-events:
+		if n != 1 {
+			t.Errorf("callback got %d events, want 1", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("callback was not called")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("PollBoundingBox: %v", err)
+	}
+}
+
+func TestPollBoundingBoxRejectsInvalidBox(t *testing.T) {
+	client, _ := newTestClient(t, "http://127.0.0.1:0")
+
+	boxes := [][4]float64{
+		{47.8, 5.9, 45.8, 10.5},  // lamin above lamax
+		{45.8, 10.5, 47.8, 5.9},  // lomin above lomax
+		{-91, 5.9, 47.8, 10.5},   // latitude out of range
+		{45.8, -181, 47.8, 10.5}, // longitude out of range
+	}
+	for _, box := range boxes {
+		err := client.PollBoundingBox(context.Background(), time.Millisecond, box[0], box[1], box[2], box[3], nil)
+		if err == nil {
+			t.Errorf("box %v: expected a validation error", box)
+		}
+	}
+}
+
+func TestPollSkipsUnchangedSnapshotTime(t *testing.T) {
+	times := []int64{1700000000, 1700000000, 1700000010}
+	var served atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := served.Add(1) - 1
+		fmt.Fprintf(w, `{"time": %d, "states": [["abc123", "TEST123 ", "Testland", 1, 1, 8.5, 47.4, 10000, false, 250, 90, 0, null, 10100, "1000", false, 0]]}`, times[i])
+	}))
+	defer server.Close()
+	client, m := newTestClient(t, server.URL)
+
+	calls := 0
+	for range times {
+		client.poll(context.Background(), client.FetchAllStates, func([]*model.FlightEvent) { calls++ })
+	}
+
+	if calls != 2 {
+		t.Errorf("callback fired %d times, want 2", calls)
+	}
+	if got := m.GetStalePollsSkipped(); got != 1 {
+		t.Errorf("stale polls skipped = %d, want 1", got)
+	}
+}
+
+// compressedStatesServer serves a multi-aircraft /states/all body encoded as
+// encoding ("" for none), failing the request if the client didn't ask for gzip
+func compressedStatesServer(t *testing.T, encoding string) (*httptest.Server, int) {
+	t.Helper()
+
+	rows := make([]string, 50)
+	for i := range rows {
+		rows[i] = fmt.Sprintf(`["a%05d", "TEST123 ", "Testland", 1700000000, 1700000001, 8.5, 47.4, 10000, false, 250, 90, 0, null, 10100, "1000", false, 0]`, i)
+	}
+	body := `{"time": 1700000000, "states": [` + strings.Join(rows, ",") + `]}`
+
+	var encoded bytes.Buffer
+	switch encoding {
+	case "gzip":
+		w := gzip.NewWriter(&encoded)
+		io.WriteString(w, body)
+		w.Close()
+	case "deflate":
+		w := zlib.NewWriter(&encoded)
+		io.WriteString(w, body)
+		w.Close()
+	default:
+		encoded.WriteString(body)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("Accept-Encoding = %q, want gzip", r.Header.Get("Accept-Encoding"))
+		}
+		if encoding != "" {
+			w.Header().Set("Content-Encoding", encoding)
+		}
+		w.Write(encoded.Bytes())
+	}))
+	t.Cleanup(server.Close)
+	return server, len(body)
+}
+
+func TestFetchDecodesCompressedResponses(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate", ""} {
+		name := encoding
+		if name == "" {
+			name = "identity"
+		}
+		t.Run(name, func(t *testing.T) {
+			server, size := compressedStatesServer(t, encoding)
+			client, m := newTestClient(t, server.URL)
+
+			response, err := client.FetchAllStates(context.Background())
+			if err != nil {
+				t.Fatalf("FetchAllStates() error = %v", err)
+			}
+			if len(response.States) != 50 {
+				t.Fatalf("decoded %d states, want 50", len(response.States))
+			}
+			if got := response.States[49][0]; got != "a00049" {
+				t.Errorf("last state icao24 = %v, want a00049", got)
+			}
+
+			if got := m.GetAPIBytesDecoded(); got != int64(size) {
+				t.Errorf("decoded bytes = %d, want %d", got, size)
+			}
+			wire := m.GetAPIBytesWire()
+			if encoding == "" && wire != int64(size) {
+				t.Errorf("wire bytes = %d, want %d for an uncompressed body", wire, size)
+			}
+			if encoding != "" && wire >= int64(size) {
+				t.Errorf("wire bytes = %d, want fewer than the %d decoded bytes", wire, size)
+			}
+		})
+	}
+}
+
+func TestFetchRejectsCorruptGzip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		io.WriteString(w, statesBody)
+	}))
+	defer server.Close()
+	client, _ := newTestClient(t, server.URL)
+
+	if _, err := client.FetchAllStates(context.Background()); err == nil {
+		t.Fatal("FetchAllStates() succeeded on a body that isn't gzip")
+	}
+}
+
+func TestFetchServesCachedStatesWithinTTL(t *testing.T) {
+	server, requests := failingServer(t, 0, http.StatusOK)
+	client, m := newTestClient(t, server.URL)
+	client.SetCacheTTL(100 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.FetchAllStates(context.Background()); err != nil {
+			t.Fatalf("FetchAllStates() #%d error = %v", i+1, err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("server saw %d requests within the TTL, want 1", got)
+	}
+	if hits, misses := m.GetAPICacheHits(), m.GetAPICacheMisses(); hits != 1 || misses != 1 {
+		t.Errorf("cache hits/misses = %d/%d, want 1/1", hits, misses)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if _, err := client.FetchAllStates(context.Background()); err != nil {
+		t.Fatalf("FetchAllStates() after expiry error = %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server saw %d requests after the TTL expired, want 2", got)
+	}
+	if misses := m.GetAPICacheMisses(); misses != 2 {
+		t.Errorf("cache misses = %d, want 2", misses)
+	}
+}
+
+func TestFetchCacheDisabledByZeroTTL(t *testing.T) {
+	server, requests := failingServer(t, 0, http.StatusOK)
+	client, m := newTestClient(t, server.URL)
+	client.SetCacheTTL(time.Minute)
+	client.SetCacheTTL(0)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.FetchAllStates(context.Background()); err != nil {
+			t.Fatalf("FetchAllStates() #%d error = %v", i+1, err)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server saw %d requests with the cache disabled, want 2", got)
+	}
+	if hits := m.GetAPICacheHits(); hits != 0 {
+		t.Errorf("cache hits = %d, want 0", hits)
+	}
+}
+
+func TestResponseCacheKeyedByURL(t *testing.T) {
+	cache := newResponseCache(time.Minute)
+	now := time.Now()
+	response := &model.OpenSkyResponse{Time: 1700000000}
+	cache.put("https://example.com/states/all", response, now)
+
+	if got, ok := cache.get("https://example.com/states/all", now.Add(time.Second)); !ok || got != response {
+		t.Errorf("get(same URL) = %v, %v; want the cached response", got, ok)
+	}
+	if _, ok := cache.get("https://example.com/states/all?lamin=45", now); ok {
+		t.Error("get(other URL) hit the cache")
+	}
+	if _, ok := cache.get("https://example.com/states/all", now.Add(time.Minute)); ok {
+		t.Error("get() at the expiry time hit the cache")
+	}
+}
+
+// advancingServer serves statesBody-like snapshots with a new time on every
+// request, so no poll is skipped as stale, counting every request
+func advancingServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		fmt.Fprintf(w, `{"time": %d, "states": [["abc123", "TEST123 ", "Testland", 1, 1, 8.5, 47.4, 10000, false, 250, 90, 0, null, 10100, "1000", false, 0]]}`, 1700000000+n)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestWarmupRunsAllPolls(t *testing.T) {
+	server, requests := advancingServer(t)
+	client, _ := newTestClient(t, server.URL)
+
+	polled := 0
+	client.Warmup(context.Background(), 3, time.Millisecond, func() bool { return false }, func(events []*model.FlightEvent) { polled += len(events) })
+
+	if requests.Load() != 3 || polled != 3 {
+		t.Errorf("requests = %d, events = %d; want 3 polls without reaching the target", requests.Load(), polled)
+	}
+}
+
+func TestWarmupStopsAtTarget(t *testing.T) {
+	server, requests := advancingServer(t)
+	client, _ := newTestClient(t, server.URL)
+
+	polled := 0
+	done := func() bool { return polled >= 2 }
+	client.Warmup(context.Background(), 5, time.Millisecond, done, func(events []*model.FlightEvent) { polled += len(events) })
+
+	if requests.Load() != 2 {
+		t.Errorf("requests = %d, want 2 (stop once the target is reached)", requests.Load())
+	}
+}
+
+func TestWarmupDisabled(t *testing.T) {
+	server, requests := advancingServer(t)
+	client, _ := newTestClient(t, server.URL)
+
+	client.Warmup(context.Background(), 0, time.Millisecond, nil, func([]*model.FlightEvent) {
+		t.Error("callback fired with warmup disabled")
+	})
+
+	if requests.Load() != 0 {
+		t.Errorf("requests = %d, want 0", requests.Load())
+	}
+}
+
+func TestWarmupStopsOnCancel(t *testing.T) {
+	server, requests := advancingServer(t)
+	client, _ := newTestClient(t, server.URL)
+
+	// The first poll is immediate; cancelling during the interval ends warmup
+	ctx, cancel := context.WithCancel(context.Background())
+	returned := make(chan struct{})
+	go func() {
+		defer close(returned)
+		client.Warmup(ctx, 5, time.Hour, nil, func([]*model.FlightEvent) { cancel() })
+	}()
+
+	select {
+	case <-returned:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Warmup did not return after ctx was cancelled")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("requests = %d, want 1", requests.Load())
+	}
+}
+
+// blockingServer holds every request until release is closed, tracking how
+// many requests are in progress at once
+func blockingServer(t *testing.T) (server *httptest.Server, release chan struct{}, active, peak *atomic.Int32) {
+	t.Helper()
+
+	release = make(chan struct{})
+	active, peak = new(atomic.Int32), new(atomic.Int32)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		io.WriteString(w, statesBody)
+	}))
+	t.Cleanup(server.Close)
+	return server, release, active, peak
+}
+
+// waitForActive waits until the server is handling n requests
+func waitForActive(t *testing.T, active *atomic.Int32, n int32) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for active.Load() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("server handling %d requests, want %d", active.Load(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMaxConcurrentRequestsBoundsInFlight(t *testing.T) {
+	server, release, active, peak := blockingServer(t)
+	client, m := newTestClient(t, server.URL)
+	client.SetMaxConcurrentRequests(2)
+
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		go func() {
+			_, err := client.FetchAllStates(context.Background())
+			errs <- err
+		}()
+	}
+
+	waitForActive(t, active, 2)
+	time.Sleep(20 * time.Millisecond)
+	if got := m.GetAPIInFlight(); got != 2 {
+		t.Errorf("in-flight gauge = %d, want 2 while the slots are taken", got)
+	}
+
+	close(release)
+	for i := 0; i < 5; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("FetchAllStates() error = %v", err)
+		}
+	}
+
+	if got := peak.Load(); got != 2 {
+		t.Errorf("peak concurrent requests = %d, want 2", got)
+	}
+	if got := m.GetAPIInFlight(); got != 0 {
+		t.Errorf("in-flight gauge = %d after all fetches, want 0", got)
+	}
+}
+
+func TestMaxConcurrentRequestsWaitRespectsCancel(t *testing.T) {
+	server, release, active, _ := blockingServer(t)
+	defer close(release)
+	client, _ := newTestClient(t, server.URL)
+	client.SetMaxConcurrentRequests(1)
+
+	go client.FetchAllStates(context.Background())
+	waitForActive(t, active, 1)
+
+	// The second fetch queues for the only slot and gives up on cancel
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.FetchAllStates(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FetchAllStates() error = %v, want the context deadline", err)
+	}
+	if got := active.Load(); got != 1 {
+		t.Errorf("server handling %d requests, want 1 (the queued fetch never sent)", got)
+	}
+}
+
+func TestMaxConcurrentRequestsUnbounded(t *testing.T) {
+	server, release, active, _ := blockingServer(t)
+	client, m := newTestClient(t, server.URL)
+	client.SetMaxConcurrentRequests(2)
+	client.SetMaxConcurrentRequests(0)
+
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		go func() {
+			_, err := client.FetchAllStates(context.Background())
+			errs <- err
+		}()
+	}
+
+	waitForActive(t, active, 4)
+	if got := m.GetAPIInFlight(); got != 0 {
+		t.Errorf("in-flight gauge = %d, want 0 without a bound", got)
+	}
+	close(release)
+	for i := 0; i < 4; i++ {
+		<-errs
+	}
+}
+
+func TestSkipIfBusySkipsTicksDuringSlowFetch(t *testing.T) {
+	server, release, active, peak := blockingServer(t)
+	client, m := newTestClient(t, server.URL)
+	client.SetSkipIfBusy(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	polled := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.PollContinuously(ctx, 5*time.Millisecond, func([]*model.FlightEvent) { polled <- struct{}{} })
+	}()
+
+	// Ticks keep firing while the first fetch is held
+	waitForActive(t, active, 1)
+	deadline := time.Now().Add(2 * time.Second)
+	for m.GetPollsSkipped() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("polls skipped = %d, want ticks skipped while the fetch is running", m.GetPollsSkipped())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	close(release)
+	select {
+	case <-polled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("slow fetch never delivered its events")
+	}
+	cancel()
+	<-done
+
+	if got := peak.Load(); got != 1 {
+		t.Errorf("peak concurrent requests = %d, want 1", got)
+	}
+}
+
+func TestSkipIfBusyDisabledPollsInline(t *testing.T) {
+	server, release, active, _ := blockingServer(t)
+	client, m := newTestClient(t, server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.PollContinuously(ctx, 5*time.Millisecond, nil)
+	}()
+
+	// The loop waits on the fetch itself, so nothing is counted as skipped
+	waitForActive(t, active, 1)
+	time.Sleep(30 * time.Millisecond)
+	if got := m.GetPollsSkipped(); got != 0 {
+		t.Errorf("polls skipped = %d, want 0 with skip_if_busy off", got)
+	}
+
+	close(release)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("PollContinuously did not return after ctx was cancelled")
+	}
+}
@@ -0,0 +1,124 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"flight-event-throttler/internal/model"
+	"flight-event-throttler/pkg/logger"
+)
+
+// StateSource is a feed of aircraft states in OpenSky's format. OpenSkyClient
+// is the production implementation; other ADS-B feeds can be plugged into the
+// pipeline by implementing it.
+type StateSource interface {
+	// FetchAllStates returns the current states of all aircraft
+	FetchAllStates(ctx context.Context) (*model.OpenSkyResponse, error)
+
+	// PollContinuously fetches states every interval and passes the converted
+	// events to callback until ctx is cancelled
+	PollContinuously(ctx context.Context, interval time.Duration, callback func([]*model.FlightEvent))
+}
+
+// BoundingBoxSource is a StateSource that can poll just a geographic region
+type BoundingBoxSource interface {
+	StateSource
+
+	// PollBoundingBox polls like PollContinuously, limited to the bounding
+	// box. It returns an error without polling if the box is invalid.
+	PollBoundingBox(ctx context.Context, interval time.Duration, lamin, lomin, lamax, lomax float64, callback func([]*model.FlightEvent)) error
+}
+
+// WarmupSource is a StateSource that can fill the pipeline with rapid polls
+// on startup
+type WarmupSource interface {
+	StateSource
+
+	// Warmup polls up to polls times, interval apart, stopping early once
+	// done reports true
+	Warmup(ctx context.Context, polls int, interval time.Duration, done func() bool, callback func([]*model.FlightEvent))
+}
+
+var (
+	_ StateSource       = (*OpenSkyClient)(nil)
+	_ BoundingBoxSource = (*OpenSkyClient)(nil)
+	_ WarmupSource      = (*OpenSkyClient)(nil)
+)
+
+// ErrSourceExhausted is returned by MockSource once every response has been replayed
+var ErrSourceExhausted = errors.New("mock source has no more responses")
+
+// MockSource is a StateSource that replays a fixed sequence of responses, one
+// per fetch, for tests and local development
+type MockSource struct {
+	responses []*model.OpenSkyResponse
+	next      int
+	converter *OpenSkyClient // Converts states exactly as the OpenSky client does
+	logger    logger.Interface
+	mu        sync.Mutex
+}
+
+// NewMockSource creates a source that replays responses in order
+func NewMockSource(responses []*model.OpenSkyResponse, log logger.Interface) *MockSource {
+	return &MockSource{
+		responses: responses,
+		converter: NewOpenSkyClient("", 0, "", "", log, nil),
+		logger:    log,
+	}
+}
+
+// FetchAllStates returns the next response, or ErrSourceExhausted once all
+// responses have been replayed
+func (s *MockSource) FetchAllStates(ctx context.Context) (*model.OpenSkyResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.next >= len(s.responses) {
+		return nil, ErrSourceExhausted
+	}
+	response := s.responses[s.next]
+	s.next++
+	return response, nil
+}
+
+// PollContinuously replays one response per interval until ctx is cancelled
+// or the responses run out
+func (s *MockSource) PollContinuously(ctx context.Context, interval time.Duration, callback func([]*model.FlightEvent)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			response, err := s.FetchAllStates(ctx)
+			if errors.Is(err, ErrSourceExhausted) {
+				s.logger.Info("Mock source exhausted, stopping polling")
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			events := s.converter.ConvertToFlightEvents(response)
+			if len(events) > 0 && callback != nil {
+				callback(events)
+			}
+		}
+	}
+}
+
+// Remaining returns how many responses have not been replayed yet
+func (s *MockSource) Remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.responses) - s.next
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// EndpointStats summarizes the requests served by a single HTTP endpoint
+type EndpointStats struct {
+	Requests     int64   `json:"requests"`
+	Errors       int64   `json:"errors"` // Responses with status 400 or above
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+}
+
+// endpointCounters holds the running totals for one endpoint
+type endpointCounters struct {
+	requests   atomic.Int64
+	errors     atomic.Int64
+	latencySum atomic.Int64
+}
+
+// RecordHTTPRequest records a request served by the endpoint at path with
+// its final status code and latency
+func (m *Metrics) RecordHTTPRequest(path string, status int, latencyMs int64) {
+	value, ok := m.endpoints.Load(path)
+	if !ok {
+		value, _ = m.endpoints.LoadOrStore(path, &endpointCounters{})
+	}
+	counters := value.(*endpointCounters)
+
+	counters.requests.Add(1)
+	counters.latencySum.Add(latencyMs)
+	if status >= http.StatusBadRequest {
+		counters.errors.Add(1)
+	}
+}
+
+// GetEndpointStats returns per-endpoint request statistics keyed by path
+func (m *Metrics) GetEndpointStats() map[string]EndpointStats {
+	stats := make(map[string]EndpointStats)
+	m.endpoints.Range(func(key, value any) bool {
+		counters := value.(*endpointCounters)
+		requests := counters.requests.Load()
+
+		entry := EndpointStats{
+			Requests: requests,
+			Errors:   counters.errors.Load(),
+		}
+		if requests > 0 {
+			entry.AvgLatencyMs = float64(counters.latencySum.Load()) / float64(requests)
+		}
+		stats[key.(string)] = entry
+		return true
+	})
+	return stats
+}
+
+// resetEndpoints clears all per-endpoint statistics
+func (m *Metrics) resetEndpoints() {
+	m.endpoints.Range(func(key, _ any) bool {
+		m.endpoints.Delete(key)
+		return true
+	})
+}
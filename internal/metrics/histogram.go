@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync/atomic"
+)
+
+// DefaultAPILatencyBuckets are the default upper bounds, in milliseconds, of
+// the API latency histogram buckets
+var DefaultAPILatencyBuckets = []int64{10, 50, 100, 250, 500, 1000, 2500}
+
+// LatencyBucket is the number of samples that fell into a single histogram
+// bucket. Counts are per bucket, not cumulative.
+type LatencyBucket struct {
+	LE    string `json:"le"` // Upper bound in milliseconds, or "+Inf"
+	Count int64  `json:"count"`
+}
+
+// latencyHistogram counts samples per bucket with one atomic counter each.
+// The last counter holds samples above the highest bound.
+type latencyHistogram struct {
+	bounds []int64
+	counts []atomic.Int64
+}
+
+func newLatencyHistogram(bounds []int64) *latencyHistogram {
+	return &latencyHistogram{
+		bounds: append([]int64(nil), bounds...),
+		counts: make([]atomic.Int64, len(bounds)+1),
+	}
+}
+
+// observe records a single sample
+func (h *latencyHistogram) observe(latencyMs int64) {
+	i := sort.Search(len(h.bounds), func(i int) bool { return latencyMs <= h.bounds[i] })
+	h.counts[i].Add(1)
+}
+
+// buckets returns the per-bucket counts
+func (h *latencyHistogram) buckets() []LatencyBucket {
+	buckets := make([]LatencyBucket, 0, len(h.counts))
+	for i := range h.counts {
+		le := "+Inf"
+		if i < len(h.bounds) {
+			le = strconv.FormatInt(h.bounds[i], 10)
+		}
+		buckets = append(buckets, LatencyBucket{LE: le, Count: h.counts[i].Load()})
+	}
+	return buckets
+}
+
+// restore loads counts saved from a histogram with the same bounds. It
+// reports false, leaving the counts untouched, when the bounds differ.
+func (h *latencyHistogram) restore(buckets []LatencyBucket) bool {
+	current := h.buckets()
+	if len(buckets) != len(current) {
+		return false
+	}
+	for i := range buckets {
+		if buckets[i].LE != current[i].LE {
+			return false
+		}
+	}
+
+	for i := range buckets {
+		h.counts[i].Store(buckets[i].Count)
+	}
+	return true
+}
+
+// SetAPILatencyBuckets replaces the API latency histogram bucket bounds, in
+// milliseconds. Bounds must be positive and strictly increasing. Samples
+// recorded so far are discarded.
+func (m *Metrics) SetAPILatencyBuckets(bounds []int64) error {
+	if len(bounds) == 0 {
+		return fmt.Errorf("at least one latency bucket is required")
+	}
+	for i, bound := range bounds {
+		if bound <= 0 || (i > 0 && bound <= bounds[i-1]) {
+			return fmt.Errorf("latency buckets must be positive and strictly increasing")
+		}
+	}
+
+	m.apiLatencyHist.Store(newLatencyHistogram(bounds))
+	return nil
+}
+
+// GetAPILatencyBuckets returns the number of API latency samples per bucket
+func (m *Metrics) GetAPILatencyBuckets() []LatencyBucket {
+	return m.apiLatencyHist.Load().buckets()
+}
+
+// GetAPILatencyPercentile estimates the p-th percentile (0-100) of API
+// latency in milliseconds, interpolating linearly within the bucket the
+// percentile falls in. Samples above the highest bound are reported as that
+// bound. Returns 0 when no samples have been recorded.
+func (m *Metrics) GetAPILatencyPercentile(p float64) float64 {
+	h := m.apiLatencyHist.Load()
+
+	counts := make([]int64, len(h.counts))
+	total := int64(0)
+	for i := range h.counts {
+		counts[i] = h.counts[i].Load()
+		total += counts[i]
+	}
+	if total == 0 {
+		return 0
+	}
+
+	p = math.Max(0, math.Min(100, p))
+	rank := math.Max(1, math.Ceil(p/100*float64(total)))
+
+	cumulative := int64(0)
+	for i, count := range counts {
+		if count == 0 || float64(cumulative+count) < rank {
+			cumulative += count
+			continue
+		}
+		if i == len(h.bounds) {
+			return float64(h.bounds[len(h.bounds)-1])
+		}
+
+		lower := 0.0
+		if i > 0 {
+			lower = float64(h.bounds[i-1])
+		}
+		upper := float64(h.bounds[i])
+		return lower + (upper-lower)*(rank-float64(cumulative))/float64(count)
+	}
+
+	return float64(h.bounds[len(h.bounds)-1])
+}
+
+// resetAPILatencyHistogram clears all histogram counts, keeping the bounds
+func (m *Metrics) resetAPILatencyHistogram() {
+	m.apiLatencyHist.Store(newLatencyHistogram(m.apiLatencyHist.Load().bounds))
+}
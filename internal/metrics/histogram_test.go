@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestAPILatencyPercentiles(t *testing.T) {
+	m := newTestMetrics(t)
+
+	// 98 fast requests and 2 slow ones
+	for i := 0; i < 98; i++ {
+		m.RecordAPILatency(30)
+	}
+	m.RecordAPILatency(800)
+	m.RecordAPILatency(900)
+
+	if p50 := m.GetAPILatencyPercentile(50); p50 <= 10 || p50 > 50 {
+		t.Errorf("p50 = %v, want within the 10-50ms bucket", p50)
+	}
+	if p99 := m.GetAPILatencyPercentile(99); p99 <= 500 || p99 > 1000 {
+		t.Errorf("p99 = %v, want within the 500-1000ms bucket", p99)
+	}
+
+	buckets := m.GetAPILatencyBuckets()
+	if buckets[1].LE != "50" || buckets[1].Count != 98 || buckets[5].LE != "1000" || buckets[5].Count != 2 {
+		t.Errorf("buckets = %+v, want 98 in le=50 and 2 in le=1000", buckets)
+	}
+}
+
+func TestAPILatencyPercentileWithoutSamples(t *testing.T) {
+	m := newTestMetrics(t)
+
+	if got := m.GetAPILatencyPercentile(99); got != 0 {
+		t.Errorf("p99 = %v, want 0 without samples", got)
+	}
+}
+
+// promValue returns the value of a sample line in Prometheus text output
+func promValue(t *testing.T, out, series string) int64 {
+	t.Helper()
+
+	match := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(series) + ` (\d+)$`).FindStringSubmatch(out)
+	if match == nil {
+		t.Fatalf("series %s not found", series)
+	}
+	value, _ := strconv.ParseInt(match[1], 10, 64)
+	return value
+}
+
+func TestPrometheusLatencyCountMatchesInfBucket(t *testing.T) {
+	m := newTestMetrics(t)
+	m.RecordAPILatency(20)
+	m.RecordAPILatency(5000)
+
+	var buf bytes.Buffer
+	if err := m.WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	inf := promValue(t, out, `flight_throttler_api_latency_ms_bucket{le="+Inf"}`)
+	count := promValue(t, out, "flight_throttler_api_latency_ms_count")
+	if inf != 2 || count != inf {
+		t.Errorf("+Inf bucket = %d, count = %d; want both 2", inf, count)
+	}
+	if sum := promValue(t, out, "flight_throttler_api_latency_ms_sum"); sum != 5020 {
+		t.Errorf("sum = %d, want 5020", sum)
+	}
+}
+
+func TestPersistenceRestoresLatencyHistogram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	src := newTestMetrics(t)
+	src.RecordAPILatency(20)
+	src.RecordAPILatency(300)
+	if err := src.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newTestMetrics(t)
+	if err := dst.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := dst.GetAPIAverageLatency(); got != 160 {
+		t.Errorf("average latency = %v, want 160", got)
+	}
+	var total int64
+	for _, bucket := range dst.GetAPILatencyBuckets() {
+		total += bucket.Count
+	}
+	if total != 2 {
+		t.Errorf("restored %d histogram samples, want 2", total)
+	}
+}
+
+func TestPersistenceSkipsLatencyWithDifferentBuckets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	src := newTestMetrics(t)
+	src.RecordAPILatency(20)
+	if err := src.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newTestMetrics(t)
+	if err := dst.SetAPILatencyBuckets([]int64{100, 1000}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Load(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restoring the count without the matching buckets would break the histogram
+	if got := dst.GetAPIAverageLatency(); got != 0 {
+		t.Errorf("average latency = %v, want 0 when buckets changed", got)
+	}
+}
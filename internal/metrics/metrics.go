@@ -13,11 +13,18 @@ type Metrics struct {
 	eventsProcessed   atomic.Int64
 	eventsDropped     atomic.Int64
 	eventsFailed      atomic.Int64
+	eventsRejected    atomic.Int64
+	eventsThrottled   atomic.Int64 // Dropped by the per-aircraft throttle
+	runDropped        atomic.Int64 // Dropped since startup; neither restored nor reset
 
 	// Rate metrics
 	eventsPerSecond   atomic.Int64
 	lastSecondCount   atomic.Int64
 	lastSecondTime    atomic.Int64
+	lastTick          atomic.Int64 // Unix time of the last rate ticker run
+	eventsRate        atomic.Pointer[rateWindow] // Per-second samples for the smoothed rate
+	droppedRate       atomic.Pointer[rateWindow] // Per-second dropped counts for the recent drop rate
+	lastSecondDropped atomic.Int64
 
 	// Buffer metrics
 	bufferSize        atomic.Int64
@@ -28,20 +35,55 @@ type Metrics struct {
 	apiErrors         atomic.Int64
 	apiLatencySum     atomic.Int64
 	apiLatencyCount   atomic.Int64
+	apiLatencyHist    atomic.Pointer[latencyHistogram]
+	apiInFlight       atomic.Int64
+	apiBytesWire      atomic.Int64 // Response bytes as received, possibly compressed
+	apiBytesDecoded   atomic.Int64 // Response bytes after decompression
+	apiCacheHits      atomic.Int64
+	apiCacheMisses    atomic.Int64
+	apiBackoffUntil   atomic.Int64 // Unix time until which OpenSky asked us to back off
+	lastPollSuccess   atomic.Int64 // Unix time of the last successful poll
+	pollsSkipped      atomic.Int64
+	stalePolls        atomic.Int64 // Polls whose snapshot time matched the previous poll
+	nullIslandFixed   atomic.Int64
+	malformedStates   atomic.Int64 // State rows skipped for a bad shape or value type
+	apiRequestsRate   atomic.Pointer[rateWindow] // Per-second samples for the recent API error rate
+	apiErrorsRate     atomic.Pointer[rateWindow]
+	lastSecondAPIRequests atomic.Int64
+	lastSecondAPIErrors   atomic.Int64
+
+	// Rate limiter metrics
+	tokensFn          func() float64 // Reports available rate limiter tokens; nil when unset
 
 	// HTTP metrics
 	httpRequests      atomic.Int64
 	httpErrors        atomic.Int64
+	streamDropped     atomic.Int64 // Events not delivered to lagging stream clients
+	endpoints         sync.Map // Path to *endpointCounters
+
+	// Sink metrics
+	sinkRetries       atomic.Int64 // Sink writes retried after a failure
+	sinkDeadLettered  atomic.Int64 // Events written to a dead-letter file
+	sinkDropped       atomic.Int64 // Events a sink lost to a full queue or failed delivery
 
 	startTime         time.Time
+	previousUptime    time.Duration // Uptime restored from earlier runs
 	mu                sync.RWMutex
+	done              chan struct{} // Closed to stop the rate ticker
+	closeOnce         sync.Once
 }
 
-// NewMetrics creates a new metrics collector
+// NewMetrics creates a new metrics collector. It starts a background rate
+// ticker, so callers that don't live for the whole process (such as tests)
+// should defer Close.
 func NewMetrics() *Metrics {
 	m := &Metrics{
 		startTime: time.Now(),
+		done:      make(chan struct{}),
 	}
+	m.lastTick.Store(m.startTime.Unix())
+	m.apiLatencyHist.Store(newLatencyHistogram(DefaultAPILatencyBuckets))
+	m.storeRateWindows(DefaultRateWindow)
 
 	// Start background ticker to calculate events per second
 	go m.calculateRateMetrics()
@@ -49,24 +91,46 @@ func NewMetrics() *Metrics {
 	return m
 }
 
+// Close stops the background rate ticker. It is safe to call more than once;
+// events_per_second stops updating afterwards.
+func (m *Metrics) Close() {
+	m.closeOnce.Do(func() {
+		close(m.done)
+	})
+}
+
 // Event metrics methods
 
 func (m *Metrics) IncrementEventsReceived() {
 	m.eventsReceived.Add(1)
 }
 
+// AddEventsReceived records a batch of n events received at once
+func (m *Metrics) AddEventsReceived(n int64) {
+	m.eventsReceived.Add(n)
+}
+
 func (m *Metrics) IncrementEventsProcessed() {
 	m.eventsProcessed.Add(1)
 }
 
 func (m *Metrics) IncrementEventsDropped() {
 	m.eventsDropped.Add(1)
+	m.runDropped.Add(1)
 }
 
 func (m *Metrics) IncrementEventsFailed() {
 	m.eventsFailed.Add(1)
 }
 
+func (m *Metrics) IncrementEventsRejected() {
+	m.eventsRejected.Add(1)
+}
+
+func (m *Metrics) IncrementEventsThrottled() {
+	m.eventsThrottled.Add(1)
+}
+
 func (m *Metrics) GetEventsReceived() int64 {
 	return m.eventsReceived.Load()
 }
@@ -79,10 +143,38 @@ func (m *Metrics) GetEventsDropped() int64 {
 	return m.eventsDropped.Load()
 }
 
+// GetRunEventsDropped returns the events dropped since this process started.
+// Unlike GetEventsDropped it ignores counts restored by Load and is not
+// zeroed by Reset.
+func (m *Metrics) GetRunEventsDropped() int64 {
+	return m.runDropped.Load()
+}
+
 func (m *Metrics) GetEventsFailed() int64 {
 	return m.eventsFailed.Load()
 }
 
+func (m *Metrics) GetEventsRejected() int64 {
+	return m.eventsRejected.Load()
+}
+
+func (m *Metrics) GetEventsThrottled() int64 {
+	return m.eventsThrottled.Load()
+}
+
+// GetDropRate returns the fraction of events submitted to the rate limiter
+// that were dropped, dropped / (processed + dropped), or 0 before any events
+// have been submitted. Events rejected or throttled before submission are not
+// counted, so the rate matches GetRecentDropRate over the whole run.
+func (m *Metrics) GetDropRate() float64 {
+	dropped := m.eventsDropped.Load()
+	attempted := m.eventsProcessed.Load() + dropped
+	if attempted == 0 {
+		return 0
+	}
+	return float64(dropped) / float64(attempted)
+}
+
 // Rate metrics methods
 
 func (m *Metrics) GetEventsPerSecond() int64 {
@@ -93,16 +185,41 @@ func (m *Metrics) calculateRateMetrics() {
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+		}
+
 		currentProcessed := m.eventsProcessed.Load()
 		lastCount := m.lastSecondCount.Load()
 
 		rate := currentProcessed - lastCount
 		m.eventsPerSecond.Store(rate)
+		m.eventsRate.Load().record(rate)
 		m.lastSecondCount.Store(currentProcessed)
+
+		m.droppedRate.Load().record(sampleDelta(&m.eventsDropped, &m.lastSecondDropped))
+		m.apiRequestsRate.Load().record(sampleDelta(&m.apiRequests, &m.lastSecondAPIRequests))
+		m.apiErrorsRate.Load().record(sampleDelta(&m.apiErrors, &m.lastSecondAPIErrors))
+
+		// Heartbeat so a dead ticker can be detected
+		m.lastTick.Store(time.Now().Unix())
 	}
 }
 
+// GetLastTick returns when the rate ticker last ran
+func (m *Metrics) GetLastTick() time.Time {
+	return time.Unix(m.lastTick.Load(), 0)
+}
+
+// IsRateTickerStale reports whether the rate ticker hasn't run within maxAge,
+// meaning events_per_second is frozen
+func (m *Metrics) IsRateTickerStale(maxAge time.Duration) bool {
+	return time.Since(m.GetLastTick()) > maxAge
+}
+
 // Buffer metrics methods
 
 func (m *Metrics) SetBufferSize(size int64) {
@@ -130,6 +247,29 @@ func (m *Metrics) GetBufferUtilization() float64 {
 	return (size / capacity) * 100
 }
 
+// Rate limiter metrics methods
+
+// SetRateLimiterTokensFunc registers a function reporting the rate limiter's
+// available tokens, sampled whenever a snapshot is taken
+func (m *Metrics) SetRateLimiterTokensFunc(fn func() float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokensFn = fn
+}
+
+// GetRateLimiterTokens returns the rate limiter's available tokens, or 0 if
+// no source is registered
+func (m *Metrics) GetRateLimiterTokens() float64 {
+	m.mu.RLock()
+	fn := m.tokensFn
+	m.mu.RUnlock()
+
+	if fn == nil {
+		return 0
+	}
+	return fn()
+}
+
 // API metrics methods
 
 func (m *Metrics) IncrementAPIRequests() {
@@ -143,6 +283,104 @@ func (m *Metrics) IncrementAPIErrors() {
 func (m *Metrics) RecordAPILatency(latencyMs int64) {
 	m.apiLatencySum.Add(latencyMs)
 	m.apiLatencyCount.Add(1)
+	m.apiLatencyHist.Load().observe(latencyMs)
+}
+
+// RecordAPIResponseBytes records the size of an upstream response body as
+// received and after decompression
+func (m *Metrics) RecordAPIResponseBytes(wire, decoded int64) {
+	m.apiBytesWire.Add(wire)
+	m.apiBytesDecoded.Add(decoded)
+}
+
+func (m *Metrics) GetAPIBytesWire() int64 {
+	return m.apiBytesWire.Load()
+}
+
+func (m *Metrics) GetAPIBytesDecoded() int64 {
+	return m.apiBytesDecoded.Load()
+}
+
+func (m *Metrics) IncrementAPICacheHits() {
+	m.apiCacheHits.Add(1)
+}
+
+func (m *Metrics) IncrementAPICacheMisses() {
+	m.apiCacheMisses.Add(1)
+}
+
+func (m *Metrics) GetAPICacheHits() int64 {
+	return m.apiCacheHits.Load()
+}
+
+func (m *Metrics) GetAPICacheMisses() int64 {
+	return m.apiCacheMisses.Load()
+}
+
+func (m *Metrics) IncrementAPIInFlight() {
+	m.apiInFlight.Add(1)
+}
+
+func (m *Metrics) DecrementAPIInFlight() {
+	m.apiInFlight.Add(-1)
+}
+
+func (m *Metrics) GetAPIInFlight() int64 {
+	return m.apiInFlight.Load()
+}
+
+// SetAPIBackoffUntil records when a rate-limited upstream API accepts requests again
+func (m *Metrics) SetAPIBackoffUntil(until time.Time) {
+	m.apiBackoffUntil.Store(until.Unix())
+}
+
+// GetAPIBackoffUntil returns the Unix time of the last upstream Retry-After,
+// or 0 if none has been received
+func (m *Metrics) GetAPIBackoffUntil() int64 {
+	return m.apiBackoffUntil.Load()
+}
+
+// SetLastSuccessfulPoll records when a poll last completed successfully
+func (m *Metrics) SetLastSuccessfulPoll(at time.Time) {
+	m.lastPollSuccess.Store(at.Unix())
+}
+
+// GetLastSuccessfulPoll returns the Unix time of the last successful poll,
+// or 0 if no poll has succeeded yet
+func (m *Metrics) GetLastSuccessfulPoll() int64 {
+	return m.lastPollSuccess.Load()
+}
+
+func (m *Metrics) IncrementPollsSkipped() {
+	m.pollsSkipped.Add(1)
+}
+
+func (m *Metrics) GetPollsSkipped() int64 {
+	return m.pollsSkipped.Load()
+}
+
+func (m *Metrics) IncrementStalePollsSkipped() {
+	m.stalePolls.Add(1)
+}
+
+func (m *Metrics) GetStalePollsSkipped() int64 {
+	return m.stalePolls.Load()
+}
+
+func (m *Metrics) IncrementNullIslandCorrected() {
+	m.nullIslandFixed.Add(1)
+}
+
+func (m *Metrics) GetNullIslandCorrected() int64 {
+	return m.nullIslandFixed.Load()
+}
+
+func (m *Metrics) IncrementMalformedStates() {
+	m.malformedStates.Add(1)
+}
+
+func (m *Metrics) GetMalformedStates() int64 {
+	return m.malformedStates.Load()
 }
 
 func (m *Metrics) GetAPIRequests() int64 {
@@ -162,6 +400,16 @@ func (m *Metrics) GetAPIAverageLatency() float64 {
 	return float64(sum) / float64(count)
 }
 
+// GetAPIErrorRate returns the fraction of upstream API requests that failed,
+// or 0 before any requests have been made
+func (m *Metrics) GetAPIErrorRate() float64 {
+	requests := m.apiRequests.Load()
+	if requests == 0 {
+		return 0
+	}
+	return float64(m.apiErrors.Load()) / float64(requests)
+}
+
 // HTTP metrics methods
 
 func (m *Metrics) IncrementHTTPRequests() {
@@ -180,6 +428,40 @@ func (m *Metrics) GetHTTPErrors() int64 {
 	return m.httpErrors.Load()
 }
 
+func (m *Metrics) IncrementStreamEventsDropped() {
+	m.streamDropped.Add(1)
+}
+
+func (m *Metrics) GetStreamEventsDropped() int64 {
+	return m.streamDropped.Load()
+}
+
+// Sink metrics methods
+
+func (m *Metrics) IncrementSinkRetries() {
+	m.sinkRetries.Add(1)
+}
+
+func (m *Metrics) GetSinkRetries() int64 {
+	return m.sinkRetries.Load()
+}
+
+func (m *Metrics) AddSinkDeadLettered(n int64) {
+	m.sinkDeadLettered.Add(n)
+}
+
+func (m *Metrics) GetSinkDeadLettered() int64 {
+	return m.sinkDeadLettered.Load()
+}
+
+func (m *Metrics) AddSinkDropped(n int64) {
+	m.sinkDropped.Add(n)
+}
+
+func (m *Metrics) GetSinkDropped() int64 {
+	return m.sinkDropped.Load()
+}
+
 // General metrics methods
 
 func (m *Metrics) GetUptime() time.Duration {
@@ -188,22 +470,53 @@ func (m *Metrics) GetUptime() time.Duration {
 	return time.Since(m.startTime)
 }
 
+// GetCumulativeUptime returns the uptime of this run plus any restored from earlier runs
+func (m *Metrics) GetCumulativeUptime() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.previousUptime + time.Since(m.startTime)
+}
+
 func (m *Metrics) Reset() {
 	m.eventsReceived.Store(0)
 	m.eventsProcessed.Store(0)
 	m.eventsDropped.Store(0)
 	m.eventsFailed.Store(0)
+	m.eventsRejected.Store(0)
+	m.eventsThrottled.Store(0)
 	m.eventsPerSecond.Store(0)
 	m.lastSecondCount.Store(0)
+	m.eventsRate.Load().reset()
+	m.lastSecondDropped.Store(0)
+	m.droppedRate.Load().reset()
+	m.lastSecondAPIRequests.Store(0)
+	m.lastSecondAPIErrors.Store(0)
+	m.apiRequestsRate.Load().reset()
+	m.apiErrorsRate.Load().reset()
 	m.apiRequests.Store(0)
 	m.apiErrors.Store(0)
 	m.apiLatencySum.Store(0)
 	m.apiLatencyCount.Store(0)
+	m.apiBytesWire.Store(0)
+	m.apiBytesDecoded.Store(0)
+	m.apiCacheHits.Store(0)
+	m.apiCacheMisses.Store(0)
+	m.resetAPILatencyHistogram()
+	m.pollsSkipped.Store(0)
+	m.stalePolls.Store(0)
+	m.nullIslandFixed.Store(0)
+	m.malformedStates.Store(0)
 	m.httpRequests.Store(0)
 	m.httpErrors.Store(0)
+	m.streamDropped.Store(0)
+	m.resetEndpoints()
+	m.sinkRetries.Store(0)
+	m.sinkDeadLettered.Store(0)
+	m.sinkDropped.Store(0)
 
 	m.mu.Lock()
 	m.startTime = time.Now()
+	m.previousUptime = 0
 	m.mu.Unlock()
 }
 
@@ -214,24 +527,57 @@ type Snapshot struct {
 	EventsProcessed   int64   `json:"events_processed"`
 	EventsDropped     int64   `json:"events_dropped"`
 	EventsFailed      int64   `json:"events_failed"`
+	EventsRejected    int64   `json:"events_rejected"`
+	EventsThrottled   int64   `json:"events_throttled_per_aircraft"`
 	EventsPerSecond   int64   `json:"events_per_second"`
+	SmoothedEventsPerSecond float64 `json:"events_per_second_smoothed"`
+	DropRate          float64 `json:"drop_rate"`
+	RecentDropRate    float64 `json:"drop_rate_recent"`
 
 	// Buffer metrics
 	BufferSize        int64   `json:"buffer_size"`
 	BufferCapacity    int64   `json:"buffer_capacity"`
 	BufferUtilization float64 `json:"buffer_utilization_percent"`
 
+	// Rate limiter metrics
+	RateLimiterTokens float64 `json:"rate_limiter_tokens"`
+
 	// API metrics
 	APIRequests       int64   `json:"api_requests"`
 	APIErrors         int64   `json:"api_errors"`
+	APIErrorRate      float64 `json:"api_error_rate"`
+	RecentAPIErrorRate float64 `json:"api_error_rate_recent"`
 	APIAvgLatency     float64 `json:"api_avg_latency_ms"`
+	APILatencyP50     float64 `json:"api_latency_p50_ms"`
+	APILatencyP99     float64 `json:"api_latency_p99_ms"`
+	APILatencyBuckets []LatencyBucket `json:"api_latency_buckets"`
+	APIInFlight       int64   `json:"api_in_flight"`
+	APIBytesWire      int64   `json:"api_response_bytes_wire"`
+	APIBytesDecoded   int64   `json:"api_response_bytes_decoded"`
+	APICacheHits      int64   `json:"api_cache_hits"`
+	APICacheMisses    int64   `json:"api_cache_misses"`
+	APIBackoffUntil   int64   `json:"api_backoff_until_unix"`
+	LastPollSuccess   int64   `json:"last_successful_poll_unix"`
+	PollsSkipped      int64   `json:"polls_skipped"`
+	StalePolls        int64   `json:"stale_polls_skipped"`
+	NullIslandFixed   int64   `json:"null_island_corrected"`
+	MalformedStates   int64   `json:"malformed_states_skipped"`
 
 	// HTTP metrics
 	HTTPRequests      int64   `json:"http_requests"`
 	HTTPErrors        int64   `json:"http_errors"`
+	StreamDropped     int64   `json:"stream_events_dropped"`
+	Endpoints         map[string]EndpointStats `json:"endpoints"`
+
+	// Sink metrics
+	SinkRetries       int64   `json:"sink_retries"`
+	SinkDeadLettered  int64   `json:"sink_dead_lettered"`
+	SinkDropped       int64   `json:"sink_dropped"`
 
 	// System metrics
+	MetricsLastTick   int64   `json:"metrics_last_tick_unix"`
 	UptimeSeconds     int64   `json:"uptime_seconds"`
+	CumulativeUptime  int64   `json:"cumulative_uptime_seconds"`
 	Timestamp         int64   `json:"timestamp"`
 }
 
@@ -242,16 +588,45 @@ func (m *Metrics) GetSnapshot() *Snapshot {
 		EventsProcessed:   m.GetEventsProcessed(),
 		EventsDropped:     m.GetEventsDropped(),
 		EventsFailed:      m.GetEventsFailed(),
+		EventsRejected:    m.GetEventsRejected(),
+		EventsThrottled:   m.GetEventsThrottled(),
 		EventsPerSecond:   m.GetEventsPerSecond(),
+		SmoothedEventsPerSecond: m.GetSmoothedEventsPerSecond(),
+		DropRate:          m.GetDropRate(),
+		RecentDropRate:    m.GetRecentDropRate(),
 		BufferSize:        m.GetBufferSize(),
 		BufferCapacity:    m.GetBufferCapacity(),
 		BufferUtilization: m.GetBufferUtilization(),
+		RateLimiterTokens: m.GetRateLimiterTokens(),
 		APIRequests:       m.GetAPIRequests(),
 		APIErrors:         m.GetAPIErrors(),
+		APIErrorRate:      m.GetAPIErrorRate(),
+		RecentAPIErrorRate: m.GetRecentAPIErrorRate(),
 		APIAvgLatency:     m.GetAPIAverageLatency(),
+		APILatencyP50:     m.GetAPILatencyPercentile(50),
+		APILatencyP99:     m.GetAPILatencyPercentile(99),
+		APILatencyBuckets: m.GetAPILatencyBuckets(),
+		APIInFlight:       m.GetAPIInFlight(),
+		APIBytesWire:      m.GetAPIBytesWire(),
+		APIBytesDecoded:   m.GetAPIBytesDecoded(),
+		APICacheHits:      m.GetAPICacheHits(),
+		APICacheMisses:    m.GetAPICacheMisses(),
+		APIBackoffUntil:   m.GetAPIBackoffUntil(),
+		LastPollSuccess:   m.GetLastSuccessfulPoll(),
+		PollsSkipped:      m.GetPollsSkipped(),
+		StalePolls:        m.GetStalePollsSkipped(),
+		NullIslandFixed:   m.GetNullIslandCorrected(),
+		MalformedStates:   m.GetMalformedStates(),
 		HTTPRequests:      m.GetHTTPRequests(),
 		HTTPErrors:        m.GetHTTPErrors(),
+		StreamDropped:     m.GetStreamEventsDropped(),
+		Endpoints:         m.GetEndpointStats(),
+		SinkRetries:       m.GetSinkRetries(),
+		SinkDeadLettered:  m.GetSinkDeadLettered(),
+		SinkDropped:       m.GetSinkDropped(),
+		MetricsLastTick:   m.lastTick.Load(),
 		UptimeSeconds:     int64(m.GetUptime().Seconds()),
+		CumulativeUptime:  int64(m.GetCumulativeUptime().Seconds()),
 		Timestamp:         time.Now().Unix(),
 	}
 }
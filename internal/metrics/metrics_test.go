@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// newTestMetrics returns a collector that is closed when the test ends
+func newTestMetrics(t *testing.T) *Metrics {
+	t.Helper()
+
+	m := NewMetrics()
+	t.Cleanup(m.Close)
+	return m
+}
+
+// waitFor polls cond until it holds or the timeout passes
+func waitFor(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return cond()
+}
+
+func TestRateTickerFreshOnStart(t *testing.T) {
+	m := newTestMetrics(t)
+
+	if m.IsRateTickerStale(5 * time.Second) {
+		t.Error("a new collector should not report a stale rate ticker")
+	}
+}
+
+func TestRateTickerStaleWhenHeartbeatOld(t *testing.T) {
+	m := newTestMetrics(t)
+	m.lastTick.Store(time.Now().Add(-time.Minute).Unix())
+
+	if !m.IsRateTickerStale(5 * time.Second) {
+		t.Error("expected a stale rate ticker after a minute without a heartbeat")
+	}
+}
+
+func TestRateTickerRefreshesHeartbeat(t *testing.T) {
+	m := newTestMetrics(t)
+	m.lastTick.Store(time.Now().Add(-time.Minute).Unix())
+
+	if !waitFor(3*time.Second, func() bool { return !m.IsRateTickerStale(5 * time.Second) }) {
+		t.Error("rate ticker did not refresh its heartbeat")
+	}
+}
+
+func TestRecentDropRateForgetsOldDrops(t *testing.T) {
+	m := newTestMetrics(t)
+	if err := m.SetRateWindow(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	m.IncrementEventsProcessed()
+	m.IncrementEventsDropped()
+	if !waitFor(3*time.Second, func() bool { return m.GetRecentDropRate() == 0.5 }) {
+		t.Fatalf("recent drop rate = %v, want 0.5", m.GetRecentDropRate())
+	}
+
+	// A quiet second pushes the drops out of a one-second window
+	if !waitFor(3*time.Second, func() bool { return m.GetRecentDropRate() == 0 }) {
+		t.Errorf("recent drop rate = %v after the window passed, want 0", m.GetRecentDropRate())
+	}
+}
+
+func TestDropRate(t *testing.T) {
+	m := newTestMetrics(t)
+	if got := m.GetDropRate(); got != 0 {
+		t.Errorf("drop rate with no events = %v, want 0", got)
+	}
+
+	// One poll of 10 events: 2 rejected up front, 6 processed, 2 dropped
+	m.AddEventsReceived(10)
+	for i := 0; i < 2; i++ {
+		m.IncrementEventsRejected()
+		m.IncrementEventsDropped()
+	}
+	for i := 0; i < 6; i++ {
+		m.IncrementEventsProcessed()
+	}
+
+	if got := m.GetEventsReceived(); got != 10 {
+		t.Errorf("events received = %d, want 10", got)
+	}
+	if got := m.GetDropRate(); got != 0.25 {
+		t.Errorf("drop rate = %v, want 0.25", got)
+	}
+	if got := m.GetSnapshot().DropRate; got != 0.25 {
+		t.Errorf("snapshot drop rate = %v, want 0.25", got)
+	}
+}
+
+func TestAPIErrorRate(t *testing.T) {
+	m := newTestMetrics(t)
+	if got := m.GetAPIErrorRate(); got != 0 {
+		t.Errorf("API error rate with no requests = %v, want 0", got)
+	}
+
+	for i := 0; i < 4; i++ {
+		m.IncrementAPIRequests()
+	}
+	m.IncrementAPIErrors()
+
+	if got := m.GetAPIErrorRate(); got != 0.25 {
+		t.Errorf("API error rate = %v, want 0.25", got)
+	}
+}
+
+func TestCloseStopsRateTicker(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	m := NewMetrics()
+	if runtime.NumGoroutine() <= before {
+		t.Fatal("NewMetrics should start the rate ticker goroutine")
+	}
+	m.Close()
+	m.Close() // Safe to call twice
+
+	if !waitFor(2*time.Second, func() bool { return runtime.NumGoroutine() <= before }) {
+		t.Errorf("goroutines = %d after Close, want %d", runtime.NumGoroutine(), before)
+	}
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// persistedState is the on-disk representation of cumulative counters
+type persistedState struct {
+	EventsReceived    int64           `json:"events_received"`
+	EventsProcessed   int64           `json:"events_processed"`
+	EventsDropped     int64           `json:"events_dropped"`
+	EventsFailed      int64           `json:"events_failed"`
+	EventsRejected    int64           `json:"events_rejected"`
+	EventsThrottled   int64           `json:"events_throttled_per_aircraft"`
+	APIRequests       int64           `json:"api_requests"`
+	APIErrors         int64           `json:"api_errors"`
+	APILatencySum     int64           `json:"api_latency_sum_ms"`
+	APILatencyCount   int64           `json:"api_latency_count"`
+	APILatencyBuckets []LatencyBucket `json:"api_latency_buckets"`
+	HTTPRequests      int64           `json:"http_requests"`
+	HTTPErrors        int64           `json:"http_errors"`
+	CumulativeUptime  int64           `json:"cumulative_uptime_seconds"`
+	SavedAt           int64           `json:"saved_at"`
+}
+
+// Save writes the cumulative counters to a file so they survive restarts.
+// The file is written atomically via a temporary file and rename.
+func (m *Metrics) Save(path string) error {
+	state := persistedState{
+		EventsReceived:    m.eventsReceived.Load(),
+		EventsProcessed:   m.eventsProcessed.Load(),
+		EventsDropped:     m.eventsDropped.Load(),
+		EventsFailed:      m.eventsFailed.Load(),
+		EventsRejected:    m.eventsRejected.Load(),
+		EventsThrottled:   m.eventsThrottled.Load(),
+		APIRequests:       m.apiRequests.Load(),
+		APIErrors:         m.apiErrors.Load(),
+		APILatencySum:     m.apiLatencySum.Load(),
+		APILatencyCount:   m.apiLatencyCount.Load(),
+		APILatencyBuckets: m.GetAPILatencyBuckets(),
+		HTTPRequests:      m.httpRequests.Load(),
+		HTTPErrors:        m.httpErrors.Load(),
+		CumulativeUptime:  int64(m.GetCumulativeUptime().Seconds()),
+		SavedAt:           time.Now().Unix(),
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace metrics file: %w", err)
+	}
+
+	return nil
+}
+
+// Load restores cumulative counters previously written by Save.
+// Uptime for the current run is not affected; the restored uptime is
+// tracked separately and reported as cumulative uptime.
+func (m *Metrics) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read metrics file: %w", err)
+	}
+
+	var state persistedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse metrics file: %w", err)
+	}
+
+	m.eventsReceived.Store(state.EventsReceived)
+	m.eventsProcessed.Store(state.EventsProcessed)
+	m.eventsDropped.Store(state.EventsDropped)
+	m.eventsFailed.Store(state.EventsFailed)
+	m.eventsRejected.Store(state.EventsRejected)
+	m.eventsThrottled.Store(state.EventsThrottled)
+	m.apiRequests.Store(state.APIRequests)
+	m.apiErrors.Store(state.APIErrors)
+	// The latency sum and count are only restored along with the histogram,
+	// so the Prometheus histogram stays self-consistent. Files from older
+	// versions, or saved with different bucket bounds, start latency afresh.
+	if m.apiLatencyHist.Load().restore(state.APILatencyBuckets) {
+		m.apiLatencySum.Store(state.APILatencySum)
+		m.apiLatencyCount.Store(state.APILatencyCount)
+	}
+	m.httpRequests.Store(state.HTTPRequests)
+	m.httpErrors.Store(state.HTTPErrors)
+
+	// Avoid reporting the restored total as a one-second rate spike
+	m.lastSecondCount.Store(state.EventsProcessed)
+	m.lastSecondDropped.Store(state.EventsDropped)
+	m.lastSecondAPIRequests.Store(state.APIRequests)
+	m.lastSecondAPIErrors.Store(state.APIErrors)
+
+	m.mu.Lock()
+	m.previousUptime = time.Duration(state.CumulativeUptime) * time.Second
+	m.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+
+	src := newTestMetrics(t)
+	src.AddEventsReceived(10)
+	for i := 0; i < 7; i++ {
+		src.IncrementEventsProcessed()
+	}
+	for i := 0; i < 3; i++ {
+		src.IncrementEventsDropped()
+	}
+	src.IncrementEventsFailed()
+	src.IncrementEventsRejected()
+	src.IncrementEventsThrottled()
+	src.IncrementAPIRequests()
+	src.IncrementAPIRequests()
+	src.IncrementAPIErrors()
+	src.IncrementHTTPRequests()
+	src.IncrementHTTPErrors()
+	src.previousUptime = time.Hour
+
+	if err := src.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+
+	dst := newTestMetrics(t)
+	if err := dst.Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	counters := []struct {
+		name      string
+		got, want int64
+	}{
+		{"events_received", dst.GetEventsReceived(), 10},
+		{"events_processed", dst.GetEventsProcessed(), 7},
+		{"events_dropped", dst.GetEventsDropped(), 3},
+		{"events_failed", dst.GetEventsFailed(), 1},
+		{"events_rejected", dst.eventsRejected.Load(), 1},
+		{"events_throttled", dst.eventsThrottled.Load(), 1},
+		{"api_requests", dst.apiRequests.Load(), 2},
+		{"api_errors", dst.apiErrors.Load(), 1},
+		{"http_requests", dst.httpRequests.Load(), 1},
+		{"http_errors", dst.httpErrors.Load(), 1},
+	}
+	for _, c := range counters {
+		if c.got != c.want {
+			t.Errorf("%s = %d after Load, want %d", c.name, c.got, c.want)
+		}
+	}
+	if uptime := dst.GetCumulativeUptime(); uptime < time.Hour {
+		t.Errorf("cumulative uptime = %v, want at least the restored hour", uptime)
+	}
+}
+
+func TestLoadMissingOrCorruptFile(t *testing.T) {
+	m := newTestMetrics(t)
+	dir := t.TempDir()
+
+	if err := m.Load(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Load of a missing file succeeded")
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Load(corrupt); err == nil {
+		t.Error("Load of a corrupt file succeeded")
+	}
+}
+
+func TestRunEventsDroppedIgnoresRestoredAndReset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+
+	previous := newTestMetrics(t)
+	for i := 0; i < 100; i++ {
+		previous.IncrementEventsDropped()
+	}
+	if err := previous.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	m := newTestMetrics(t)
+	if err := m.Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := m.GetRunEventsDropped(); got != 0 {
+		t.Errorf("GetRunEventsDropped() = %d after Load, want 0", got)
+	}
+
+	m.IncrementEventsDropped()
+	m.IncrementEventsDropped()
+	m.Reset()
+	m.IncrementEventsDropped()
+
+	if got := m.GetEventsDropped(); got != 1 {
+		t.Errorf("GetEventsDropped() = %d, want 1 since the reset", got)
+	}
+	if got := m.GetRunEventsDropped(); got != 3 {
+		t.Errorf("GetRunEventsDropped() = %d, want all 3 drops of this run", got)
+	}
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// prometheusPrefix is prepended to every exported metric name
+const prometheusPrefix = "flight_throttler_"
+
+// WritePrometheus renders the current metrics in the Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	snapshot := m.GetSnapshot()
+
+	// Event metrics
+	writeMetric(bw, "events_received_total", "counter", "Total number of events received from the feed.", float64(snapshot.EventsReceived))
+	writeMetric(bw, "events_processed_total", "counter", "Total number of events accepted for processing.", float64(snapshot.EventsProcessed))
+	writeMetric(bw, "events_dropped_total", "counter", "Total number of events dropped.", float64(snapshot.EventsDropped))
+	writeMetric(bw, "events_failed_total", "counter", "Total number of events that failed processing.", float64(snapshot.EventsFailed))
+	writeMetric(bw, "events_rejected_total", "counter", "Total number of events rejected for timestamps outside the acceptance window.", float64(snapshot.EventsRejected))
+	writeMetric(bw, "events_throttled_per_aircraft_total", "counter", "Total number of updates dropped by the per-aircraft throttle.", float64(snapshot.EventsThrottled))
+	writeMetric(bw, "events_per_second", "gauge", "Events processed during the last second.", float64(snapshot.EventsPerSecond))
+	writeMetric(bw, "events_per_second_smoothed", "gauge", "Events processed per second averaged over the rate window.", snapshot.SmoothedEventsPerSecond)
+	writeMetric(bw, "drop_rate", "gauge", "Fraction of events submitted to the rate limiter that were dropped.", snapshot.DropRate)
+
+	// Buffer metrics
+	writeMetric(bw, "buffer_size", "gauge", "Number of events currently buffered.", float64(snapshot.BufferSize))
+	writeMetric(bw, "buffer_capacity", "gauge", "Maximum number of events the buffer can hold.", float64(snapshot.BufferCapacity))
+	writeMetric(bw, "buffer_utilization_percent", "gauge", "Buffer utilization as a percentage of capacity.", snapshot.BufferUtilization)
+	writeMetric(bw, "rate_limiter_tokens", "gauge", "Tokens currently available in the global rate limiter (remaining burst capacity).", snapshot.RateLimiterTokens)
+
+	// API metrics
+	writeMetric(bw, "api_requests_total", "counter", "Total number of upstream API requests.", float64(snapshot.APIRequests))
+	writeMetric(bw, "api_errors_total", "counter", "Total number of failed upstream API requests.", float64(snapshot.APIErrors))
+	writeMetric(bw, "api_error_rate", "gauge", "Fraction of upstream API requests that failed.", snapshot.APIErrorRate)
+	writeMetric(bw, "api_in_flight", "gauge", "Number of upstream API requests currently in flight.", float64(snapshot.APIInFlight))
+	writeMetric(bw, "api_response_bytes_wire_total", "counter", "Upstream response body bytes as received, before decompression.", float64(snapshot.APIBytesWire))
+	writeMetric(bw, "api_response_bytes_decoded_total", "counter", "Upstream response body bytes after decompression.", float64(snapshot.APIBytesDecoded))
+	writeMetric(bw, "api_cache_hits_total", "counter", "State requests served from the response cache.", float64(snapshot.APICacheHits))
+	writeMetric(bw, "api_cache_misses_total", "counter", "State requests that missed the response cache and called OpenSky.", float64(snapshot.APICacheMisses))
+	writeMetric(bw, "api_backoff_until_unix", "gauge", "Unix time until which OpenSky asked for no requests after a 429, or 0.", float64(snapshot.APIBackoffUntil))
+	writeMetric(bw, "last_successful_poll_unix", "gauge", "Unix time of the last successful OpenSky poll, or 0.", float64(snapshot.LastPollSuccess))
+	writeMetric(bw, "polls_skipped_total", "counter", "Poll ticks skipped because the previous fetch was still running.", float64(snapshot.PollsSkipped))
+	writeMetric(bw, "stale_polls_skipped_total", "counter", "Polls discarded because the OpenSky snapshot time had not advanced.", float64(snapshot.StalePolls))
+	writeMetric(bw, "null_island_corrected_total", "counter", "Positions at exactly (0, 0) treated as missing.", float64(snapshot.NullIslandFixed))
+	writeMetric(bw, "malformed_states_skipped_total", "counter", "OpenSky state rows skipped for a bad shape or value type.", float64(snapshot.MalformedStates))
+	fmt.Fprintf(bw, "# HELP %sapi_latency_ms Upstream API request latency in milliseconds.\n", prometheusPrefix)
+	fmt.Fprintf(bw, "# TYPE %sapi_latency_ms histogram\n", prometheusPrefix)
+	cumulative := int64(0)
+	for _, bucket := range snapshot.APILatencyBuckets {
+		cumulative += bucket.Count
+		fmt.Fprintf(bw, "%sapi_latency_ms_bucket{le=%q} %d\n", prometheusPrefix, bucket.LE, cumulative)
+	}
+	fmt.Fprintf(bw, "%sapi_latency_ms_sum %d\n", prometheusPrefix, m.apiLatencySum.Load())
+	// The count must equal the +Inf bucket, so take it from the same snapshot
+	fmt.Fprintf(bw, "%sapi_latency_ms_count %d\n", prometheusPrefix, cumulative)
+
+	// HTTP metrics
+	writeMetric(bw, "http_requests_total", "counter", "Total number of HTTP requests served.", float64(snapshot.HTTPRequests))
+	writeMetric(bw, "http_errors_total", "counter", "Total number of HTTP requests that failed.", float64(snapshot.HTTPErrors))
+	writeMetric(bw, "stream_events_dropped_total", "counter", "Events not delivered to SSE or WebSocket clients that fell behind.", float64(snapshot.StreamDropped))
+	writeEndpointMetrics(bw, snapshot.Endpoints)
+
+	// Sink metrics
+	writeMetric(bw, "sink_retries_total", "counter", "Sink writes retried after a failure.", float64(snapshot.SinkRetries))
+	writeMetric(bw, "sink_dead_lettered_total", "counter", "Events written to a sink's dead-letter file after its retries ran out.", float64(snapshot.SinkDeadLettered))
+	writeMetric(bw, "sink_dropped_total", "counter", "Events a sink lost to a full queue or a failed delivery with no dead-letter file.", float64(snapshot.SinkDropped))
+
+	// System metrics
+	writeMetric(bw, "metrics_last_tick_unix", "gauge", "Unix time of the last metrics rate ticker run.", float64(snapshot.MetricsLastTick))
+	writeMetric(bw, "uptime_seconds", "gauge", "Seconds since the service started.", float64(snapshot.UptimeSeconds))
+	writeMetric(bw, "cumulative_uptime_seconds", "counter", "Total uptime across restarts when metrics persistence is enabled.", float64(snapshot.CumulativeUptime))
+
+	return bw.Flush()
+}
+
+// writeEndpointMetrics writes per-endpoint request metrics labeled by path, in a stable order
+func writeEndpointMetrics(w io.Writer, endpoints map[string]EndpointStats) {
+	paths := make([]string, 0, len(endpoints))
+	for path := range endpoints {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	fmt.Fprintf(w, "# HELP %shttp_endpoint_requests_total HTTP requests served per endpoint.\n", prometheusPrefix)
+	fmt.Fprintf(w, "# TYPE %shttp_endpoint_requests_total counter\n", prometheusPrefix)
+	for _, path := range paths {
+		fmt.Fprintf(w, "%shttp_endpoint_requests_total{path=%q} %d\n", prometheusPrefix, path, endpoints[path].Requests)
+	}
+
+	fmt.Fprintf(w, "# HELP %shttp_endpoint_errors_total HTTP responses with status 400 or above per endpoint.\n", prometheusPrefix)
+	fmt.Fprintf(w, "# TYPE %shttp_endpoint_errors_total counter\n", prometheusPrefix)
+	for _, path := range paths {
+		fmt.Fprintf(w, "%shttp_endpoint_errors_total{path=%q} %d\n", prometheusPrefix, path, endpoints[path].Errors)
+	}
+
+	fmt.Fprintf(w, "# HELP %shttp_endpoint_avg_latency_ms Average HTTP request latency per endpoint in milliseconds.\n", prometheusPrefix)
+	fmt.Fprintf(w, "# TYPE %shttp_endpoint_avg_latency_ms gauge\n", prometheusPrefix)
+	for _, path := range paths {
+		fmt.Fprintf(w, "%shttp_endpoint_avg_latency_ms{path=%q} %g\n", prometheusPrefix, path, endpoints[path].AvgLatencyMs)
+	}
+}
+
+// writeMetric writes a single metric with its HELP and TYPE lines
+func writeMetric(w io.Writer, name, metricType, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s%s %s\n", prometheusPrefix, name, help)
+	fmt.Fprintf(w, "# TYPE %s%s %s\n", prometheusPrefix, name, metricType)
+	fmt.Fprintf(w, "%s%s %g\n", prometheusPrefix, name, value)
+}
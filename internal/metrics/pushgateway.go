@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"flight-event-throttler/pkg/logger"
+)
+
+// PushgatewayExporter pushes metrics to a Prometheus Pushgateway for short-lived
+// or batch runs where scraping isn't feasible
+type PushgatewayExporter struct {
+	metrics    *Metrics
+	pushURL    string
+	interval   time.Duration
+	httpClient *http.Client
+	logger     logger.Interface
+}
+
+// NewPushgatewayExporter creates a new exporter that pushes to the given gateway
+// under the job name and grouping labels
+func NewPushgatewayExporter(m *Metrics, gatewayURL, job string, labels map[string]string, interval time.Duration, log logger.Interface) *PushgatewayExporter {
+	return &PushgatewayExporter{
+		metrics:  m,
+		pushURL:  buildPushURL(gatewayURL, job, labels),
+		interval: interval,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		logger: log,
+	}
+}
+
+// buildPushURL builds the grouping key URL: <gateway>/metrics/job/<job>/<label>/<value>...
+func buildPushURL(gatewayURL, job string, labels map[string]string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.TrimSuffix(gatewayURL, "/"))
+	sb.WriteString("/metrics/")
+	sb.WriteString(groupingKeyPair("job", job))
+
+	// Sort label names so the grouping key is stable
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sb.WriteString("/")
+		sb.WriteString(groupingKeyPair(name, labels[name]))
+	}
+
+	return sb.String()
+}
+
+// groupingKeyPair encodes one label of the grouping key as <name>/<value>.
+// The Pushgateway splits the path on "/" before unescaping, so values
+// containing one use its <name>@base64/<base64url value> form instead, as
+// do empty values, which would leave an empty path segment.
+func groupingKeyPair(name, value string) string {
+	if value == "" {
+		return url.PathEscape(name) + "@base64/="
+	}
+	if strings.Contains(value, "/") {
+		return url.PathEscape(name) + "@base64/" + base64.URLEncoding.EncodeToString([]byte(value))
+	}
+	return url.PathEscape(name) + "/" + url.PathEscape(value)
+}
+
+// Push sends the current metrics to the Pushgateway, replacing the previous group
+func (p *PushgatewayExporter) Push(ctx context.Context) error {
+	var body bytes.Buffer
+	if err := p.metrics.WritePrometheus(&body); err != nil {
+		return fmt.Errorf("failed to render metrics: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, p.pushURL, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("pushgateway returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Run pushes metrics every interval until the context is cancelled. The
+// final values of the run are pushed by PushFinal, once the pipeline has
+// drained.
+func (p *PushgatewayExporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.logger.Info("Pushing metrics to %s every %v", p.pushURL, p.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Push(ctx); err != nil {
+				p.logger.Error("Failed to push metrics: %v", err)
+			}
+		}
+	}
+}
+
+// PushFinal pushes once more with a fresh context. Call it after Run has
+// returned and the pipeline has drained, so the counts include every event
+// processed during shutdown.
+func (p *PushgatewayExporter) PushFinal() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := p.Push(ctx); err != nil {
+		p.logger.Error("Failed to push final metrics: %v", err)
+		return
+	}
+	p.logger.Info("Final metrics pushed to pushgateway")
+}
@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"flight-event-throttler/pkg/logger"
+)
+
+// pushRecorder is a fake Pushgateway recording the pushes it receives
+type pushRecorder struct {
+	mu     sync.Mutex
+	status int
+	pushes []recordedPush
+}
+
+type recordedPush struct {
+	method      string
+	path        string
+	contentType string
+	body        string
+}
+
+func (p *pushRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pushes = append(p.pushes, recordedPush{r.Method, r.URL.EscapedPath(), r.Header.Get("Content-Type"), string(body)})
+	if p.status != 0 {
+		w.WriteHeader(p.status)
+	}
+}
+
+func (p *pushRecorder) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.pushes)
+}
+
+func newTestExporter(t *testing.T, m *Metrics, gateway *pushRecorder, interval time.Duration) *PushgatewayExporter {
+	t.Helper()
+
+	server := httptest.NewServer(gateway)
+	t.Cleanup(server.Close)
+	labels := map[string]string{"instance": "eu-west"}
+	return NewPushgatewayExporter(m, server.URL+"/", "throttler", labels, interval, logger.NewWithWriter("ERROR", io.Discard))
+}
+
+func TestPushgatewayPush(t *testing.T) {
+	m := newTestMetrics(t)
+	m.AddEventsReceived(42)
+	gateway := &pushRecorder{}
+	exporter := newTestExporter(t, m, gateway, time.Hour)
+
+	if err := exporter.Push(context.Background()); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	if gateway.count() != 1 {
+		t.Fatalf("gateway got %d pushes, want 1", gateway.count())
+	}
+	push := gateway.pushes[0]
+	if push.method != http.MethodPut {
+		t.Errorf("method = %s, want PUT to replace the group", push.method)
+	}
+	if push.path != "/metrics/job/throttler/instance/eu-west" {
+		t.Errorf("path = %s, want /metrics/job/throttler/instance/eu-west", push.path)
+	}
+	if !strings.HasPrefix(push.contentType, "text/plain") {
+		t.Errorf("Content-Type = %q, want the Prometheus text format", push.contentType)
+	}
+	if !strings.Contains(push.body, "flight_throttler_events_received_total 42") {
+		t.Errorf("body does not carry the received count:\n%s", push.body)
+	}
+}
+
+func TestPushgatewayPushFailsOnErrorStatus(t *testing.T) {
+	exporter := newTestExporter(t, newTestMetrics(t), &pushRecorder{status: http.StatusBadRequest}, time.Hour)
+
+	if err := exporter.Push(context.Background()); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("Push against a 400 = %v, want a status error", err)
+	}
+}
+
+func TestPushgatewayFinalPushAfterRun(t *testing.T) {
+	m := newTestMetrics(t)
+	gateway := &pushRecorder{}
+	exporter := newTestExporter(t, m, gateway, 20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		exporter.Run(ctx)
+	}()
+	if !waitFor(2*time.Second, func() bool { return gateway.count() >= 1 }) {
+		t.Fatal("Run never pushed")
+	}
+
+	// Cancelling stops the periodic pushes without a push of its own, so
+	// events processed while draining aren't missed by the final push
+	cancel()
+	<-done
+	pushed := gateway.count()
+	m.AddEventsReceived(7)
+
+	exporter.PushFinal()
+	if gateway.count() != pushed+1 {
+		t.Fatalf("gateway got %d pushes after PushFinal, want %d", gateway.count(), pushed+1)
+	}
+	if body := gateway.pushes[pushed].body; !strings.Contains(body, "flight_throttler_events_received_total 7") {
+		t.Errorf("final push does not include events counted after Run stopped:\n%s", body)
+	}
+}
+
+func TestBuildPushURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		job    string
+		labels map[string]string
+		want   string
+	}{
+		{"job only", "throttler", nil, "http://gw/metrics/job/throttler"},
+		{"sorted labels", "throttler", map[string]string{"zone": "b", "env": "prod"}, "http://gw/metrics/job/throttler/env/prod/zone/b"},
+		{"escaped value", "throttler", map[string]string{"team": "flight ops"}, "http://gw/metrics/job/throttler/team/flight%20ops"},
+		{"value with slash", "throttler", map[string]string{"path": "/var/tmp"}, "http://gw/metrics/job/throttler/path@base64/L3Zhci90bXA="},
+		{"job with slash", "batch/nightly", nil, "http://gw/metrics/job@base64/YmF0Y2gvbmlnaHRseQ=="},
+		{"empty value", "throttler", map[string]string{"instance": ""}, "http://gw/metrics/job/throttler/instance@base64/="},
+	}
+	for _, tt := range tests {
+		if got := buildPushURL("http://gw/", tt.job, tt.labels); got != tt.want {
+			t.Errorf("%s: buildPushURL = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultRateWindow is the default span over which GetSmoothedEventsPerSecond
+// averages the per-second processed counts
+const DefaultRateWindow = 10 * time.Second
+
+// rateWindow is a fixed-size ring of per-second samples with a running sum,
+// so recording and averaging never allocate
+type rateWindow struct {
+	mu      sync.Mutex
+	samples []int64
+	next    int
+	filled  int
+	sum     int64
+}
+
+func newRateWindow(window time.Duration) *rateWindow {
+	return &rateWindow{samples: make([]int64, int(window/time.Second))}
+}
+
+// record adds one second's sample, evicting the oldest once the ring is full
+func (w *rateWindow) record(sample int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.filled == len(w.samples) {
+		w.sum -= w.samples[w.next]
+	} else {
+		w.filled++
+	}
+	w.samples[w.next] = sample
+	w.sum += sample
+	w.next = (w.next + 1) % len(w.samples)
+}
+
+// reset discards all recorded samples
+func (w *rateWindow) reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for i := range w.samples {
+		w.samples[i] = 0
+	}
+	w.next = 0
+	w.filled = 0
+	w.sum = 0
+}
+
+// average returns the mean of the recorded samples, or 0 when there are none
+func (w *rateWindow) average() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.filled == 0 {
+		return 0
+	}
+	return float64(w.sum) / float64(w.filled)
+}
+
+// total returns the sum of the recorded samples
+func (w *rateWindow) total() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.sum
+}
+
+// sampleDelta returns how much the counter grew since the previous sample and
+// remembers its current value for the next one
+func sampleDelta(counter, last *atomic.Int64) int64 {
+	current := counter.Load()
+	return current - last.Swap(current)
+}
+
+// SetRateWindow replaces the window used by GetSmoothedEventsPerSecond and the
+// recent drop and API error rates. The window is rounded down to whole seconds
+// and must be at least one second. Samples recorded so far are discarded.
+func (m *Metrics) SetRateWindow(window time.Duration) error {
+	if window < time.Second {
+		return fmt.Errorf("rate window must be at least 1s")
+	}
+
+	m.storeRateWindows(window)
+	return nil
+}
+
+func (m *Metrics) storeRateWindows(window time.Duration) {
+	m.eventsRate.Store(newRateWindow(window))
+	m.droppedRate.Store(newRateWindow(window))
+	m.apiRequestsRate.Store(newRateWindow(window))
+	m.apiErrorsRate.Store(newRateWindow(window))
+}
+
+// GetSmoothedEventsPerSecond returns the events processed per second averaged
+// over the rate window. Until the window fills, it averages the seconds seen
+// so far.
+func (m *Metrics) GetSmoothedEventsPerSecond() float64 {
+	return m.eventsRate.Load().average()
+}
+
+// GetRecentDropRate returns the fraction of events that reached the rate
+// limiter and were dropped, over the rate window only, so it falls back to 0
+// once a burst of drops has passed
+func (m *Metrics) GetRecentDropRate() float64 {
+	dropped := m.droppedRate.Load().total()
+	attempted := m.eventsRate.Load().total() + dropped
+	if attempted == 0 {
+		return 0
+	}
+	return float64(dropped) / float64(attempted)
+}
+
+// GetRecentAPIErrorRate returns the fraction of upstream API requests that
+// failed over the rate window, or 0 when none were made in it
+func (m *Metrics) GetRecentAPIErrorRate() float64 {
+	requests := m.apiRequestsRate.Load().total()
+	if requests == 0 {
+		return 0
+	}
+	return float64(m.apiErrorsRate.Load().total()) / float64(requests)
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateWindowSteadySeries(t *testing.T) {
+	w := newRateWindow(5 * time.Second)
+	for i := 0; i < 12; i++ {
+		w.record(100)
+	}
+
+	if got := w.average(); got != 100 {
+		t.Errorf("average = %v, want 100 for a steady series", got)
+	}
+}
+
+func TestRateWindowBurstySeries(t *testing.T) {
+	w := newRateWindow(4 * time.Second)
+
+	// A burst of 400 followed by idle seconds averages out over the window
+	for _, sample := range []int64{0, 400, 0, 0} {
+		w.record(sample)
+	}
+	if got := w.average(); got != 100 {
+		t.Errorf("average = %v, want 100", got)
+	}
+
+	// Once the burst leaves the window, the average drops back to zero
+	w.record(0)
+	w.record(0)
+	if got := w.average(); got != 0 {
+		t.Errorf("average after the burst expired = %v, want 0", got)
+	}
+}
+
+func TestRateWindowAveragesPartialWindow(t *testing.T) {
+	w := newRateWindow(10 * time.Second)
+	if got := w.average(); got != 0 {
+		t.Errorf("average of an empty window = %v, want 0", got)
+	}
+
+	w.record(10)
+	w.record(30)
+	if got := w.average(); got != 20 {
+		t.Errorf("average = %v, want 20 over the two seconds seen", got)
+	}
+}
+
+func TestRateWindowRecordDoesNotAllocate(t *testing.T) {
+	w := newRateWindow(10 * time.Second)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		w.record(5)
+		w.average()
+	})
+	if allocs != 0 {
+		t.Errorf("record and average allocated %v times per run, want 0", allocs)
+	}
+}
+
+func TestSetRateWindowRejectsSubSecond(t *testing.T) {
+	m := newTestMetrics(t)
+
+	if err := m.SetRateWindow(500 * time.Millisecond); err == nil {
+		t.Error("expected an error for a window under one second")
+	}
+}
@@ -0,0 +1,45 @@
+package model
+
+// Conversion factors from the SI units reported by OpenSky
+const (
+	FeetPerMeter           = 3.28084
+	KnotsPerMeterPerSecond = 1.943844
+)
+
+// squawkMeanings labels transponder codes with a well-known meaning
+var squawkMeanings = map[string]string{
+	"7500": "hijack",
+	"7600": "radio failure",
+	"7700": "emergency",
+	"7000": "VFR (ICAO)",
+	"1200": "VFR (North America)",
+	"2000": "no code assigned",
+}
+
+// AltitudeFeet returns the barometric altitude in feet, or nil when it is
+// unknown
+func (e *FlightEvent) AltitudeFeet() *float64 {
+	if e == nil || e.BaroAltitude == nil {
+		return nil
+	}
+	feet := *e.BaroAltitude * FeetPerMeter
+	return &feet
+}
+
+// VelocityKnots returns the ground speed in knots, or nil when it is unknown
+func (e *FlightEvent) VelocityKnots() *float64 {
+	if e == nil || e.Velocity == nil {
+		return nil
+	}
+	knots := *e.Velocity * KnotsPerMeterPerSecond
+	return &knots
+}
+
+// SquawkMeaning returns a label for well-known squawk codes, such as
+// "emergency" for 7700. It returns "" for other or missing codes.
+func (e *FlightEvent) SquawkMeaning() string {
+	if e == nil || e.Squawk == nil {
+		return ""
+	}
+	return squawkMeanings[*e.Squawk]
+}
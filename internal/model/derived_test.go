@@ -0,0 +1,53 @@
+package model
+
+import "testing"
+
+func stringPtr(s string) *string { return &s }
+
+func TestDerivedFieldsNil(t *testing.T) {
+	var nilEvent *FlightEvent
+	empty := &FlightEvent{ICAO24: "abc123"}
+
+	for name, e := range map[string]*FlightEvent{"nil event": nilEvent, "missing fields": empty} {
+		if got := e.AltitudeFeet(); got != nil {
+			t.Errorf("%s: AltitudeFeet() = %v, want nil", name, *got)
+		}
+		if got := e.VelocityKnots(); got != nil {
+			t.Errorf("%s: VelocityKnots() = %v, want nil", name, *got)
+		}
+		if got := e.SquawkMeaning(); got != "" {
+			t.Errorf("%s: SquawkMeaning() = %q, want empty", name, got)
+		}
+	}
+}
+
+func TestDerivedUnitConversions(t *testing.T) {
+	e := &FlightEvent{BaroAltitude: floatPtr(10000), Velocity: floatPtr(250)}
+
+	if got := e.AltitudeFeet(); got == nil || !approx(*got, 32808.4, 1e-6) {
+		t.Errorf("AltitudeFeet() = %v, want 32808.4", got)
+	}
+	if got := e.VelocityKnots(); got == nil || !approx(*got, 485.961, 1e-6) {
+		t.Errorf("VelocityKnots() = %v, want 485.961", got)
+	}
+}
+
+func TestSquawkMeaning(t *testing.T) {
+	tests := map[string]string{
+		"7500": "hijack",
+		"7600": "radio failure",
+		"7700": "emergency",
+		"7000": "VFR (ICAO)",
+		"1200": "VFR (North America)",
+		"2000": "no code assigned",
+		"1000": "",
+		"7777": "",
+		"":     "",
+	}
+	for squawk, want := range tests {
+		e := &FlightEvent{Squawk: stringPtr(squawk)}
+		if got := e.SquawkMeaning(); got != want {
+			t.Errorf("SquawkMeaning(%q) = %q, want %q", squawk, got, want)
+		}
+	}
+}
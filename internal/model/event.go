@@ -15,13 +15,41 @@ type FlightEvent struct {
 	Velocity       *float64  `json:"velocity"`
 	TrueTrack      *float64  `json:"true_track"`
 	VerticalRate   *float64  `json:"vertical_rate"`
+	Sensors        []int     `json:"sensors,omitempty"`
 	GeoAltitude    *float64  `json:"geo_altitude"`
 	Squawk         *string   `json:"squawk"`
 	Spi            bool      `json:"spi"`
 	PositionSource int       `json:"position_source"`
+	Registration   string    `json:"registration,omitempty"`
+	AircraftType   string    `json:"aircraft_type,omitempty"`
 	Timestamp      time.Time `json:"timestamp"`
 }
 
+// Timestamp sources used to populate FlightEvent.Timestamp
+const (
+	TimestampSourceIngest       = "ingest"
+	TimestampSourceLastContact  = "last_contact"
+	TimestampSourceTimePosition = "time_position"
+)
+
+// StampTimestamp sets Timestamp from the given source. The ingest source uses
+// now; the event-time sources fall back to now when the value is missing.
+func (e *FlightEvent) StampTimestamp(source string, now time.Time) {
+	var unix int64
+	switch source {
+	case TimestampSourceLastContact:
+		unix = e.LastContact
+	case TimestampSourceTimePosition:
+		unix = e.TimePosition
+	}
+
+	if unix > 0 {
+		e.Timestamp = time.Unix(unix, 0)
+	} else {
+		e.Timestamp = now
+	}
+}
+
 type FlightEventBatch struct {
 	Events    []FlightEvent `json:"events"`
 	Count     int           `json:"count"`
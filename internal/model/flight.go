@@ -0,0 +1,13 @@
+package model
+
+// FlightConnection is a single flight between two airports as estimated by
+// OpenSky. Field names follow the OpenSky /flights API so responses decode
+// directly; airports are empty when OpenSky could not estimate them.
+type FlightConnection struct {
+	ICAO24              string `json:"icao24"`
+	FirstSeen           int64  `json:"firstSeen"`
+	EstDepartureAirport string `json:"estDepartureAirport"`
+	LastSeen            int64  `json:"lastSeen"`
+	EstArrivalAirport   string `json:"estArrivalAirport"`
+	Callsign            string `json:"callsign"`
+}
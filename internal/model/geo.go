@@ -0,0 +1,29 @@
+package model
+
+import "math"
+
+// EarthRadiusKm is the mean radius of the Earth
+const EarthRadiusKm = 6371.0
+
+// Haversine returns the great-circle distance in kilometers between two
+// points given in degrees
+func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	return EarthRadiusKm * angularDistance(toRadians(lat1), toRadians(lon1), toRadians(lat2), toRadians(lon2))
+}
+
+// angularDistance returns the central angle in radians between two points
+// given in radians, using the haversine formula
+func angularDistance(phi1, lambda1, phi2, lambda2 float64) float64 {
+	a := math.Pow(math.Sin((phi2-phi1)/2), 2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Pow(math.Sin((lambda2-lambda1)/2), 2)
+	return 2 * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// DistanceTo returns the great-circle distance in kilometers from the event's
+// position to the given point. It reports false when the position is unknown.
+func (e *FlightEvent) DistanceTo(lat, lon float64) (float64, bool) {
+	if e == nil || e.Latitude == nil || e.Longitude == nil {
+		return 0, false
+	}
+	return Haversine(*e.Latitude, *e.Longitude, lat, lon), true
+}
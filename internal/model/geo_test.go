@@ -0,0 +1,57 @@
+package model
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHaversineKnownCities(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		wantKm                 float64
+	}{
+		{"London to Paris", 51.5074, -0.1278, 48.8566, 2.3522, 343.5},
+		{"New York to Los Angeles", 40.7128, -74.0060, 34.0522, -118.2437, 3935.7},
+		{"Zurich to Geneva", 47.3769, 8.5417, 46.2044, 6.1432, 224.3},
+		{"Sydney to Tokyo", -33.8688, 151.2093, 35.6762, 139.6503, 7823.3},
+	}
+	for _, tt := range tests {
+		got := Haversine(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+		// Within 0.5% of the spherical-Earth reference distance
+		if !approx(got, tt.wantKm, tt.wantKm*0.005) {
+			t.Errorf("%s: Haversine = %.1f km, want %.1f km", tt.name, got, tt.wantKm)
+		}
+		if back := Haversine(tt.lat2, tt.lon2, tt.lat1, tt.lon1); !approx(back, got, 1e-9) {
+			t.Errorf("%s: distance is not symmetric: %.6f vs %.6f", tt.name, got, back)
+		}
+	}
+}
+
+func TestHaversineEdgeCases(t *testing.T) {
+	if got := Haversine(47.4, 8.5, 47.4, 8.5); got != 0 {
+		t.Errorf("same point: Haversine = %v, want 0", got)
+	}
+
+	// Antipodal points are half the circumference apart
+	halfCircumference := math.Pi * EarthRadiusKm
+	if got := Haversine(0, 0, 0, 180); !approx(got, halfCircumference, 1e-6) {
+		t.Errorf("antipodes: Haversine = %v, want %v", got, halfCircumference)
+	}
+
+	// Crossing the antimeridian takes the short way round
+	if got := Haversine(0, 179.5, 0, -179.5); !approx(got, 111.19, 0.01) {
+		t.Errorf("across the antimeridian: Haversine = %v, want about 111.19", got)
+	}
+}
+
+func TestDistanceToUnknownPosition(t *testing.T) {
+	if _, ok := (&FlightEvent{ICAO24: "abc123"}).DistanceTo(47.4, 8.5); ok {
+		t.Error("DistanceTo reported a distance for an event without a position")
+	}
+
+	e := &FlightEvent{Latitude: floatPtr(51.5074), Longitude: floatPtr(-0.1278)}
+	if km, ok := e.DistanceTo(48.8566, 2.3522); !ok || !approx(km, Haversine(51.5074, -0.1278, 48.8566, 2.3522), 1e-9) {
+		t.Errorf("DistanceTo = %v, %v, want the Haversine distance", km, ok)
+	}
+}
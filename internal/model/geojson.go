@@ -0,0 +1,69 @@
+package model
+
+// GeoJSONFeatureCollection is a GeoJSON FeatureCollection of aircraft positions
+type GeoJSONFeatureCollection struct {
+	Type     string            `json:"type"` // Always "FeatureCollection"
+	Features []*GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature is a GeoJSON Point feature for one aircraft
+type GeoJSONFeature struct {
+	Type       string            `json:"type"` // Always "Feature"
+	Geometry   GeoJSONPoint      `json:"geometry"`
+	Properties GeoJSONProperties `json:"properties"`
+}
+
+// GeoJSONPoint is a GeoJSON Point geometry. Coordinates are [longitude,
+// latitude] as GeoJSON requires.
+type GeoJSONPoint struct {
+	Type        string     `json:"type"` // Always "Point"
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// GeoJSONProperties are the event fields carried by a feature. Values OpenSky
+// doesn't report are null.
+type GeoJSONProperties struct {
+	ICAO24       string   `json:"icao24"`
+	Callsign     string   `json:"callsign"`
+	Velocity     *float64 `json:"velocity"`
+	TrueTrack    *float64 `json:"true_track"`
+	BaroAltitude *float64 `json:"baro_altitude"`
+}
+
+// ToGeoJSONFeature returns the event as a Point feature, or nil when it has
+// no position
+func (e *FlightEvent) ToGeoJSONFeature() *GeoJSONFeature {
+	if e == nil || e.Latitude == nil || e.Longitude == nil {
+		return nil
+	}
+
+	return &GeoJSONFeature{
+		Type: "Feature",
+		Geometry: GeoJSONPoint{
+			Type:        "Point",
+			Coordinates: [2]float64{*e.Longitude, *e.Latitude},
+		},
+		Properties: GeoJSONProperties{
+			ICAO24:       e.ICAO24,
+			Callsign:     e.Callsign,
+			Velocity:     e.Velocity,
+			TrueTrack:    e.TrueTrack,
+			BaroAltitude: e.BaroAltitude,
+		},
+	}
+}
+
+// NewGeoJSONFeatureCollection builds a FeatureCollection from events,
+// omitting events without a position
+func NewGeoJSONFeatureCollection(events []*FlightEvent) *GeoJSONFeatureCollection {
+	collection := &GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]*GeoJSONFeature, 0, len(events)),
+	}
+	for _, event := range events {
+		if feature := event.ToGeoJSONFeature(); feature != nil {
+			collection.Features = append(collection.Features, feature)
+		}
+	}
+	return collection
+}
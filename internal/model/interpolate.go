@@ -0,0 +1,114 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// Interpolate returns the estimated state of an aircraft at time t between two
+// consecutive updates a and b. Latitude/longitude follow the great circle
+// between the two positions (so paths crossing the antimeridian are handled);
+// altitudes and other numeric fields are interpolated linearly. t is clamped
+// to the [a.Timestamp, b.Timestamp] range.
+//
+// If either update is missing coordinates, no position is interpolated and a
+// copy of whichever update is closest in time to t is returned. Nil is
+// returned if a or b is nil.
+func Interpolate(a, b *FlightEvent, t time.Time) *FlightEvent {
+	if a == nil || b == nil {
+		return nil
+	}
+
+	// Fraction of the way from a to b
+	f := 0.0
+	if span := b.Timestamp.Sub(a.Timestamp); span > 0 {
+		f = float64(t.Sub(a.Timestamp)) / float64(span)
+	}
+	f = math.Max(0, math.Min(1, f))
+
+	if a.Latitude == nil || a.Longitude == nil || b.Latitude == nil || b.Longitude == nil {
+		nearest := *a
+		if f >= 0.5 {
+			nearest = *b
+		}
+		return &nearest
+	}
+
+	// Identity and discrete fields come from the later update once past the midpoint
+	result := *a
+	if f >= 0.5 {
+		result = *b
+	}
+	result.Timestamp = a.Timestamp.Add(time.Duration(f * float64(b.Timestamp.Sub(a.Timestamp))))
+
+	lat, lon := greatCircleInterpolate(*a.Latitude, *a.Longitude, *b.Latitude, *b.Longitude, f)
+	result.Latitude = &lat
+	result.Longitude = &lon
+
+	result.BaroAltitude = lerpPtr(a.BaroAltitude, b.BaroAltitude, f)
+	result.GeoAltitude = lerpPtr(a.GeoAltitude, b.GeoAltitude, f)
+	result.Velocity = lerpPtr(a.Velocity, b.Velocity, f)
+	result.VerticalRate = lerpPtr(a.VerticalRate, b.VerticalRate, f)
+	result.TrueTrack = lerpAnglePtr(a.TrueTrack, b.TrueTrack, f)
+
+	return &result
+}
+
+// greatCircleInterpolate returns the point a fraction f along the great circle
+// from (lat1, lon1) to (lat2, lon2), in degrees
+func greatCircleInterpolate(lat1, lon1, lat2, lon2, f float64) (float64, float64) {
+	phi1, lambda1 := toRadians(lat1), toRadians(lon1)
+	phi2, lambda2 := toRadians(lat2), toRadians(lon2)
+
+	d := angularDistance(phi1, lambda1, phi2, lambda2)
+	if d == 0 {
+		return lat1, lon1
+	}
+
+	A := math.Sin((1-f)*d) / math.Sin(d)
+	B := math.Sin(f*d) / math.Sin(d)
+
+	x := A*math.Cos(phi1)*math.Cos(lambda1) + B*math.Cos(phi2)*math.Cos(lambda2)
+	y := A*math.Cos(phi1)*math.Sin(lambda1) + B*math.Cos(phi2)*math.Sin(lambda2)
+	z := A*math.Sin(phi1) + B*math.Sin(phi2)
+
+	lat := math.Atan2(z, math.Sqrt(x*x+y*y))
+	lon := math.Atan2(y, x)
+
+	return toDegrees(lat), toDegrees(lon)
+}
+
+// lerpPtr linearly interpolates two optional values; if either is nil the
+// available one is returned
+func lerpPtr(a, b *float64, f float64) *float64 {
+	switch {
+	case a == nil && b == nil:
+		return nil
+	case a == nil:
+		v := *b
+		return &v
+	case b == nil:
+		v := *a
+		return &v
+	}
+	v := *a + (*b-*a)*f
+	return &v
+}
+
+// lerpAnglePtr interpolates two optional headings in degrees along the shorter arc
+func lerpAnglePtr(a, b *float64, f float64) *float64 {
+	if a == nil || b == nil {
+		return lerpPtr(a, b, f)
+	}
+	delta := math.Mod(*b-*a+540, 360) - 180
+	v := math.Mod(*a+delta*f+360, 360)
+	return &v
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+
+// approx reports whether a and b differ by at most tol
+func approx(a, b, tol float64) bool {
+	return math.Abs(a-b) <= tol
+}
+
+// positionAt returns an event at lat/lon and the given time
+func positionAt(lat, lon float64, ts time.Time) *FlightEvent {
+	return &FlightEvent{ICAO24: "abc123", Latitude: floatPtr(lat), Longitude: floatPtr(lon), Timestamp: ts}
+}
+
+func TestInterpolateEquatorMidpoint(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	a := positionAt(0, 0, start)
+	b := positionAt(0, 10, start.Add(10*time.Second))
+	a.BaroAltitude = floatPtr(1000)
+	b.BaroAltitude = floatPtr(2000)
+
+	got := Interpolate(a, b, start.Add(5*time.Second))
+
+	if !approx(*got.Latitude, 0, 1e-9) || !approx(*got.Longitude, 5, 1e-9) {
+		t.Errorf("position = (%v, %v), want (0, 5)", *got.Latitude, *got.Longitude)
+	}
+	if !approx(*got.BaroAltitude, 1500, 1e-9) {
+		t.Errorf("altitude = %v, want 1500", *got.BaroAltitude)
+	}
+	if !got.Timestamp.Equal(start.Add(5 * time.Second)) {
+		t.Errorf("timestamp = %v, want the target time", got.Timestamp)
+	}
+}
+
+func TestInterpolateMeridianQuarter(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	a := positionAt(0, 0, start)
+	b := positionAt(40, 0, start.Add(4*time.Second))
+
+	got := Interpolate(a, b, start.Add(time.Second))
+
+	if !approx(*got.Latitude, 10, 1e-9) || !approx(*got.Longitude, 0, 1e-9) {
+		t.Errorf("position = (%v, %v), want (10, 0)", *got.Latitude, *got.Longitude)
+	}
+}
+
+func TestInterpolateAntimeridian(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	a := positionAt(0, 179, start)
+	b := positionAt(0, -179, start.Add(2*time.Second))
+
+	got := Interpolate(a, b, start.Add(time.Second))
+
+	if !approx(math.Abs(*got.Longitude), 180, 1e-9) {
+		t.Errorf("longitude = %v, want ±180 (crossing the antimeridian, not 0)", *got.Longitude)
+	}
+}
+
+func TestInterpolateClampsTime(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	a := positionAt(0, 0, start)
+	b := positionAt(0, 10, start.Add(10*time.Second))
+
+	before := Interpolate(a, b, start.Add(-time.Minute))
+	after := Interpolate(a, b, start.Add(time.Minute))
+
+	if !approx(*before.Longitude, 0, 1e-9) {
+		t.Errorf("before start: longitude = %v, want 0", *before.Longitude)
+	}
+	if !approx(*after.Longitude, 10, 1e-9) {
+		t.Errorf("after end: longitude = %v, want 10", *after.Longitude)
+	}
+}
+
+func TestInterpolateMissingCoordinates(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	a := positionAt(0, 0, start)
+	b := &FlightEvent{ICAO24: "abc123", Callsign: "LATE", Timestamp: start.Add(10 * time.Second)}
+
+	early := Interpolate(a, b, start.Add(2*time.Second))
+	if early.Latitude == nil || *early.Longitude != 0 {
+		t.Errorf("near a: got %+v, want a copy of a", early)
+	}
+	if early == a {
+		t.Error("expected a copy, not the original event")
+	}
+
+	late := Interpolate(a, b, start.Add(8*time.Second))
+	if late.Callsign != "LATE" || late.Latitude != nil {
+		t.Errorf("near b: got %+v, want a copy of b", late)
+	}
+}
+
+func TestInterpolateNil(t *testing.T) {
+	if got := Interpolate(nil, positionAt(0, 0, time.Now()), time.Now()); got != nil {
+		t.Errorf("Interpolate(nil, b) = %+v, want nil", got)
+	}
+}
+
+func TestInterpolateHeadingShorterArc(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	a := positionAt(0, 0, start)
+	b := positionAt(0, 1, start.Add(2*time.Second))
+	a.TrueTrack = floatPtr(350)
+	b.TrueTrack = floatPtr(10)
+
+	got := Interpolate(a, b, start.Add(time.Second))
+
+	if !approx(*got.TrueTrack, 0, 1e-9) && !approx(*got.TrueTrack, 360, 1e-9) {
+		t.Errorf("heading = %v, want 0 (through north)", *got.TrueTrack)
+	}
+}
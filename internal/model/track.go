@@ -0,0 +1,31 @@
+package model
+
+// FlightTrack is the trajectory of a single aircraft over one flight
+type FlightTrack struct {
+	ICAO24    string     `json:"icao24"`
+	Callsign  string     `json:"callsign"`
+	StartTime int64      `json:"start_time"`
+	EndTime   int64      `json:"end_time"`
+	Waypoints []Waypoint `json:"waypoints"`
+}
+
+// Waypoint is a single point along a FlightTrack. Position fields are nil
+// when OpenSky has no value for them.
+type Waypoint struct {
+	Time         int64    `json:"time"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	BaroAltitude *float64 `json:"baro_altitude"`
+	TrueTrack    *float64 `json:"true_track"`
+	OnGround     bool     `json:"on_ground"`
+}
+
+// OpenSkyTrackResponse is the raw /tracks/all response. Each path entry is
+// [time, latitude, longitude, baro_altitude, true_track, on_ground].
+type OpenSkyTrackResponse struct {
+	ICAO24    string          `json:"icao24"`
+	Callsign  *string         `json:"callsign"`
+	StartTime float64         `json:"startTime"`
+	EndTime   float64         `json:"endTime"`
+	Path      [][]interface{} `json:"path"`
+}
@@ -0,0 +1,121 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"flight-event-throttler/internal/metrics"
+	"flight-event-throttler/pkg/logger"
+)
+
+// AdaptiveController tunes the global rate limit from buffer utilization:
+// the fuller the buffer, the lower the limit. The limit moves linearly from
+// maxRate at an empty buffer to minRate at a full one.
+type AdaptiveController struct {
+	rateLimiter *RateLimiter
+	metrics     *metrics.Metrics
+	minRate     int
+	maxRate     int
+	interval    time.Duration
+	logger      logger.Interface
+	current     atomic.Int64
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	mu          sync.Mutex
+}
+
+// NewAdaptiveController creates a controller that adjusts rl between minRate
+// and maxRate events per second every interval
+func NewAdaptiveController(rl *RateLimiter, m *metrics.Metrics, minRate, maxRate int, interval time.Duration, log logger.Interface) *AdaptiveController {
+	ac := &AdaptiveController{
+		rateLimiter: rl,
+		metrics:     m,
+		minRate:     minRate,
+		maxRate:     maxRate,
+		interval:    interval,
+		logger:      log,
+	}
+	current, _ := rl.GetLimit()
+	ac.current.Store(int64(current))
+	return ac
+}
+
+// Start begins adjusting the limit in the background. Calling Start on a
+// running controller has no effect.
+func (ac *AdaptiveController) Start() {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if ac.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ac.cancel = cancel
+	ac.wg.Add(1)
+	go ac.run(ctx)
+}
+
+// Stop halts adjustment and waits for the background loop to exit. The last
+// computed limit stays in effect.
+func (ac *AdaptiveController) Stop() {
+	ac.mu.Lock()
+	cancel := ac.cancel
+	ac.cancel = nil
+	ac.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		ac.wg.Wait()
+	}
+}
+
+// CurrentLimit returns the rate limit most recently applied, in events per second
+func (ac *AdaptiveController) CurrentLimit() int {
+	return int(ac.current.Load())
+}
+
+func (ac *AdaptiveController) run(ctx context.Context) {
+	defer ac.wg.Done()
+
+	ticker := time.NewTicker(ac.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ac.Adjust(ac.metrics.GetBufferUtilization())
+		}
+	}
+}
+
+// Adjust applies the limit for the given buffer utilization percentage and
+// returns it. The rate limiter is only updated when the limit changes.
+func (ac *AdaptiveController) Adjust(utilization float64) int {
+	limit := ac.limitFor(utilization)
+	if previous := int(ac.current.Swap(int64(limit))); previous == limit {
+		return limit
+	}
+
+	_, burst := ac.rateLimiter.GetLimit()
+	ac.rateLimiter.UpdateLimit(limit, burst)
+	ac.logger.Debug("Adaptive rate limit set to %d events/sec at %.1f%% buffer utilization", limit, utilization)
+	return limit
+}
+
+// limitFor maps utilization (0-100, clamped) linearly onto [minRate, maxRate]
+func (ac *AdaptiveController) limitFor(utilization float64) int {
+	if utilization < 0 {
+		utilization = 0
+	}
+	if utilization > 100 {
+		utilization = 100
+	}
+
+	span := float64(ac.maxRate - ac.minRate)
+	return ac.maxRate - int(span*utilization/100+0.5)
+}
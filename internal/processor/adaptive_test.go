@@ -0,0 +1,89 @@
+package processor
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/metrics"
+	"flight-event-throttler/pkg/logger"
+)
+
+func newTestController(t *testing.T, minRate, maxRate int) (*AdaptiveController, *RateLimiter) {
+	t.Helper()
+
+	m := metrics.NewMetrics()
+	t.Cleanup(m.Close)
+	rl := NewRateLimiter(maxRate, 50)
+	return NewAdaptiveController(rl, m, minRate, maxRate, time.Second, logger.NewWithWriter("ERROR", io.Discard)), rl
+}
+
+func TestAdaptiveLimitFollowsUtilization(t *testing.T) {
+	ac, rl := newTestController(t, 10, 100)
+
+	previous := ac.CurrentLimit()
+	for _, utilization := range []float64{10, 40, 75, 95} {
+		limit := ac.Adjust(utilization)
+		if limit >= previous {
+			t.Errorf("Adjust(%.0f) = %d, want less than %d as the buffer fills", utilization, limit, previous)
+		}
+		if got, burst := rl.GetLimit(); got != limit || burst != 50 {
+			t.Errorf("limiter at %d/%d after Adjust(%.0f), want %d/50", got, burst, utilization, limit)
+		}
+		previous = limit
+	}
+
+	for _, utilization := range []float64{60, 20, 5} {
+		limit := ac.Adjust(utilization)
+		if limit <= previous {
+			t.Errorf("Adjust(%.0f) = %d, want more than %d as the buffer drains", utilization, limit, previous)
+		}
+		previous = limit
+	}
+	if got := ac.CurrentLimit(); got != previous {
+		t.Errorf("CurrentLimit() = %d, want %d", got, previous)
+	}
+}
+
+func TestAdaptiveLimitClampsToBounds(t *testing.T) {
+	ac, _ := newTestController(t, 10, 100)
+
+	tests := []struct {
+		utilization float64
+		want        int
+	}{
+		{-20, 100},
+		{0, 100},
+		{50, 55},
+		{100, 10},
+		{250, 10},
+	}
+	for _, tt := range tests {
+		if got := ac.limitFor(tt.utilization); got != tt.want {
+			t.Errorf("limitFor(%.0f) = %d, want %d", tt.utilization, got, tt.want)
+		}
+	}
+}
+
+func TestAdaptiveControllerReadsBufferUtilization(t *testing.T) {
+	m := metrics.NewMetrics()
+	defer m.Close()
+	rl := NewRateLimiter(100, 50)
+	ac := NewAdaptiveController(rl, m, 10, 100, 5*time.Millisecond, logger.NewWithWriter("ERROR", io.Discard))
+
+	m.SetBufferCapacity(100)
+	m.SetBufferSize(100)
+	ac.Start()
+	defer ac.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for ac.CurrentLimit() != 10 {
+		if time.Now().After(deadline) {
+			t.Fatalf("CurrentLimit() = %d after the buffer filled, want 10", ac.CurrentLimit())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got, _ := rl.GetLimit(); got != 10 {
+		t.Errorf("limiter rate = %d, want 10", got)
+	}
+}
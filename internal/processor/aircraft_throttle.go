@@ -0,0 +1,70 @@
+package processor
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// AircraftThrottle limits updates to at most one per aircraft per interval,
+// independent of the global rate limiter. Last-emit times are kept in a
+// bounded LRU so memory stays constant regardless of how many aircraft are seen.
+type AircraftThrottle struct {
+	interval   time.Duration
+	maxTracked int
+	entries    map[string]*list.Element
+	lru        *list.List // Front is most recently emitted
+	mu         sync.Mutex
+}
+
+type aircraftEntry struct {
+	icao24   string
+	lastEmit time.Time
+}
+
+// NewAircraftThrottle creates a throttle allowing one update per aircraft per interval,
+// tracking at most maxTracked aircraft
+func NewAircraftThrottle(interval time.Duration, maxTracked int) *AircraftThrottle {
+	return &AircraftThrottle{
+		interval:   interval,
+		maxTracked: maxTracked,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Allow reports whether an update for the aircraft may be emitted at now,
+// recording the emit time if so
+func (at *AircraftThrottle) Allow(icao24 string, now time.Time) bool {
+	at.mu.Lock()
+	defer at.mu.Unlock()
+
+	if elem, ok := at.entries[icao24]; ok {
+		entry := elem.Value.(*aircraftEntry)
+		if now.Sub(entry.lastEmit) < at.interval {
+			return false
+		}
+		entry.lastEmit = now
+		at.lru.MoveToFront(elem)
+		return true
+	}
+
+	at.entries[icao24] = at.lru.PushFront(&aircraftEntry{icao24: icao24, lastEmit: now})
+
+	// Evict the least recently emitted aircraft once over capacity
+	if at.lru.Len() > at.maxTracked {
+		oldest := at.lru.Back()
+		at.lru.Remove(oldest)
+		delete(at.entries, oldest.Value.(*aircraftEntry).icao24)
+	}
+
+	return true
+}
+
+// Tracked returns the number of aircraft currently tracked
+func (at *AircraftThrottle) Tracked() int {
+	at.mu.Lock()
+	defer at.mu.Unlock()
+
+	return at.lru.Len()
+}
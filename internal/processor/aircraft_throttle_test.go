@@ -0,0 +1,87 @@
+package processor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAircraftThrottleLimitsEachAircraft(t *testing.T) {
+	at := NewAircraftThrottle(10*time.Second, 100)
+	start := time.Unix(1700000000, 0)
+
+	if !at.Allow("abc123", start) {
+		t.Fatal("first update should be allowed")
+	}
+	if at.Allow("abc123", start.Add(10*time.Second-time.Nanosecond)) {
+		t.Error("update within the interval should be throttled")
+	}
+	// Other aircraft are limited independently
+	if !at.Allow("def456", start.Add(time.Second)) {
+		t.Error("another aircraft's first update should be allowed")
+	}
+	if !at.Allow("abc123", start.Add(10*time.Second)) {
+		t.Error("update once the interval has passed should be allowed")
+	}
+
+	// The interval runs from the last allowed update, not the last attempt
+	if at.Allow("abc123", start.Add(19*time.Second)) {
+		t.Error("update 9s after the last allowed one should be throttled")
+	}
+	if !at.Allow("abc123", start.Add(20*time.Second)) {
+		t.Error("update 10s after the last allowed one should be allowed")
+	}
+}
+
+func TestAircraftThrottleEvictsLeastRecentlyEmitted(t *testing.T) {
+	at := NewAircraftThrottle(time.Minute, 2)
+	start := time.Unix(1700000000, 0)
+
+	at.Allow("aaa111", start)
+	at.Allow("bbb222", start.Add(time.Second))
+
+	// A throttled attempt doesn't make aaa111 more recent
+	if at.Allow("aaa111", start.Add(2*time.Second)) {
+		t.Fatal("aaa111 should still be throttled")
+	}
+
+	// Tracking a third aircraft evicts aaa111, the least recently emitted
+	at.Allow("ccc333", start.Add(3*time.Second))
+	if at.Tracked() != 2 {
+		t.Errorf("Tracked() = %d, want the cap of 2", at.Tracked())
+	}
+	if at.Allow("bbb222", start.Add(4*time.Second)) {
+		t.Error("bbb222 is still tracked and should be throttled")
+	}
+	if !at.Allow("aaa111", start.Add(5*time.Second)) {
+		t.Error("aaa111 was evicted, so its update should be allowed")
+	}
+
+	// Re-adding aaa111 evicted bbb222 in turn, while ccc333 stays throttled
+	if at.Allow("ccc333", start.Add(6*time.Second)) {
+		t.Error("ccc333 is still tracked and should be throttled")
+	}
+	if !at.Allow("bbb222", start.Add(7*time.Second)) {
+		t.Error("bbb222 was evicted, so its update should be allowed")
+	}
+	if at.Tracked() != 2 {
+		t.Errorf("Tracked() = %d, want the cap of 2", at.Tracked())
+	}
+}
+
+func TestAircraftThrottleRefreshesRecencyOnEmit(t *testing.T) {
+	at := NewAircraftThrottle(time.Second, 2)
+	start := time.Unix(1700000000, 0)
+
+	at.Allow("aaa111", start)
+	at.Allow("bbb222", start)
+	// aaa111 emits again, so bbb222 becomes the least recently emitted
+	at.Allow("aaa111", start.Add(time.Second))
+	at.Allow("ccc333", start.Add(time.Second))
+
+	if at.Allow("aaa111", start.Add(1500*time.Millisecond)) {
+		t.Error("aaa111 should have been kept and throttled")
+	}
+	if !at.Allow("bbb222", start.Add(1500*time.Millisecond)) {
+		t.Error("bbb222 should have been evicted and allowed")
+	}
+}
@@ -0,0 +1,61 @@
+package processor
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"flight-event-throttler/pkg/logger"
+)
+
+// DropReporter aggregates dropped events and periodically logs a single summary line
+// instead of logging every drop in the hot path
+type DropReporter struct {
+	logger     logger.Interface
+	interval   time.Duration
+	dropped    atomic.Int64
+	lastReport time.Time // When the current counting period began; owned by Run
+}
+
+// NewDropReporter creates a new drop reporter that summarizes drops every interval
+func NewDropReporter(log logger.Interface, interval time.Duration) *DropReporter {
+	return &DropReporter{
+		logger:   log,
+		interval: interval,
+	}
+}
+
+// Record counts a single dropped event
+func (dr *DropReporter) Record() {
+	dr.dropped.Add(1)
+}
+
+// Run emits a summary every interval until the context is cancelled
+func (dr *DropReporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(dr.interval)
+	defer ticker.Stop()
+
+	dr.lastReport = time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			// Flush whatever was counted since the last tick, which is
+			// usually less than a full interval ago
+			dr.report(time.Now())
+			return
+		case now := <-ticker.C:
+			dr.report(now)
+		}
+	}
+}
+
+// report logs and resets the pending drop count, covering the time since the
+// previous report
+func (dr *DropReporter) report(now time.Time) {
+	elapsed := now.Sub(dr.lastReport)
+	dr.lastReport = now
+
+	if n := dr.dropped.Swap(0); n > 0 {
+		dr.logger.Warn("Dropped %d events in last %v", n, elapsed.Round(time.Millisecond))
+	}
+}
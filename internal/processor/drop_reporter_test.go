@@ -0,0 +1,116 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// dropSummary is one "Dropped %d events in last %v" line
+type dropSummary struct {
+	count   int64
+	elapsed time.Duration
+}
+
+// summaryLogger records the drop summaries a DropReporter logs
+type summaryLogger struct {
+	mu        sync.Mutex
+	summaries []dropSummary
+}
+
+func (l *summaryLogger) Debug(format string, v ...interface{}) {}
+func (l *summaryLogger) Info(format string, v ...interface{})  {}
+func (l *summaryLogger) Error(format string, v ...interface{}) {}
+
+func (l *summaryLogger) Warn(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.summaries = append(l.summaries, dropSummary{count: v[0].(int64), elapsed: v[1].(time.Duration)})
+}
+
+func (l *summaryLogger) logged() []dropSummary {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]dropSummary(nil), l.summaries...)
+}
+
+// startDropReporter runs a reporter until the returned stop function is called
+func startDropReporter(interval time.Duration) (*DropReporter, *summaryLogger, func()) {
+	log := &summaryLogger{}
+	dr := NewDropReporter(log, interval)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		dr.Run(ctx)
+	}()
+	return dr, log, func() {
+		cancel()
+		<-done
+	}
+}
+
+func TestDropReporterPeriodicReport(t *testing.T) {
+	dr, log, stop := startDropReporter(20 * time.Millisecond)
+	defer stop()
+
+	for i := 0; i < 3; i++ {
+		dr.Record()
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(log.logged()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("no summary logged after an interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	summary := log.logged()[0]
+	if summary.count != 3 {
+		t.Errorf("summary count = %d, want 3", summary.count)
+	}
+	if summary.elapsed < 15*time.Millisecond || summary.elapsed > time.Second {
+		t.Errorf("summary covers %v, want about the 20ms interval", summary.elapsed)
+	}
+
+	// The count is reset once reported
+	time.Sleep(60 * time.Millisecond)
+	if got := len(log.logged()); got != 1 {
+		t.Errorf("%d summaries logged, want 1 with no further drops", got)
+	}
+}
+
+func TestDropReporterShutdownFlush(t *testing.T) {
+	dr, log, stop := startDropReporter(time.Hour)
+
+	dr.Record()
+	dr.Record()
+	time.Sleep(30 * time.Millisecond)
+	stop()
+
+	summaries := log.logged()
+	if len(summaries) != 1 {
+		t.Fatalf("%d summaries logged on shutdown, want 1", len(summaries))
+	}
+	if summaries[0].count != 2 {
+		t.Errorf("summary count = %d, want 2", summaries[0].count)
+	}
+	// The flush covers the time actually elapsed, not the hour-long interval
+	if elapsed := summaries[0].elapsed; elapsed < 30*time.Millisecond || elapsed > time.Minute {
+		t.Errorf("flush covers %v, want the ~30ms since the reporter started", elapsed)
+	}
+}
+
+func TestDropReporterSilentWithoutDrops(t *testing.T) {
+	_, log, stop := startDropReporter(10 * time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+	stop()
+
+	if summaries := log.logged(); len(summaries) != 0 {
+		t.Errorf("logged %v without any drops, want nothing", summaries)
+	}
+}
@@ -0,0 +1,88 @@
+package processor
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// minIdleEviction is the shortest time a key must go unused before eviction,
+// so fast-refilling limiters aren't churned on every sweep
+const minIdleEviction = time.Minute
+
+// KeyedRateLimiter applies a separate token bucket to each key, such as an
+// aircraft's ICAO24, so a few very chatty keys can't use up the budget of the
+// rest. Keys idle long enough for their bucket to refill are evicted, which
+// keeps memory bounded without changing behavior: a re-created limiter starts
+// with the same full bucket the evicted one would have had.
+type KeyedRateLimiter struct {
+	eventsPerSec int
+	burst        int
+	idleAfter    time.Duration
+	limiters     map[string]*keyedLimiter
+	lastSweep    time.Time
+	mu           sync.Mutex
+}
+
+type keyedLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewKeyedRateLimiter creates a limiter allowing perKeyEventsPerSec events per
+// second for each key, with bursts of up to burst events
+func NewKeyedRateLimiter(perKeyEventsPerSec, burst int) *KeyedRateLimiter {
+	idleAfter := minIdleEviction
+	if perKeyEventsPerSec > 0 {
+		if refill := time.Duration(burst) * time.Second / time.Duration(perKeyEventsPerSec); refill > idleAfter {
+			idleAfter = refill
+		}
+	}
+
+	return &KeyedRateLimiter{
+		eventsPerSec: perKeyEventsPerSec,
+		burst:        burst,
+		idleAfter:    idleAfter,
+		limiters:     make(map[string]*keyedLimiter),
+		lastSweep:    time.Now(),
+	}
+}
+
+// AllowKey reports whether an event for key may be processed now
+func (kl *KeyedRateLimiter) AllowKey(key string) bool {
+	kl.mu.Lock()
+	defer kl.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(kl.lastSweep) >= kl.idleAfter {
+		kl.evictIdle(now)
+	}
+
+	entry, ok := kl.limiters[key]
+	if !ok {
+		entry = &keyedLimiter{limiter: rate.NewLimiter(rate.Limit(kl.eventsPerSec), kl.burst)}
+		kl.limiters[key] = entry
+	}
+	entry.lastSeen = now
+
+	return entry.limiter.AllowN(now, 1)
+}
+
+// evictIdle drops limiters unused for at least idleAfter (must be called with lock held)
+func (kl *KeyedRateLimiter) evictIdle(now time.Time) {
+	for key, entry := range kl.limiters {
+		if now.Sub(entry.lastSeen) >= kl.idleAfter {
+			delete(kl.limiters, key)
+		}
+	}
+	kl.lastSweep = now
+}
+
+// Tracked returns the number of keys currently holding a limiter
+func (kl *KeyedRateLimiter) Tracked() int {
+	kl.mu.Lock()
+	defer kl.mu.Unlock()
+
+	return len(kl.limiters)
+}
@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+func TestKeyedRateLimiterThrottlesNoisyKey(t *testing.T) {
+	kl := NewKeyedRateLimiter(1, 3)
+
+	allowed := 0
+	for i := 0; i < 20; i++ {
+		if kl.AllowKey("noisy1") {
+			allowed++
+		}
+	}
+	if allowed != 3 {
+		t.Errorf("noisy key allowed %d of 20 events, want its burst of 3", allowed)
+	}
+
+	for _, key := range []string{"quiet1", "quiet2", "quiet3"} {
+		if !kl.AllowKey(key) {
+			t.Errorf("AllowKey(%q) = false while only another key was noisy", key)
+		}
+	}
+	if got := kl.Tracked(); got != 4 {
+		t.Errorf("Tracked() = %d, want 4", got)
+	}
+}
+
+func TestSubmitBatchAppliesKeyedLimiterFirst(t *testing.T) {
+	ep := NewEventProcessor(NewRateLimiter(1000, 100), 100)
+	ep.SetEmergencySquawks(nil)
+	ep.SetKeyedLimiter(NewKeyedRateLimiter(1, 2))
+
+	var dropped []string
+	ep.OnDropped(func(event *model.FlightEvent) {
+		dropped = append(dropped, event.ICAO24)
+	})
+
+	var events []*model.FlightEvent
+	for i := 0; i < 5; i++ {
+		events = append(events, &model.FlightEvent{ICAO24: "noisy1"})
+	}
+	events = append(events, &model.FlightEvent{ICAO24: "quiet1"}, &model.FlightEvent{ICAO24: "quiet2"})
+
+	accepted := ep.SubmitBatch(events)
+	counts := map[string]int{}
+	for _, event := range accepted {
+		counts[event.ICAO24]++
+	}
+	if counts["noisy1"] != 2 || counts["quiet1"] != 1 || counts["quiet2"] != 1 {
+		t.Errorf("accepted per aircraft = %v, want noisy1:2 quiet1:1 quiet2:1", counts)
+	}
+	if len(dropped) != 3 {
+		t.Errorf("dropped %v, want three noisy1 events", dropped)
+	}
+
+	// Throttled aircraft must not use up global tokens
+	if processed, _ := ep.GetStats(); processed != 4 {
+		t.Errorf("global limiter admitted %d events, want 4", processed)
+	}
+}
@@ -0,0 +1,115 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Rate limiting algorithms selectable with rate_limit.algorithm
+const (
+	AlgorithmTokenBucket = "token_bucket"
+	AlgorithmLeakyBucket = "leaky_bucket"
+)
+
+// Limiter is the rate limiting surface the EventProcessor depends on
+type Limiter interface {
+	// Allow reports whether an event may be processed now
+	Allow() bool
+	// Wait blocks until an event may be processed or ctx is cancelled
+	Wait(ctx context.Context) error
+	// GetStats returns how many events were allowed and rejected
+	GetStats() (processed, dropped int64)
+}
+
+// batchLimiter is implemented by limiters that can admit part of a batch up
+// front. Limiters without it have batches queued and paced through Wait.
+type batchLimiter interface {
+	AllowUpTo(n int) int
+}
+
+var (
+	_ Limiter      = (*RateLimiter)(nil)
+	_ batchLimiter = (*RateLimiter)(nil)
+	_ Limiter      = (*LeakyBucketLimiter)(nil)
+)
+
+// LeakyBucketLimiter releases events at a strictly constant rate, one every
+// 1/eventsPerSecond, with no bursts. Unlike the token bucket, idle time does
+// not build up credit: Allow admits an event only once a full interval has
+// passed since the previous one, and Wait schedules each caller into the next
+// free slot.
+type LeakyBucketLimiter struct {
+	interval       time.Duration
+	eventsPerSec   int
+	next           time.Time // Earliest time the next event may leave
+	processedCount int64
+	droppedCount   int64
+	mu             sync.Mutex
+}
+
+// NewLeakyBucketLimiter creates a leaky bucket releasing eventsPerSecond events per second
+func NewLeakyBucketLimiter(eventsPerSecond int) *LeakyBucketLimiter {
+	return &LeakyBucketLimiter{
+		interval:     time.Second / time.Duration(eventsPerSecond),
+		eventsPerSec: eventsPerSecond,
+	}
+}
+
+// Allow reports whether an event may leave the bucket now
+func (lb *LeakyBucketLimiter) Allow() bool {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(lb.next) {
+		lb.droppedCount++
+		return false
+	}
+
+	lb.next = now.Add(lb.interval)
+	lb.processedCount++
+	return true
+}
+
+// Wait blocks until the caller's slot comes up or ctx is cancelled. A
+// cancelled caller's slot is not reclaimed.
+func (lb *LeakyBucketLimiter) Wait(ctx context.Context) error {
+	lb.mu.Lock()
+	now := time.Now()
+	slot := lb.next
+	if slot.Before(now) {
+		slot = now
+	}
+	lb.next = slot.Add(lb.interval)
+	lb.mu.Unlock()
+
+	if delay := slot.Sub(now); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	lb.mu.Lock()
+	lb.processedCount++
+	lb.mu.Unlock()
+	return nil
+}
+
+// GetStats returns current statistics
+func (lb *LeakyBucketLimiter) GetStats() (processed, dropped int64) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	return lb.processedCount, lb.droppedCount
+}
+
+// GetLimit returns the configured rate in events per second
+func (lb *LeakyBucketLimiter) GetLimit() int {
+	return lb.eventsPerSec
+}
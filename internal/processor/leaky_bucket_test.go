@@ -0,0 +1,99 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// burst submits n events at once and returns when each left the processor,
+// relative to submission, and how many were dropped
+func burst(t *testing.T, limiter Limiter, n int) ([]time.Duration, int) {
+	t.Helper()
+
+	ep := NewEventProcessor(limiter, n)
+	ep.SetEmergencySquawks(nil)
+	dropped := 0
+	ep.OnDropped(func(*model.FlightEvent) { dropped++ })
+	ep.Start()
+	defer ep.Stop()
+
+	events := make([]*model.FlightEvent, n)
+	for i := range events {
+		events[i] = &model.FlightEvent{ICAO24: fmt.Sprintf("a%05d", i)}
+	}
+
+	start := time.Now()
+	accepted := len(ep.SubmitBatch(events))
+
+	var released []time.Duration
+	for len(released) < accepted {
+		select {
+		case <-ep.GetOutputChannel():
+			released = append(released, time.Since(start))
+		case <-time.After(2 * time.Second):
+			t.Fatalf("released %d of %d accepted events", len(released), accepted)
+		}
+	}
+	return released, dropped
+}
+
+func TestBurstTokenBucketVersusLeakyBucket(t *testing.T) {
+	const events, perSecond, burstSize = 20, 100, 10
+	interval := time.Second / perSecond
+
+	// The token bucket releases its burst at once and paces the excess
+	released, dropped := burst(t, NewRateLimiter(perSecond, burstSize), events)
+	if len(released) != events || dropped != 0 {
+		t.Fatalf("token bucket released %d and dropped %d, want %d and 0", len(released), dropped, events)
+	}
+	if early := countBefore(released, 5*interval); early < burstSize {
+		t.Errorf("token bucket released %d events at once, want its burst of %d", early, burstSize)
+	}
+	if span, want := released[len(released)-1], (events-burstSize)*interval*9/10; span < want {
+		t.Errorf("token bucket released the excess within %v, want it paced over at least %v", span, want)
+	}
+
+	// The leaky bucket queues every event and releases one per interval
+	released, dropped = burst(t, NewLeakyBucketLimiter(perSecond), events)
+	if len(released) != events || dropped != 0 {
+		t.Fatalf("leaky bucket released %d and dropped %d, want %d and 0", len(released), dropped, events)
+	}
+	if early := countBefore(released, interval/2); early != 1 {
+		t.Errorf("leaky bucket released %d events before the first interval passed, want 1", early)
+	}
+	if span, want := released[len(released)-1]-released[0], (events-1)*interval*9/10; span < want {
+		t.Errorf("leaky bucket released %d events over %v, want at least %v", events, span, want)
+	}
+}
+
+func TestAllowBurstTokenBucketVersusLeakyBucket(t *testing.T) {
+	allowed := func(limiter Limiter) int {
+		n := 0
+		for i := 0; i < 10; i++ {
+			if limiter.Allow() {
+				n++
+			}
+		}
+		return n
+	}
+
+	if got := allowed(NewRateLimiter(1, 5)); got != 5 {
+		t.Errorf("token bucket allowed %d back-to-back events, want its burst of 5", got)
+	}
+	if got := allowed(NewLeakyBucketLimiter(1)); got != 1 {
+		t.Errorf("leaky bucket allowed %d back-to-back events, want 1", got)
+	}
+}
+
+func countBefore(offsets []time.Duration, limit time.Duration) int {
+	n := 0
+	for _, offset := range offsets {
+		if offset < limit {
+			n++
+		}
+	}
+	return n
+}
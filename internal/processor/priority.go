@@ -0,0 +1,53 @@
+package processor
+
+import (
+	"flight-event-throttler/internal/model"
+)
+
+// DefaultEmergencySquawks are the transponder codes for hijack (7500), radio
+// failure (7600) and general emergency (7700)
+var DefaultEmergencySquawks = []string{"7500", "7600", "7700"}
+
+// SetEmergencySquawks replaces the squawk codes treated as emergencies by
+// SubmitPriority and SubmitBatch. It must be set before events are submitted;
+// an empty list disables the priority path.
+func (ep *EventProcessor) SetEmergencySquawks(codes []string) {
+	squawks := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		squawks[code] = struct{}{}
+	}
+	ep.emergencySquawks = squawks
+}
+
+// IsEmergency reports whether the event is squawking an emergency code
+func (ep *EventProcessor) IsEmergency(event *model.FlightEvent) bool {
+	if event == nil || event.Squawk == nil {
+		return false
+	}
+	_, ok := ep.emergencySquawks[*event.Squawk]
+	return ok
+}
+
+// SubmitPriority submits an event, sending emergencies through the priority
+// queue. Emergency events bypass both rate limiters and are drained before
+// any other event; if the priority queue is full the call waits for space
+// rather than dropping, giving up only when the processor stops. Other events
+// are submitted normally with Submit.
+func (ep *EventProcessor) SubmitPriority(event *model.FlightEvent) bool {
+	if !ep.IsEmergency(event) {
+		return ep.Submit(event)
+	}
+
+	if ep.ctx.Err() != nil {
+		ep.dropped(event)
+		return false
+	}
+
+	select {
+	case ep.priority <- event:
+		return true
+	case <-ep.ctx.Done():
+		ep.dropped(event)
+		return false
+	}
+}
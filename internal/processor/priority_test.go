@@ -0,0 +1,109 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+func squawking(icao24, squawk string) *model.FlightEvent {
+	return &model.FlightEvent{ICAO24: icao24, Squawk: &squawk}
+}
+
+func TestEmergencyDeliveredWhileNormalQueueFull(t *testing.T) {
+	ep := NewEventProcessor(NewRateLimiter(1000, 100), 2)
+	defer ep.Stop()
+
+	var dropped []string
+	ep.OnDropped(func(event *model.FlightEvent) {
+		dropped = append(dropped, event.ICAO24)
+	})
+
+	for _, icao24 := range []string{"norm01", "norm02", "norm03"} {
+		ep.SubmitPriority(squawking(icao24, "1000"))
+	}
+	if len(dropped) != 1 || dropped[0] != "norm03" {
+		t.Fatalf("dropped %v, want [norm03] once the normal queue filled", dropped)
+	}
+
+	if !ep.SubmitPriority(squawking("emrg01", "7700")) {
+		t.Fatal("SubmitPriority() dropped an emergency while the normal queue was full")
+	}
+	if accepted := ep.SubmitBatch([]*model.FlightEvent{squawking("emrg02", "7600")}); len(accepted) != 1 {
+		t.Fatal("SubmitBatch() dropped an emergency while the normal queue was full")
+	}
+
+	ep.Start()
+	var got []string
+	for len(got) < 4 {
+		select {
+		case event := <-ep.GetOutputChannel():
+			got = append(got, event.ICAO24)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("received %v, want 4 events", got)
+		}
+	}
+
+	want := []string{"emrg01", "emrg02", "norm01", "norm02"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("delivered %v, want emergencies first: %v", got, want)
+		}
+	}
+}
+
+func TestEmergencySquawksConfigurable(t *testing.T) {
+	ep := NewEventProcessor(NewRateLimiter(1000, 100), 2)
+	defer ep.Stop()
+
+	if !ep.IsEmergency(squawking("emrg01", "7700")) {
+		t.Error("7700 is not an emergency by default")
+	}
+
+	ep.SetEmergencySquawks([]string{"7777"})
+	if ep.IsEmergency(squawking("emrg01", "7700")) {
+		t.Error("7700 still an emergency after replacing the codes")
+	}
+	if !ep.IsEmergency(squawking("mil001", "7777")) {
+		t.Error("configured code 7777 is not an emergency")
+	}
+	if ep.IsEmergency(&model.FlightEvent{ICAO24: "nosq01"}) {
+		t.Error("event without a squawk is an emergency")
+	}
+
+	ep.SetEmergencySquawks(nil)
+	if ep.IsEmergency(squawking("mil001", "7777")) {
+		t.Error("an empty code list did not disable the priority path")
+	}
+}
+
+func TestSubmitBatchLimitedRateLimitsEmergencies(t *testing.T) {
+	rl := NewRateLimiter(1, 1)
+	ep := NewEventProcessor(rl, 10)
+	defer ep.Stop()
+
+	// Without the priority path emergencies share the single token and the
+	// rest are paced behind it
+	accepted := ep.SubmitBatchLimited([]*model.FlightEvent{
+		squawking("emrg01", "7700"),
+		squawking("emrg02", "7500"),
+	})
+	if len(accepted) != 2 {
+		t.Fatalf("accepted %d events, want both queued", len(accepted))
+	}
+	if len(ep.priority) != 0 {
+		t.Errorf("%d events took the priority path", len(ep.priority))
+	}
+	if len(ep.admitted) != 1 || len(ep.inputChan) != 1 {
+		t.Errorf("%d admitted and %d paced, want 1 each", len(ep.admitted), len(ep.inputChan))
+	}
+	if tokens := rl.Tokens(); tokens > 0.1 {
+		t.Errorf("Tokens() = %.2f, want the emergency to have used the token", tokens)
+	}
+
+	// SubmitBatch still lets emergencies skip the rate limits
+	if accepted := ep.SubmitBatch([]*model.FlightEvent{squawking("emrg03", "7700")}); len(accepted) != 1 || len(ep.priority) != 1 {
+		t.Error("SubmitBatch() rate limited an emergency")
+	}
+}
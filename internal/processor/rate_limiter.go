@@ -68,6 +68,41 @@ func (rl *RateLimiter) AllowN(n int) bool {
 	return allowed
 }
 
+// AllowUpTo admits as many of n events as the limiter currently allows, never
+// more than the burst size, and returns the admitted count. Unlike AllowN, a
+// batch larger than the available tokens is partially admitted rather than
+// rejected outright. The remainder is not counted: the caller decides whether
+// to pace it through Wait or drop it.
+func (rl *RateLimiter) AllowUpTo(n int) int {
+	if n <= 0 {
+		return 0
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	admitted := n
+	if admitted > rl.burstSize {
+		admitted = rl.burstSize
+	}
+	if available := int(rl.limiter.TokensAt(now)); admitted > available {
+		admitted = available
+	}
+	if admitted < 0 {
+		admitted = 0
+	}
+
+	// Wait callers can race for tokens outside our lock, so fall back to
+	// admitting nothing rather than overdrawing the bucket
+	if admitted > 0 && !rl.limiter.AllowN(now, admitted) {
+		admitted = 0
+	}
+
+	rl.processedCount += int64(admitted)
+	return admitted
+}
+
 // Reserve reserves a token for future use and returns a Reservation
 func (rl *RateLimiter) Reserve() *rate.Reservation {
 	rl.mu.Lock()
@@ -105,6 +140,15 @@ func (rl *RateLimiter) ResetStats() {
 	rl.droppedCount = 0
 }
 
+// Tokens returns the number of tokens currently available, i.e. how much
+// burst capacity remains
+func (rl *RateLimiter) Tokens() float64 {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+
+	return rl.limiter.Tokens()
+}
+
 // GetLimit returns current rate limit settings
 func (rl *RateLimiter) GetLimit() (eventsPerSec int, burstSize int) {
 	rl.mu.RLock()
@@ -115,24 +159,35 @@ func (rl *RateLimiter) GetLimit() (eventsPerSec int, burstSize int) {
 
 // EventProcessor handles event processing with rate limiting and buffering
 type EventProcessor struct {
-	rateLimiter *RateLimiter
+	rateLimiter Limiter
+	keyLimiter  *KeyedRateLimiter // Optional per-aircraft limiter consulted before rateLimiter
 	inputChan   chan *model.FlightEvent
+	admitted    chan *model.FlightEvent // Events already admitted by SubmitBatch
+	priority    chan *model.FlightEvent // Emergency events, drained before all others
 	outputChan  chan *model.FlightEvent
 	ctx         context.Context
 	cancel      context.CancelFunc
 	wg          sync.WaitGroup
+	stopOnce    sync.Once
+	onDropped   func(event *model.FlightEvent)
+
+	emergencySquawks map[string]struct{}
 }
 
 // NewEventProcessor creates a new event processor
-func NewEventProcessor(rateLimiter *RateLimiter, bufferSize int) *EventProcessor {
+func NewEventProcessor(rateLimiter Limiter, bufferSize int) *EventProcessor {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &EventProcessor{
+	ep := &EventProcessor{
 		rateLimiter: rateLimiter,
 		inputChan:   make(chan *model.FlightEvent, bufferSize),
+		admitted:    make(chan *model.FlightEvent, bufferSize),
+		priority:    make(chan *model.FlightEvent, bufferSize),
 		outputChan:  make(chan *model.FlightEvent, bufferSize),
 		ctx:         ctx,
 		cancel:      cancel,
 	}
+	ep.SetEmergencySquawks(DefaultEmergencySquawks)
+	return ep
 }
 
 // Start begins processing events
@@ -146,9 +201,34 @@ func (ep *EventProcessor) processEvents() {
 	defer ep.wg.Done()
 
 	for {
+		// Emergencies jump the queue: drain them before looking at anything else
+		select {
+		case event := <-ep.priority:
+			if !ep.emit(event) {
+				return
+			}
+			continue
+		default:
+		}
+
+		// Events whose tokens were already taken go next, so a batch's burst
+		// isn't held up behind its paced remainder
+		select {
+		case event := <-ep.admitted:
+			if !ep.emit(event) {
+				return
+			}
+			continue
+		default:
+		}
+
 		select {
 		case <-ep.ctx.Done():
 			return
+		case event := <-ep.priority:
+			if !ep.emit(event) {
+				return
+			}
 		case event := <-ep.inputChan:
 			if event == nil {
 				continue
@@ -166,21 +246,207 @@ func (ep *EventProcessor) processEvents() {
 			case <-ep.ctx.Done():
 				return
 			}
+		case event := <-ep.admitted:
+			// Tokens were already taken when the batch was submitted
+			select {
+			case ep.outputChan <- event:
+			case <-ep.ctx.Done():
+				return
+			}
 		}
 	}
 }
 
+// emit sends an event to the output channel, reporting false if the processor
+// stopped first
+func (ep *EventProcessor) emit(event *model.FlightEvent) bool {
+	select {
+	case ep.outputChan <- event:
+		return true
+	case <-ep.ctx.Done():
+		return false
+	}
+}
+
+// OnDropped registers a callback invoked with each event rejected by Submit,
+// e.g. to sample drops or route them to a dead-letter sink. It must be set
+// before events are submitted and should return quickly since it runs on the
+// submitting goroutine. A nil callback disables the hook.
+func (ep *EventProcessor) OnDropped(fn func(event *model.FlightEvent)) {
+	ep.onDropped = fn
+}
+
+// SetKeyedLimiter makes submission consult a per-aircraft limiter, keyed by
+// ICAO24, before the global one. Events it rejects are reported as dropped
+// without using global tokens. It must be set before events are submitted;
+// nil disables it.
+func (ep *EventProcessor) SetKeyedLimiter(kl *KeyedRateLimiter) {
+	ep.keyLimiter = kl
+}
+
+// allowKey reports whether the per-aircraft limiter, if any, admits the event
+func (ep *EventProcessor) allowKey(event *model.FlightEvent) bool {
+	return ep.keyLimiter == nil || event == nil || ep.keyLimiter.AllowKey(event.ICAO24)
+}
+
 // Submit submits an event for processing
 func (ep *EventProcessor) Submit(event *model.FlightEvent) bool {
+	if ep.ctx.Err() != nil || !ep.allowKey(event) {
+		ep.dropped(event)
+		return false
+	}
+
 	select {
 	case ep.inputChan <- event:
 		return true
 	case <-ep.ctx.Done():
+		ep.dropped(event)
 		return false
 	default:
 		// Channel is full
+		ep.dropped(event)
+		return false
+	}
+}
+
+// SubmitWithTimeout submits an event like Submit, but when the queue is full it
+// waits up to timeout for space before dropping the event, applying brief
+// backpressure instead of shedding load immediately. It also gives up when the
+// processor is stopped.
+func (ep *EventProcessor) SubmitWithTimeout(event *model.FlightEvent, timeout time.Duration) bool {
+	if ep.ctx.Err() != nil || !ep.allowKey(event) {
+		ep.dropped(event)
 		return false
 	}
+
+	// Take the fast path first so a free slot never allocates a timer
+	select {
+	case ep.inputChan <- event:
+		return true
+	default:
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case ep.inputChan <- event:
+		return true
+	case <-ep.ctx.Done():
+		ep.dropped(event)
+		return false
+	case <-timer.C:
+		ep.dropped(event)
+		return false
+	}
+}
+
+// SubmitBatch submits a batch of events, admitting as many as the rate limiter
+// currently allows at once and queueing the rest to be paced out as tokens
+// refill, instead of rejecting the whole batch when it exceeds the burst size.
+// Events that don't fit in the queue are reported as dropped. Emergency events take the priority path first and
+// are never rate limited. The accepted events are returned with emergencies
+// first and the rest in order.
+func (ep *EventProcessor) SubmitBatch(events []*model.FlightEvent) []*model.FlightEvent {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var accepted []*model.FlightEvent
+	if len(ep.emergencySquawks) > 0 {
+		routine := make([]*model.FlightEvent, 0, len(events))
+		for _, event := range events {
+			if !ep.IsEmergency(event) {
+				routine = append(routine, event)
+			} else if ep.SubmitPriority(event) {
+				accepted = append(accepted, event)
+			}
+		}
+		events = routine
+	}
+
+	return ep.submitLimited(events, accepted)
+}
+
+// SubmitBatchLimited submits a batch like SubmitBatch, but without the
+// priority path: emergencies are rate limited like any other event. Use it
+// for events from untrusted producers, who could otherwise bypass the limits
+// by setting an emergency squawk.
+func (ep *EventProcessor) SubmitBatchLimited(events []*model.FlightEvent) []*model.FlightEvent {
+	if len(events) == 0 {
+		return nil
+	}
+	return ep.submitLimited(events, nil)
+}
+
+// submitLimited runs events through the per-aircraft and global rate limits,
+// appending those it queues to accepted
+func (ep *EventProcessor) submitLimited(events, accepted []*model.FlightEvent) []*model.FlightEvent {
+	// Filter out chatty aircraft first so they don't consume global tokens
+	if ep.keyLimiter != nil {
+		allowed := make([]*model.FlightEvent, 0, len(events))
+		for _, event := range events {
+			if ep.allowKey(event) {
+				allowed = append(allowed, event)
+			} else {
+				ep.dropped(event)
+			}
+		}
+		events = allowed
+	}
+
+	// Limiters that can't admit part of a batch up front, such as the leaky
+	// bucket, get the batch queued and paced by the processing loop instead
+	bl, ok := ep.rateLimiter.(batchLimiter)
+	if !ok {
+		return ep.queue(events, accepted)
+	}
+
+	admitted := 0
+	if ep.ctx.Err() == nil {
+		admitted = bl.AllowUpTo(len(events))
+	}
+
+	// The share covered by the available tokens is released at once
+	for _, event := range events[:admitted] {
+		select {
+		case ep.admitted <- event:
+			accepted = append(accepted, event)
+		default:
+			// Queue is full
+			ep.dropped(event)
+		}
+	}
+
+	// The rest is paced out as tokens refill rather than dropped
+	return ep.queue(events[admitted:], accepted)
+}
+
+// queue puts events on the input queue to be paced through Wait, appending
+// those it queues to accepted and dropping those that don't fit
+func (ep *EventProcessor) queue(events, accepted []*model.FlightEvent) []*model.FlightEvent {
+	for _, event := range events {
+		if ep.ctx.Err() != nil {
+			ep.dropped(event)
+			continue
+		}
+
+		select {
+		case ep.inputChan <- event:
+			accepted = append(accepted, event)
+		default:
+			// Queue is full
+			ep.dropped(event)
+		}
+	}
+	return accepted
+}
+
+// dropped notifies the OnDropped hook if one is registered
+func (ep *EventProcessor) dropped(event *model.FlightEvent) {
+	if ep.onDropped != nil {
+		ep.onDropped(event)
+	}
 }
 
 // GetOutputChannel returns the output channel for processed events
@@ -188,12 +454,17 @@ func (ep *EventProcessor) GetOutputChannel() <-chan *model.FlightEvent {
 	return ep.outputChan
 }
 
-// Stop gracefully stops the processor
+// Stop gracefully stops the processor. It is safe to call more than once and
+// concurrently with Submit: the input channels are never closed, since a
+// submitter could still be sending on them, and submissions after Stop are
+// dropped. Only the output channel is closed, once the processing loop (its
+// sole sender) has exited.
 func (ep *EventProcessor) Stop() {
-	ep.cancel()
-	ep.wg.Wait()
-	close(ep.inputChan)
-	close(ep.outputChan)
+	ep.stopOnce.Do(func() {
+		ep.cancel()
+		ep.wg.Wait()
+		close(ep.outputChan)
+	})
 }
 
 // GetStats returns processing statistics
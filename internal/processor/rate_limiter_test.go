@@ -0,0 +1,135 @@
+package processor
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+func TestTokensDecreaseWithAllow(t *testing.T) {
+	// One token a second, so refill between calls stays far below 0.1
+	rl := NewRateLimiter(1, 5)
+
+	if got := rl.Tokens(); math.Abs(got-5) > 0.1 {
+		t.Fatalf("Tokens() = %.2f on a new limiter, want the burst of 5", got)
+	}
+
+	for want := 4.0; want >= 0; want-- {
+		if !rl.Allow() {
+			t.Fatalf("Allow() = false with %.2f tokens left", rl.Tokens())
+		}
+		if got := rl.Tokens(); math.Abs(got-want) > 0.1 {
+			t.Errorf("Tokens() = %.2f after Allow, want %.0f", got, want)
+		}
+	}
+
+	if rl.Allow() {
+		t.Error("Allow() = true with the bucket empty")
+	}
+	if got := rl.Tokens(); got > 0.1 {
+		t.Errorf("Tokens() = %.2f after a rejected Allow, want about 0", got)
+	}
+}
+
+func TestAllowUpToCapsAtAvailableTokens(t *testing.T) {
+	rl := NewRateLimiter(1, 5)
+
+	if got := rl.AllowUpTo(3); got != 3 {
+		t.Errorf("AllowUpTo(3) = %d with 5 tokens, want 3", got)
+	}
+	if got := rl.AllowUpTo(10); got != 2 {
+		t.Errorf("AllowUpTo(10) = %d with 2 tokens left, want 2", got)
+	}
+	if got := rl.AllowUpTo(10); got != 0 {
+		t.Errorf("AllowUpTo(10) = %d with the bucket empty, want 0", got)
+	}
+
+	// The remainder is left to the caller, not counted as dropped
+	if processed, dropped := rl.GetStats(); processed != 5 || dropped != 0 {
+		t.Errorf("GetStats() = %d, %d, want 5 processed and 0 dropped", processed, dropped)
+	}
+}
+
+// recordDrops registers an OnDropped hook collecting the ICAO24 of each drop
+func recordDrops(ep *EventProcessor) *[]string {
+	var dropped []string
+	ep.OnDropped(func(event *model.FlightEvent) {
+		dropped = append(dropped, event.ICAO24)
+	})
+	return &dropped
+}
+
+func TestOnDroppedReportsEachRejectedEvent(t *testing.T) {
+	ep := NewEventProcessor(NewRateLimiter(1000, 100), 2)
+	defer ep.Stop()
+	dropped := recordDrops(ep)
+
+	for i := 1; i <= 4; i++ {
+		ep.Submit(&model.FlightEvent{ICAO24: fmt.Sprintf("evt%03d", i)})
+	}
+
+	// Queued events are not reported, only those that didn't fit
+	if got := fmt.Sprint(*dropped); got != "[evt003 evt004]" {
+		t.Errorf("dropped %s, want [evt003 evt004]", got)
+	}
+}
+
+func TestOnDroppedPartitionsBatch(t *testing.T) {
+	ep := NewEventProcessor(NewRateLimiter(1000, 2), 1)
+	defer ep.Stop()
+	dropped := recordDrops(ep)
+
+	batch := make([]*model.FlightEvent, 5)
+	for i := range batch {
+		batch[i] = &model.FlightEvent{ICAO24: fmt.Sprintf("evt%03d", i)}
+	}
+	accepted := ep.SubmitBatch(batch)
+
+	// Every event is either accepted or reported dropped, never both
+	seen := make(map[string]int)
+	for _, event := range accepted {
+		seen[event.ICAO24]++
+	}
+	for _, icao24 := range *dropped {
+		seen[icao24]++
+	}
+	if len(*dropped) == 0 {
+		t.Fatal("no drops reported although the batch overflowed the queues")
+	}
+	for _, event := range batch {
+		if seen[event.ICAO24] != 1 {
+			t.Errorf("%s accounted for %d times, want once", event.ICAO24, seen[event.ICAO24])
+		}
+	}
+}
+
+func TestOnDroppedAfterStop(t *testing.T) {
+	ep := NewEventProcessor(NewRateLimiter(1000, 100), 10)
+	dropped := recordDrops(ep)
+	ep.Start()
+	ep.Stop()
+
+	ep.Submit(&model.FlightEvent{ICAO24: "late01"})
+	ep.SubmitBatch([]*model.FlightEvent{{ICAO24: "late02"}, {ICAO24: "late03"}})
+
+	if got := fmt.Sprint(*dropped); got != "[late01 late02 late03]" {
+		t.Errorf("dropped %s, want every submission after Stop", got)
+	}
+}
+
+func TestOnDroppedNilDisablesHook(t *testing.T) {
+	ep := NewEventProcessor(NewRateLimiter(1000, 100), 1)
+	defer ep.Stop()
+	dropped := recordDrops(ep)
+	ep.OnDropped(nil)
+
+	ep.Submit(&model.FlightEvent{ICAO24: "evt001"})
+	if ep.Submit(&model.FlightEvent{ICAO24: "evt002"}) {
+		t.Fatal("Submit() into a full queue succeeded")
+	}
+	if len(*dropped) != 0 {
+		t.Errorf("dropped %v reported after the hook was removed", *dropped)
+	}
+}
@@ -0,0 +1,156 @@
+package processor
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// fullProcessor returns a processor, not started, whose one-slot queue is full
+func fullProcessor(t *testing.T) *EventProcessor {
+	t.Helper()
+
+	ep := NewEventProcessor(NewRateLimiter(1000, 100), 1)
+	t.Cleanup(ep.Stop)
+	if !ep.Submit(&model.FlightEvent{ICAO24: "first1"}) {
+		t.Fatal("Submit() into an empty queue failed")
+	}
+	if ep.Submit(&model.FlightEvent{ICAO24: "extra1"}) {
+		t.Fatal("Submit() into a full queue succeeded")
+	}
+	return ep
+}
+
+func TestSubmitWithTimeoutWaitsForSpace(t *testing.T) {
+	ep := fullProcessor(t)
+
+	freed := make(chan time.Time, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		freed <- time.Now()
+		<-ep.inputChan
+	}()
+
+	if !ep.SubmitWithTimeout(&model.FlightEvent{ICAO24: "second"}, 2*time.Second) {
+		t.Fatal("SubmitWithTimeout() = false although space freed up before the timeout")
+	}
+	returned := time.Now()
+	if freedAt := <-freed; returned.Before(freedAt) {
+		t.Error("SubmitWithTimeout() returned before the queue had space")
+	}
+	if event := <-ep.inputChan; event.ICAO24 != "second" {
+		t.Errorf("queued %q, want second", event.ICAO24)
+	}
+}
+
+func TestSubmitWithTimeoutGivesUp(t *testing.T) {
+	ep := fullProcessor(t)
+
+	var dropped int
+	ep.OnDropped(func(*model.FlightEvent) { dropped++ })
+
+	start := time.Now()
+	if ep.SubmitWithTimeout(&model.FlightEvent{ICAO24: "second"}, 30*time.Millisecond) {
+		t.Fatal("SubmitWithTimeout() = true with the queue still full")
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("SubmitWithTimeout() gave up after %v, before the 30ms timeout", elapsed)
+	}
+	if dropped != 1 {
+		t.Errorf("dropped %d events, want 1", dropped)
+	}
+}
+
+func TestSubmitWithTimeoutReturnsOnStop(t *testing.T) {
+	ep := fullProcessor(t)
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		ep.Stop()
+	}()
+
+	start := time.Now()
+	if ep.SubmitWithTimeout(&model.FlightEvent{ICAO24: "second"}, 5*time.Second) {
+		t.Fatal("SubmitWithTimeout() = true after the processor stopped")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("SubmitWithTimeout() waited %v instead of returning on Stop", elapsed)
+	}
+}
+
+func TestConcurrentSubmitAndStop(t *testing.T) {
+	for round := 0; round < 20; round++ {
+		ep := NewEventProcessor(NewRateLimiter(100000, 1000), 16)
+		ep.Start()
+		go func() {
+			for range ep.GetOutputChannel() {
+			}
+		}()
+
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for i := 0; i < 16; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				<-start
+				event := &model.FlightEvent{ICAO24: fmt.Sprintf("a%05d", i)}
+				for j := 0; j < 200; j++ {
+					switch j % 3 {
+					case 0:
+						ep.Submit(event)
+					case 1:
+						ep.SubmitWithTimeout(event, time.Millisecond)
+					default:
+						ep.SubmitBatch([]*model.FlightEvent{event, event})
+					}
+				}
+			}(i)
+		}
+
+		close(start)
+		ep.Stop()
+		ep.Stop()
+		wg.Wait()
+
+		// Submissions after Stop are dropped rather than panicking
+		if ep.Submit(&model.FlightEvent{ICAO24: "late01"}) {
+			t.Fatal("Submit() after Stop succeeded")
+		}
+	}
+}
+
+func TestSubmitBatchPacesEventsBeyondBurst(t *testing.T) {
+	const perSecond, burstSize, events = 50, 5, 20
+	ep := NewEventProcessor(NewRateLimiter(perSecond, burstSize), events)
+	dropped := 0
+	ep.OnDropped(func(*model.FlightEvent) { dropped++ })
+	ep.Start()
+	defer ep.Stop()
+
+	batch := make([]*model.FlightEvent, events)
+	for i := range batch {
+		batch[i] = &model.FlightEvent{ICAO24: fmt.Sprintf("a%05d", i)}
+	}
+
+	start := time.Now()
+	if accepted := ep.SubmitBatch(batch); len(accepted) != events || dropped != 0 {
+		t.Fatalf("accepted %d and dropped %d of a batch of %d, want all queued", len(accepted), dropped, events)
+	}
+
+	for i := 0; i < events; i++ {
+		select {
+		case <-ep.GetOutputChannel():
+		case <-time.After(2 * time.Second):
+			t.Fatalf("only %d of %d events released", i, events)
+		}
+	}
+
+	// The burst leaves at once, the other 15 at 50 per second
+	if elapsed, want := time.Since(start), (events-burstSize)*time.Second/perSecond*9/10; elapsed < want {
+		t.Errorf("released %d events in %v, want them paced over at least %v", events, elapsed, want)
+	}
+}
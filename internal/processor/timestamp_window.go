@@ -0,0 +1,32 @@
+package processor
+
+import (
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+// TimestampWindow rejects events whose timestamps fall outside
+// [now-MaxPast, now+MaxFuture], keeping sliding window expiry sane when
+// ingesting historical data. A zero bound disables that side of the check.
+type TimestampWindow struct {
+	MaxPast   time.Duration
+	MaxFuture time.Duration
+}
+
+// Accept reports whether the event's timestamp is within the acceptance window
+func (tw TimestampWindow) Accept(event *model.FlightEvent, now time.Time) bool {
+	if event == nil {
+		return false
+	}
+
+	if tw.MaxPast > 0 && event.Timestamp.Before(now.Add(-tw.MaxPast)) {
+		return false
+	}
+
+	if tw.MaxFuture > 0 && event.Timestamp.After(now.Add(tw.MaxFuture)) {
+		return false
+	}
+
+	return true
+}
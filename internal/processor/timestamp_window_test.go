@@ -0,0 +1,68 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/model"
+)
+
+func TestTimestampWindowAccept(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	window := TimestampWindow{MaxPast: time.Hour, MaxFuture: time.Minute}
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		want   bool
+	}{
+		{"now", 0, true},
+		{"within past bound", -59 * time.Minute, true},
+		{"at past bound", -time.Hour, true},
+		{"beyond past bound", -time.Hour - time.Second, false},
+		{"within future bound", 30 * time.Second, true},
+		{"at future bound", time.Minute, true},
+		{"beyond future bound", time.Minute + time.Second, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &model.FlightEvent{ICAO24: "abc123", Timestamp: now.Add(tt.offset)}
+			if got := window.Accept(event, now); got != tt.want {
+				t.Errorf("Accept(now%+v) = %v, want %v", tt.offset, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimestampWindowZeroBoundsDisableChecks(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	past := &model.FlightEvent{ICAO24: "abc123", Timestamp: now.Add(-365 * 24 * time.Hour)}
+	future := &model.FlightEvent{ICAO24: "abc123", Timestamp: now.Add(365 * 24 * time.Hour)}
+
+	tests := []struct {
+		name       string
+		window     TimestampWindow
+		wantPast   bool
+		wantFuture bool
+	}{
+		{"both disabled", TimestampWindow{}, true, true},
+		{"past only", TimestampWindow{MaxPast: time.Hour}, false, true},
+		{"future only", TimestampWindow{MaxFuture: time.Hour}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Accept(past, now); got != tt.wantPast {
+				t.Errorf("Accept(year old) = %v, want %v", got, tt.wantPast)
+			}
+			if got := tt.window.Accept(future, now); got != tt.wantFuture {
+				t.Errorf("Accept(year ahead) = %v, want %v", got, tt.wantFuture)
+			}
+		})
+	}
+}
+
+func TestTimestampWindowRejectsNil(t *testing.T) {
+	if (TimestampWindow{}).Accept(nil, time.Now()) {
+		t.Error("Accept(nil) = true, want false")
+	}
+}
@@ -0,0 +1,105 @@
+package processor
+
+import (
+	"context"
+	"time"
+
+	"flight-event-throttler/internal/metrics"
+	"flight-event-throttler/pkg/logger"
+)
+
+// Watchdog actions taken when the pipeline stalls
+const (
+	WatchdogActionLog           = "log"
+	WatchdogActionRestartPoller = "restart_poller"
+	WatchdogActionMarkUnready   = "mark_unready"
+)
+
+// Watchdog detects a stalled pipeline: events keep arriving at the submit
+// stage but none have been processed for the stall threshold, e.g. because the
+// processing goroutine is wedged and the queue has filled up. Stalls are
+// detected from processed-count deltas, so a quiet feed is never reported.
+type Watchdog struct {
+	metrics   *metrics.Metrics
+	threshold time.Duration
+	interval  time.Duration // How often progress is checked
+	logger    logger.Interface
+	onStall   func()
+	onRecover func()
+}
+
+// NewWatchdog creates a watchdog that reports a stall once no events have
+// been processed for threshold while the feed is still delivering
+func NewWatchdog(m *metrics.Metrics, threshold time.Duration, log logger.Interface) *Watchdog {
+	interval := threshold / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	return &Watchdog{
+		metrics:   m,
+		threshold: threshold,
+		interval:  interval,
+		logger:    log,
+	}
+}
+
+// OnStall registers a callback invoked once each time a stall is detected
+func (wd *Watchdog) OnStall(fn func()) {
+	wd.onStall = fn
+}
+
+// OnRecover registers a callback invoked when processing resumes after a stall
+func (wd *Watchdog) OnRecover(fn func()) {
+	wd.onRecover = fn
+}
+
+// Run checks for stalls until the context is cancelled
+func (wd *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(wd.interval)
+	defer ticker.Stop()
+
+	lastProcessed := wd.metrics.GetEventsProcessed()
+	lastDropped := wd.metrics.GetEventsDropped()
+	lastProgress := time.Now()
+	attempted := int64(0) // Events dropped at the submit stage since the last progress
+	stalled := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			processed := wd.metrics.GetEventsProcessed()
+			dropped := wd.metrics.GetEventsDropped()
+			attempted += dropped - lastDropped
+			lastDropped = dropped
+
+			if processed != lastProcessed {
+				lastProcessed = processed
+				lastProgress = now
+				attempted = 0
+
+				if stalled {
+					stalled = false
+					wd.logger.Info("Pipeline recovered, events are being processed again")
+					if wd.onRecover != nil {
+						wd.onRecover()
+					}
+				}
+				continue
+			}
+
+			if stalled || attempted == 0 || now.Sub(lastProgress) < wd.threshold {
+				continue
+			}
+
+			stalled = true
+			wd.logger.Warn("Pipeline stalled: no events processed for %v while %d events were dropped",
+				now.Sub(lastProgress).Round(time.Second), attempted)
+			if wd.onStall != nil {
+				wd.onStall()
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package processor
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/metrics"
+	"flight-event-throttler/pkg/logger"
+)
+
+// runWatchdog starts a fast-ticking watchdog over fresh metrics, reporting
+// stalls and recoveries on the returned channels
+func runWatchdog(t *testing.T, threshold time.Duration) (*metrics.Metrics, <-chan struct{}, <-chan struct{}) {
+	t.Helper()
+
+	m := metrics.NewMetrics()
+	t.Cleanup(m.Close)
+
+	wd := NewWatchdog(m, threshold, logger.NewWithWriter("ERROR", io.Discard))
+	wd.interval = 5 * time.Millisecond
+
+	stalls := make(chan struct{}, 10)
+	recoveries := make(chan struct{}, 10)
+	wd.OnStall(func() { stalls <- struct{}{} })
+	wd.OnRecover(func() { recoveries <- struct{}{} })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wd.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return m, stalls, recoveries
+}
+
+// dropUntilStall keeps dropping events, as a wedged pipeline would, until a
+// stall is reported
+func dropUntilStall(t *testing.T, m *metrics.Metrics, stalls <-chan struct{}) {
+	t.Helper()
+
+	deadline := time.After(2 * time.Second)
+	for {
+		m.IncrementEventsDropped()
+		select {
+		case <-stalls:
+			return
+		case <-deadline:
+			t.Fatal("no stall reported")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func expectSignal(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+
+	select {
+	case <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no %s reported", what)
+	}
+}
+
+func expectNoSignal(t *testing.T, ch <-chan struct{}, wait time.Duration, what string) {
+	t.Helper()
+
+	select {
+	case <-ch:
+		t.Fatalf("unexpected %s reported", what)
+	case <-time.After(wait):
+	}
+}
+
+func TestWatchdogReportsStallOnce(t *testing.T) {
+	m, stalls, _ := runWatchdog(t, 20*time.Millisecond)
+
+	// Events keep being dropped while nothing is processed
+	dropUntilStall(t, m, stalls)
+
+	m.IncrementEventsDropped()
+	expectNoSignal(t, stalls, 50*time.Millisecond, "second stall")
+}
+
+func TestWatchdogRecoversWhenProcessingResumes(t *testing.T) {
+	m, stalls, recoveries := runWatchdog(t, 20*time.Millisecond)
+
+	dropUntilStall(t, m, stalls)
+
+	m.IncrementEventsProcessed()
+	expectSignal(t, recoveries, "recovery")
+
+	// A fresh stall after recovering is reported again
+	dropUntilStall(t, m, stalls)
+}
+
+func TestWatchdogIgnoresQuietFeed(t *testing.T) {
+	_, stalls, _ := runWatchdog(t, 20*time.Millisecond)
+
+	// Nothing arrives, so nothing is stalled
+	expectNoSignal(t, stalls, 100*time.Millisecond, "stall")
+}
+
+func TestWatchdogIgnoresDropsWhileProcessing(t *testing.T) {
+	m, stalls, _ := runWatchdog(t, 50*time.Millisecond)
+
+	for i := 0; i < 20; i++ {
+		m.IncrementEventsDropped()
+		m.IncrementEventsProcessed()
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(stalls) > 0 {
+		t.Error("stall reported although events were still being processed")
+	}
+}
+
+func TestNewWatchdogCheckInterval(t *testing.T) {
+	log := logger.NewWithWriter("ERROR", io.Discard)
+
+	tests := []struct {
+		threshold time.Duration
+		want      time.Duration
+	}{
+		{time.Minute, 15 * time.Second},
+		{2 * time.Second, time.Second},
+	}
+	for _, tt := range tests {
+		if got := NewWatchdog(nil, tt.threshold, log).interval; got != tt.want {
+			t.Errorf("threshold %v: check interval = %v, want %v", tt.threshold, got, tt.want)
+		}
+	}
+}
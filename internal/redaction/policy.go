@@ -0,0 +1,176 @@
+package redaction
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"flight-event-throttler/internal/model"
+)
+
+// Rule blanks the listed fields for events matching any of its ICAO24 ranges
+// or origin countries
+type Rule struct {
+	ICAO24Ranges []string // Inclusive hex ranges, e.g. "ae0000-afffff"
+	Countries    []string // Exact origin country names
+	Fields       []string // JSON field names to blank
+}
+
+// icaoRange is a parsed inclusive ICAO24 address range
+type icaoRange struct {
+	low  uint32
+	high uint32
+}
+
+// compiledRule is a rule with its matchers pre-parsed
+type compiledRule struct {
+	ranges    []icaoRange
+	countries map[string]bool
+	fields    []string
+}
+
+// Policy applies redaction rules to events before they are served
+type Policy struct {
+	rules []compiledRule
+}
+
+// redactors blanks a single field on an event copy
+var redactors = map[string]func(event *model.FlightEvent){
+	"callsign":      func(event *model.FlightEvent) { event.Callsign = "" },
+	"registration":  func(event *model.FlightEvent) { event.Registration = "" },
+	"longitude":     func(event *model.FlightEvent) { event.Longitude = nil },
+	"latitude":      func(event *model.FlightEvent) { event.Latitude = nil },
+	"baro_altitude": func(event *model.FlightEvent) { event.BaroAltitude = nil },
+	"geo_altitude":  func(event *model.FlightEvent) { event.GeoAltitude = nil },
+	"velocity":      func(event *model.FlightEvent) { event.Velocity = nil },
+	"true_track":    func(event *model.FlightEvent) { event.TrueTrack = nil },
+	"vertical_rate": func(event *model.FlightEvent) { event.VerticalRate = nil },
+	"squawk":        func(event *model.FlightEvent) { event.Squawk = nil },
+}
+
+// NewPolicy validates and compiles the given rules. An empty rule set yields a
+// policy that never redacts.
+func NewPolicy(rules []Rule) (*Policy, error) {
+	policy := &Policy{}
+
+	for i, rule := range rules {
+		compiled := compiledRule{
+			countries: make(map[string]bool),
+		}
+
+		if len(rule.Fields) == 0 {
+			return nil, fmt.Errorf("redaction rule %d has no fields", i)
+		}
+		for _, field := range rule.Fields {
+			if _, ok := redactors[field]; !ok {
+				return nil, fmt.Errorf("redaction rule %d has unsupported field %q", i, field)
+			}
+		}
+		compiled.fields = rule.Fields
+
+		for _, r := range rule.ICAO24Ranges {
+			parsed, err := parseICAORange(r)
+			if err != nil {
+				return nil, fmt.Errorf("redaction rule %d: %w", i, err)
+			}
+			compiled.ranges = append(compiled.ranges, parsed)
+		}
+
+		for _, country := range rule.Countries {
+			compiled.countries[country] = true
+		}
+
+		if len(compiled.ranges) == 0 && len(compiled.countries) == 0 {
+			return nil, fmt.Errorf("redaction rule %d matches no events", i)
+		}
+
+		policy.rules = append(policy.rules, compiled)
+	}
+
+	return policy, nil
+}
+
+// parseICAORange parses "low-high" hex ranges or a single hex address
+func parseICAORange(s string) (icaoRange, error) {
+	lowStr, highStr, found := strings.Cut(strings.TrimSpace(s), "-")
+	if !found {
+		highStr = lowStr
+	}
+
+	low, err := strconv.ParseUint(strings.TrimSpace(lowStr), 16, 32)
+	if err != nil {
+		return icaoRange{}, fmt.Errorf("invalid ICAO24 range %q", s)
+	}
+	high, err := strconv.ParseUint(strings.TrimSpace(highStr), 16, 32)
+	if err != nil {
+		return icaoRange{}, fmt.Errorf("invalid ICAO24 range %q", s)
+	}
+	if low > high {
+		return icaoRange{}, fmt.Errorf("inverted ICAO24 range %q", s)
+	}
+
+	return icaoRange{low: uint32(low), high: uint32(high)}, nil
+}
+
+// matches reports whether the rule applies to the event
+func (r compiledRule) matches(event *model.FlightEvent) bool {
+	if r.countries[event.OriginCountry] {
+		return true
+	}
+
+	if len(r.ranges) == 0 {
+		return false
+	}
+
+	addr, err := strconv.ParseUint(event.ICAO24, 16, 32)
+	if err != nil {
+		return false
+	}
+	for _, rng := range r.ranges {
+		if uint32(addr) >= rng.low && uint32(addr) <= rng.high {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns the event with matching fields blanked. Buffered events are
+// never modified: a copy is returned when redaction applies, otherwise the
+// original pointer.
+func (p *Policy) Apply(event *model.FlightEvent) *model.FlightEvent {
+	if p == nil || len(p.rules) == 0 || event == nil {
+		return event
+	}
+
+	var redacted *model.FlightEvent
+	for _, rule := range p.rules {
+		if !rule.matches(event) {
+			continue
+		}
+		if redacted == nil {
+			copied := *event
+			redacted = &copied
+		}
+		for _, field := range rule.fields {
+			redactors[field](redacted)
+		}
+	}
+
+	if redacted == nil {
+		return event
+	}
+	return redacted
+}
+
+// ApplyAll applies the policy to each event in the slice
+func (p *Policy) ApplyAll(events []*model.FlightEvent) []*model.FlightEvent {
+	if p == nil || len(p.rules) == 0 {
+		return events
+	}
+
+	out := make([]*model.FlightEvent, len(events))
+	for i, event := range events {
+		out[i] = p.Apply(event)
+	}
+	return out
+}
@@ -0,0 +1,159 @@
+package redaction
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
+
+// fullEvent returns an event with every redactable field set
+func fullEvent(icao24, country string) *model.FlightEvent {
+	return &model.FlightEvent{
+		ICAO24:        icao24,
+		Callsign:      "RCH123",
+		OriginCountry: country,
+		Registration:  "05-5140",
+		Latitude:      floatPtr(49.44),
+		Longitude:     floatPtr(7.6),
+		BaroAltitude:  floatPtr(9144),
+		GeoAltitude:   floatPtr(9300),
+		Velocity:      floatPtr(230),
+		TrueTrack:     floatPtr(270),
+		VerticalRate:  floatPtr(-2.5),
+		Squawk:        stringPtr("4521"),
+	}
+}
+
+// fields returns the event's JSON fields
+func fields(t *testing.T, event *model.FlightEvent) map[string]any {
+	t.Helper()
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestApplyBlanksEachField(t *testing.T) {
+	for field := range redactors {
+		t.Run(field, func(t *testing.T) {
+			policy, err := NewPolicy([]Rule{{ICAO24Ranges: []string{"ae0000-afffff"}, Fields: []string{field}}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			original := fullEvent("ae1234", "United States")
+			before := fields(t, original)
+			after := fields(t, policy.Apply(original))
+
+			// Blank means null for numbers and squawk, empty or omitted for strings
+			if v, ok := after[field]; ok && v != nil && v != "" {
+				t.Errorf("%s = %v after redaction, want it blank", field, v)
+			}
+			for name, want := range before {
+				if name == field {
+					continue
+				}
+				if got := after[name]; !jsonEqual(got, want) {
+					t.Errorf("%s = %v after redacting %s, want %v unchanged", name, got, field, want)
+				}
+			}
+			if got := fields(t, original); !jsonEqual(got[field], before[field]) {
+				t.Errorf("Apply modified the original event's %s", field)
+			}
+		})
+	}
+}
+
+func jsonEqual(a, b any) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return string(x) == string(y)
+}
+
+func TestApplyMatching(t *testing.T) {
+	policy, err := NewPolicy([]Rule{
+		{ICAO24Ranges: []string{"ae0000-afffff", "43c123"}, Fields: []string{"latitude", "longitude"}},
+		{Countries: []string{"Example Country"}, Fields: []string{"callsign"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		event        *model.FlightEvent
+		wantPosition bool
+		wantCallsign bool
+	}{
+		{"range start", fullEvent("ae0000", "United States"), false, true},
+		{"range end in upper case", fullEvent("AFFFFF", "United States"), false, true},
+		{"single address", fullEvent("43c123", "United Kingdom"), false, true},
+		{"just outside the range", fullEvent("b00000", "United States"), true, true},
+		{"country", fullEvent("3c6444", "Example Country"), true, false},
+		{"country is exact", fullEvent("3c6444", "example country"), true, true},
+		{"both rules combine", fullEvent("ae1234", "Example Country"), false, false},
+		{"non-hex address", fullEvent("zzzzzz", "Germany"), true, true},
+	}
+	for _, tt := range tests {
+		got := policy.Apply(tt.event)
+		if hasPosition := got.Latitude != nil && got.Longitude != nil; hasPosition != tt.wantPosition {
+			t.Errorf("%s: position kept = %v, want %v", tt.name, hasPosition, tt.wantPosition)
+		}
+		if hasCallsign := got.Callsign != ""; hasCallsign != tt.wantCallsign {
+			t.Errorf("%s: callsign kept = %v, want %v", tt.name, hasCallsign, tt.wantCallsign)
+		}
+		if tt.wantPosition && tt.wantCallsign && got != tt.event {
+			t.Errorf("%s: an unredacted event should be returned as is", tt.name)
+		}
+	}
+}
+
+func TestApplyWithoutRules(t *testing.T) {
+	event := fullEvent("ae1234", "United States")
+	for _, policy := range []*Policy{nil, {}} {
+		if policy.Apply(event) != event {
+			t.Error("a policy without rules should return the event as is")
+		}
+	}
+
+	events := []*model.FlightEvent{event}
+	if got := (&Policy{}).ApplyAll(events); &got[0] != &events[0] {
+		t.Error("ApplyAll without rules should return the slice as is")
+	}
+}
+
+func TestNewPolicyRejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr string
+	}{
+		{"no fields", Rule{Countries: []string{"Germany"}}, "has no fields"},
+		{"unsupported field", Rule{Countries: []string{"Germany"}, Fields: []string{"icao24"}}, `unsupported field "icao24"`},
+		{"no matchers", Rule{Fields: []string{"callsign"}}, "matches no events"},
+		{"bad hex", Rule{ICAO24Ranges: []string{"ae0000-zzzzzz"}, Fields: []string{"callsign"}}, "invalid ICAO24 range"},
+		{"inverted range", Rule{ICAO24Ranges: []string{"afffff-ae0000"}, Fields: []string{"callsign"}}, "inverted ICAO24 range"},
+	}
+	for _, tt := range tests {
+		_, err := NewPolicy([]Rule{tt.rule})
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: NewPolicy() = %v, want error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
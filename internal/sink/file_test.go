@@ -0,0 +1,49 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+func TestFileSinkAppendsNDJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+
+	// Reopening appends rather than truncating
+	for _, icao24 := range []string{"abc123", "def456"} {
+		s, err := NewFileSink(path)
+		if err != nil {
+			t.Fatalf("NewFileSink: %v", err)
+		}
+		if err := s.Write(context.Background(), []Record{{Key: icao24, Event: &model.FlightEvent{ICAO24: icao24}}}); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		if err := s.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var got []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event model.FlightEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("line %q is not an event: %v", scanner.Text(), err)
+		}
+		got = append(got, event.ICAO24)
+	}
+	if len(got) != 2 || got[0] != "abc123" || got[1] != "def456" {
+		t.Errorf("file holds %v, want [abc123 def456]", got)
+	}
+}
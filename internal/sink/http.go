@@ -0,0 +1,60 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPSink posts each batch to a URL as a JSON array of
+// {"key": ..., "event": ...} records
+type HTTPSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewHTTPSink creates a sink posting to the given URL
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{
+		url: url,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Write posts the batch; any non-2xx response is an error
+func (s *HTTPSink) Write(ctx context.Context, records []Record) error {
+	body, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to encode events: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post events: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("sink returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Close is a no-op; the HTTP client holds no resources worth releasing
+func (s *HTTPSink) Close() error {
+	return nil
+}
@@ -0,0 +1,47 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+func TestHTTPSinkPostsBatch(t *testing.T) {
+	var got []Record
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	records := []Record{
+		{Key: "Germany", Event: &model.FlightEvent{ICAO24: "3c6444"}},
+		{Key: "France", Event: &model.FlightEvent{ICAO24: "39de4f"}},
+	}
+	if err := NewHTTPSink(server.URL).Write(context.Background(), records); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if len(got) != 2 || got[0].Key != "Germany" || got[0].Event.ICAO24 != "3c6444" || got[1].Key != "France" {
+		t.Errorf("server received %+v", got)
+	}
+}
+
+func TestHTTPSinkFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewHTTPSink(server.URL).Write(context.Background(), []Record{{Key: "abc123", Event: &model.FlightEvent{ICAO24: "abc123"}}})
+	if err == nil {
+		t.Fatal("expected an error for a 503 response")
+	}
+}
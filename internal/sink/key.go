@@ -0,0 +1,59 @@
+package sink
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"flight-event-throttler/internal/model"
+)
+
+// unknownKey is the partition key for events missing the keyed field
+const unknownKey = "unknown"
+
+// regionCellDegrees is the size of the grid cells RegionKey partitions by
+const regionCellDegrees = 10
+
+// KeyFunc returns the partition key for an event
+type KeyFunc func(*model.FlightEvent) string
+
+// ICAO24Key partitions by aircraft, keeping each aircraft's events in order
+func ICAO24Key(event *model.FlightEvent) string {
+	if event.ICAO24 == "" {
+		return unknownKey
+	}
+	return strings.ToLower(event.ICAO24)
+}
+
+// CountryKey partitions by origin country
+func CountryKey(event *model.FlightEvent) string {
+	if event.OriginCountry == "" {
+		return unknownKey
+	}
+	return event.OriginCountry
+}
+
+// RegionKey partitions by the 10x10 degree grid cell of the position, named
+// by its south-west corner, e.g. "40,-10"
+func RegionKey(event *model.FlightEvent) string {
+	if event.Latitude == nil || event.Longitude == nil {
+		return unknownKey
+	}
+	lat := int(math.Floor(*event.Latitude/regionCellDegrees)) * regionCellDegrees
+	lon := int(math.Floor(*event.Longitude/regionCellDegrees)) * regionCellDegrees
+	return fmt.Sprintf("%d,%d", lat, lon)
+}
+
+// KeyFuncFor returns the key function for a sink.partition_key setting
+func KeyFuncFor(name string) (KeyFunc, error) {
+	switch name {
+	case "icao24":
+		return ICAO24Key, nil
+	case "country":
+		return CountryKey, nil
+	case "region":
+		return RegionKey, nil
+	default:
+		return nil, fmt.Errorf("unknown partition key %q", name)
+	}
+}
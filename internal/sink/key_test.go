@@ -0,0 +1,47 @@
+package sink
+
+import (
+	"testing"
+
+	"flight-event-throttler/internal/model"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestKeyFuncs(t *testing.T) {
+	located := &model.FlightEvent{
+		ICAO24:        "3C6444",
+		OriginCountry: "Germany",
+		Latitude:      floatPtr(50.03),
+		Longitude:     floatPtr(-8.57),
+	}
+	blank := &model.FlightEvent{}
+
+	tests := []struct {
+		name  string
+		event *model.FlightEvent
+		want  string
+	}{
+		{"icao24", located, "3c6444"},
+		{"icao24", blank, "unknown"},
+		{"country", located, "Germany"},
+		{"country", blank, "unknown"},
+		{"region", located, "50,-10"},
+		{"region", blank, "unknown"},
+	}
+	for _, tt := range tests {
+		keyFunc, err := KeyFuncFor(tt.name)
+		if err != nil {
+			t.Fatalf("KeyFuncFor(%q): %v", tt.name, err)
+		}
+		if got := keyFunc(tt.event); got != tt.want {
+			t.Errorf("%s key of %+v = %q, want %q", tt.name, tt.event, got, tt.want)
+		}
+	}
+
+	if _, err := KeyFuncFor("callsign"); err == nil {
+		t.Error("KeyFuncFor(\"callsign\") should fail")
+	}
+}
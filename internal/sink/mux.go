@@ -0,0 +1,203 @@
+package sink
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"flight-event-throttler/internal/model"
+	"flight-event-throttler/pkg/logger"
+)
+
+// maxRetryDelay caps the exponential backoff between retries
+const maxRetryDelay = 30 * time.Second
+
+// RetryPolicy controls how failed writes to one output are retried
+type RetryPolicy struct {
+	Retries   int           // Attempts after the first; 0 gives up after one failure
+	BaseDelay time.Duration // Wait before the first retry, doubled for each further retry
+}
+
+// Output is a named sink with its own retry policy and dead-letter sink
+type Output struct {
+	Name       string
+	Sink       Sink
+	Retry      RetryPolicy
+	DeadLetter Sink // Receives batches that exhaust their retries; nil drops them
+}
+
+// Multiplexer fans processed events out to several outputs. Each output has
+// its own queue and goroutine, so a slow or failing sink never delays the
+// pipeline or the other outputs; when an output's queue is full, new events
+// are dropped for that output only. Every event is keyed once, by ICAO24
+// unless SetKeyFunc chose another key.
+type Multiplexer struct {
+	workers       []*worker
+	keyFunc       KeyFunc
+	batchSize     int
+	flushInterval time.Duration
+	logger        logger.Interface
+	wg            sync.WaitGroup
+
+	onRetry      func()
+	onDeadLetter func(events int64)
+	onDropped    func(events int64)
+}
+
+type worker struct {
+	output Output
+	queue  chan Record
+}
+
+// NewMultiplexer creates a multiplexer writing batches of up to batchSize
+// events, flushing partial batches every flushInterval. Each output may fall
+// queueSize events behind.
+func NewMultiplexer(outputs []Output, batchSize int, flushInterval time.Duration, queueSize int, log logger.Interface) *Multiplexer {
+	m := &Multiplexer{
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		keyFunc:       ICAO24Key,
+		logger:        log,
+		onRetry:       func() {},
+		onDeadLetter:  func(int64) {},
+		onDropped:     func(int64) {},
+	}
+	for _, output := range outputs {
+		m.workers = append(m.workers, &worker{
+			output: output,
+			queue:  make(chan Record, queueSize),
+		})
+	}
+	return m
+}
+
+// SetKeyFunc sets how events are partitioned; call before Start
+func (m *Multiplexer) SetKeyFunc(fn KeyFunc) {
+	m.keyFunc = fn
+}
+
+// OnRetry registers a callback run before each retried write; call before Start
+func (m *Multiplexer) OnRetry(fn func()) {
+	m.onRetry = fn
+}
+
+// OnDeadLetter registers a callback run with the size of each batch written
+// to a dead-letter sink; call before Start
+func (m *Multiplexer) OnDeadLetter(fn func(events int64)) {
+	m.onDeadLetter = fn
+}
+
+// OnDropped registers a callback run with the number of events an output
+// lost, either to a full queue or to a failed delivery with no working
+// dead-letter sink; call before Start
+func (m *Multiplexer) OnDropped(fn func(events int64)) {
+	m.onDropped = fn
+}
+
+// Start launches one delivery goroutine per output
+func (m *Multiplexer) Start() {
+	for _, w := range m.workers {
+		m.wg.Add(1)
+		go m.run(w)
+	}
+}
+
+// Publish queues the event for every output without blocking
+func (m *Multiplexer) Publish(event *model.FlightEvent) {
+	record := Record{Key: m.keyFunc(event), Event: event}
+	for _, w := range m.workers {
+		select {
+		case w.queue <- record:
+		default:
+			m.onDropped(1)
+		}
+	}
+}
+
+// Close delivers what is still queued, including any retries, then closes
+// every sink. Publish must not be called after Close.
+func (m *Multiplexer) Close() {
+	for _, w := range m.workers {
+		close(w.queue)
+	}
+	m.wg.Wait()
+}
+
+func (m *Multiplexer) run(w *worker) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Record, 0, m.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		m.deliver(w.output, batch)
+		batch = make([]Record, 0, m.batchSize)
+	}
+
+	for {
+		select {
+		case record, ok := <-w.queue:
+			if !ok {
+				flush()
+				m.closeOutput(w.output)
+				return
+			}
+			batch = append(batch, record)
+			if len(batch) >= m.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// deliver writes the batch, retrying with exponential backoff, and hands it
+// to the dead-letter sink once the retries are exhausted
+func (m *Multiplexer) deliver(output Output, batch []Record) {
+	ctx := context.Background()
+	delay := output.Retry.BaseDelay
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = output.Sink.Write(ctx, batch); err == nil {
+			return
+		}
+		if attempt >= output.Retry.Retries {
+			break
+		}
+		m.onRetry()
+		m.logger.Debug("Sink %s write failed, retrying in %v: %v", output.Name, delay, err)
+		time.Sleep(delay)
+		delay = min(delay*2, maxRetryDelay)
+	}
+
+	attempts := output.Retry.Retries + 1
+	if output.DeadLetter == nil {
+		m.logger.Error("Sink %s failed after %d attempts, dropping %d events: %v", output.Name, attempts, len(batch), err)
+		m.onDropped(int64(len(batch)))
+		return
+	}
+	if dlqErr := output.DeadLetter.Write(ctx, batch); dlqErr != nil {
+		m.logger.Error("Sink %s failed after %d attempts and its dead-letter write failed, dropping %d events: %v", output.Name, attempts, len(batch), dlqErr)
+		m.onDropped(int64(len(batch)))
+		return
+	}
+	m.logger.Warn("Sink %s failed after %d attempts, dead-lettered %d events: %v", output.Name, attempts, len(batch), err)
+	m.onDeadLetter(int64(len(batch)))
+}
+
+func (m *Multiplexer) closeOutput(output Output) {
+	if err := output.Sink.Close(); err != nil {
+		m.logger.Error("Failed to close sink %s: %v", output.Name, err)
+	}
+	if output.DeadLetter != nil {
+		if err := output.DeadLetter.Close(); err != nil {
+			m.logger.Error("Failed to close dead-letter sink for %s: %v", output.Name, err)
+		}
+	}
+}
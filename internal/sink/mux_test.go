@@ -0,0 +1,195 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"flight-event-throttler/internal/model"
+	"flight-event-throttler/pkg/logger"
+)
+
+// fakeSink records delivered events, fails its first failures writes and
+// waits on block, when set, before each write
+type fakeSink struct {
+	mu       sync.Mutex
+	failures int
+	writes   int
+	records  []Record
+	block    chan struct{}
+	closed   bool
+}
+
+func (s *fakeSink) Write(ctx context.Context, records []Record) error {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writes++
+	if s.writes <= s.failures {
+		return errors.New("unavailable")
+	}
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSink) delivered() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records)
+}
+
+func newTestMux(outputs []Output, queueSize int) (*Multiplexer, *atomic.Int64, *atomic.Int64, *atomic.Int64) {
+	m := NewMultiplexer(outputs, 10, time.Hour, queueSize, logger.NewWithWriter("ERROR", io.Discard))
+	var retries, deadLettered, dropped atomic.Int64
+	m.OnRetry(func() { retries.Add(1) })
+	m.OnDeadLetter(func(n int64) { deadLettered.Add(n) })
+	m.OnDropped(func(n int64) { dropped.Add(n) })
+	m.Start()
+	return m, &retries, &deadLettered, &dropped
+}
+
+func publish(m *Multiplexer, n int) {
+	for i := 0; i < n; i++ {
+		m.Publish(&model.FlightEvent{ICAO24: "abc123"})
+	}
+}
+
+func TestMultiplexerRetriesUntilWriteSucceeds(t *testing.T) {
+	out := &fakeSink{failures: 2}
+	dlq := &fakeSink{}
+	m, retries, deadLettered, _ := newTestMux([]Output{{
+		Name: "primary", Sink: out, DeadLetter: dlq,
+		Retry: RetryPolicy{Retries: 3, BaseDelay: time.Millisecond},
+	}}, 100)
+
+	publish(m, 5)
+	m.Close()
+
+	if out.delivered() != 5 {
+		t.Errorf("delivered %d events, want 5", out.delivered())
+	}
+	if retries.Load() != 2 {
+		t.Errorf("retries = %d, want 2", retries.Load())
+	}
+	if deadLettered.Load() != 0 || dlq.delivered() != 0 {
+		t.Errorf("dead-lettered %d events, want 0", deadLettered.Load())
+	}
+	if !out.closed || !dlq.closed {
+		t.Error("Close should close the sink and its dead-letter sink")
+	}
+}
+
+func TestMultiplexerDeadLettersAfterRetriesExhausted(t *testing.T) {
+	out := &fakeSink{failures: 100}
+	dlq := &fakeSink{}
+	m, retries, deadLettered, dropped := newTestMux([]Output{{
+		Name: "primary", Sink: out, DeadLetter: dlq,
+		Retry: RetryPolicy{Retries: 2, BaseDelay: time.Millisecond},
+	}}, 100)
+
+	publish(m, 4)
+	m.Close()
+
+	if out.writes != 3 {
+		t.Errorf("attempted %d writes, want 3", out.writes)
+	}
+	if retries.Load() != 2 {
+		t.Errorf("retries = %d, want 2", retries.Load())
+	}
+	if dlq.delivered() != 4 || deadLettered.Load() != 4 {
+		t.Errorf("dead-lettered %d events (counted %d), want 4", dlq.delivered(), deadLettered.Load())
+	}
+	if dropped.Load() != 0 {
+		t.Errorf("dropped = %d, want 0", dropped.Load())
+	}
+}
+
+func TestMultiplexerDropsWithoutDeadLetterSink(t *testing.T) {
+	out := &fakeSink{failures: 100}
+	m, _, deadLettered, dropped := newTestMux([]Output{{Name: "primary", Sink: out}}, 100)
+
+	publish(m, 3)
+	m.Close()
+
+	if out.writes != 1 {
+		t.Errorf("attempted %d writes, want 1 with no retries", out.writes)
+	}
+	if dropped.Load() != 3 || deadLettered.Load() != 0 {
+		t.Errorf("dropped %d, dead-lettered %d; want 3 and 0", dropped.Load(), deadLettered.Load())
+	}
+}
+
+func TestMultiplexerIsolatesStuckOutput(t *testing.T) {
+	stuck := &fakeSink{block: make(chan struct{})}
+	healthy := &fakeSink{}
+	m, _, _, dropped := newTestMux([]Output{
+		{Name: "stuck", Sink: stuck},
+		{Name: "healthy", Sink: healthy},
+	}, 15)
+
+	// The stuck output holds its first batch of 10 in a write, then fills
+	// its queue of 15 and drops 5; the healthy output keeps delivering
+	for want := 10; want <= 30; want += 10 {
+		publish(m, 10)
+		deadline := time.Now().Add(2 * time.Second)
+		for healthy.delivered() < want && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if healthy.delivered() != want {
+			t.Fatalf("healthy output got %d events, want %d", healthy.delivered(), want)
+		}
+	}
+	if dropped.Load() != 5 {
+		t.Errorf("dropped = %d, want 5 for the stuck output only", dropped.Load())
+	}
+
+	close(stuck.block)
+	m.Close()
+	if stuck.delivered() != 25 {
+		t.Errorf("stuck output got %d events after unblocking, want 25", stuck.delivered())
+	}
+}
+
+func TestMultiplexerFlushesPartialBatchOnInterval(t *testing.T) {
+	out := &fakeSink{}
+	m := NewMultiplexer([]Output{{Name: "primary", Sink: out}}, 100, 10*time.Millisecond, 100, logger.NewWithWriter("ERROR", io.Discard))
+	m.Start()
+	defer m.Close()
+
+	publish(m, 3)
+
+	deadline := time.Now().Add(time.Second)
+	for out.delivered() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if out.delivered() != 3 {
+		t.Errorf("delivered %d events before close, want 3", out.delivered())
+	}
+}
+
+func TestMultiplexerKeysRecords(t *testing.T) {
+	out := &fakeSink{}
+	m := NewMultiplexer([]Output{{Name: "primary", Sink: out}}, 10, time.Hour, 10, logger.NewWithWriter("ERROR", io.Discard))
+	m.SetKeyFunc(CountryKey)
+	m.Start()
+
+	m.Publish(&model.FlightEvent{ICAO24: "3c6444", OriginCountry: "Germany"})
+	m.Close()
+
+	if len(out.records) != 1 || out.records[0].Key != "Germany" {
+		t.Errorf("records = %+v, want one keyed Germany", out.records)
+	}
+}
@@ -0,0 +1,21 @@
+package sink
+
+import (
+	"context"
+
+	"flight-event-throttler/internal/model"
+)
+
+// Record is a processed event with its partition key. Destinations that
+// partition their data, such as a keyed topic behind an HTTP endpoint, use
+// the key to keep related events together and in order.
+type Record struct {
+	Key   string             `json:"key"`
+	Event *model.FlightEvent `json:"event"`
+}
+
+// Sink delivers batches of records to an external destination
+type Sink interface {
+	Write(ctx context.Context, records []Record) error
+	Close() error
+}
@@ -0,0 +1,70 @@
+package logger
+
+import "fmt"
+
+// Interface is the logging surface the rest of the application depends on.
+// The built-in *Logger implements it; host applications embedding the
+// throttler can supply their own logging stack through an adapter.
+type Interface interface {
+	Debug(format string, v ...interface{})
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
+
+var _ Interface = (*Logger)(nil)
+
+// Printfer is implemented by loggers with printf-style leveled methods,
+// such as zap's *SugaredLogger
+type Printfer interface {
+	Debugf(template string, args ...interface{})
+	Infof(template string, args ...interface{})
+	Warnf(template string, args ...interface{})
+	Errorf(template string, args ...interface{})
+}
+
+// FromPrintf adapts a printf-style leveled logger to Interface, e.g.
+//
+//	log := logger.FromPrintf(zapLogger.Sugar())
+func FromPrintf(p Printfer) Interface {
+	return printfAdapter{p: p}
+}
+
+type printfAdapter struct {
+	p Printfer
+}
+
+func (a printfAdapter) Debug(format string, v ...interface{}) { a.p.Debugf(format, v...) }
+func (a printfAdapter) Info(format string, v ...interface{})  { a.p.Infof(format, v...) }
+func (a printfAdapter) Warn(format string, v ...interface{})  { a.p.Warnf(format, v...) }
+func (a printfAdapter) Error(format string, v ...interface{}) { a.p.Errorf(format, v...) }
+
+// Funcs adapts per-level message functions to Interface. It suits loggers
+// without printf-style methods, such as zerolog:
+//
+//	log := logger.Funcs{
+//		DebugFn: func(msg string) { zl.Debug().Msg(msg) },
+//		InfoFn:  func(msg string) { zl.Info().Msg(msg) },
+//		WarnFn:  func(msg string) { zl.Warn().Msg(msg) },
+//		ErrorFn: func(msg string) { zl.Error().Msg(msg) },
+//	}
+//
+// Nil functions discard messages at that level.
+type Funcs struct {
+	DebugFn func(msg string)
+	InfoFn  func(msg string)
+	WarnFn  func(msg string)
+	ErrorFn func(msg string)
+}
+
+func (f Funcs) Debug(format string, v ...interface{}) { call(f.DebugFn, format, v...) }
+func (f Funcs) Info(format string, v ...interface{})  { call(f.InfoFn, format, v...) }
+func (f Funcs) Warn(format string, v ...interface{})  { call(f.WarnFn, format, v...) }
+func (f Funcs) Error(format string, v ...interface{}) { call(f.ErrorFn, format, v...) }
+
+// call formats the message and passes it to fn if set
+func call(fn func(msg string), format string, v ...interface{}) {
+	if fn != nil {
+		fn(fmt.Sprintf(format, v...))
+	}
+}
@@ -1,59 +1,373 @@
 package logger
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Level int
 
 const (
-	DEBUG Level = iota
+	TRACE Level = iota
+	DEBUG
 	INFO
+	WARN
 	ERROR
 )
 
+// String returns the level name used in log output
+func (lv Level) String() string {
+	switch lv {
+	case TRACE:
+		return "TRACE"
+	case DEBUG:
+		return "DEBUG"
+	case WARN:
+		return "WARN"
+	case ERROR:
+		return "ERROR"
+	default:
+		return "INFO"
+	}
+}
+
 type Logger struct {
-	level       Level
+	level       *atomic.Int32 // Current Level; shared with derived loggers
 	infoLogger  *log.Logger
+	warnLogger  *log.Logger
 	errorLogger *log.Logger
 	debugLogger *log.Logger
+	traceLogger *log.Logger
+
+	json       bool          // Write one JSON object per line instead of prefixed text
+	out        io.Writer     // Destination for DEBUG, INFO and WARN
+	errOut     io.Writer     // Destination for ERROR
+	mu         *sync.Mutex   // Serializes JSON writes; shared with derived loggers
+	callerSkip int           // Extra stack frames to skip when reporting file:line
+	fields     []interface{} // Key/value pairs added to every line, unique keys
 }
 
+// callDepth is the number of frames between log.Logger.Output and the code
+// that called a public logging method: Output's caller (log or logw), then the
+// public method, then its caller
+const callDepth = 3
+
 func New(level string) *Logger {
+	l := NewWithWriter(level, os.Stdout)
+	l.SetErrorWriter(os.Stderr)
+	return l
+}
+
+// NewWithWriter creates a text logger that writes every level to out, e.g. a
+// file, a rotating writer such as lumberjack, or a buffer in tests. Use
+// SetErrorWriter to send ERROR lines elsewhere.
+func NewWithWriter(level string, out io.Writer) *Logger {
 	l := &Logger{
-		infoLogger:  log.New(os.Stdout, "[INFO] ", log.Ldate|log.Ltime|log.Lshortfile),
-		errorLogger: log.New(os.Stderr, "[ERROR] ", log.Ldate|log.Ltime|log.Lshortfile),
-		debugLogger: log.New(os.Stdout, "[DEBUG] ", log.Ldate|log.Ltime|log.Lshortfile),
+		infoLogger:  log.New(out, "[INFO] ", log.Ldate|log.Ltime|log.Lshortfile),
+		warnLogger:  log.New(out, "[WARN] ", log.Ldate|log.Ltime|log.Lshortfile),
+		errorLogger: log.New(out, "[ERROR] ", log.Ldate|log.Ltime|log.Lshortfile),
+		debugLogger: log.New(out, "[DEBUG] ", log.Ldate|log.Ltime|log.Lshortfile),
+		traceLogger: log.New(out, "[TRACE] ", log.Ldate|log.Ltime|log.Lshortfile),
+		level:       &atomic.Int32{},
+		out:         out,
+		errOut:      out,
+		mu:          &sync.Mutex{},
 	}
+	l.SetLevel(level)
+	return l
+}
+
+// NewJSON creates a logger that writes one JSON object per line with "ts",
+// "level" and "msg" keys plus any fields passed to the *w methods, for log
+// pipelines that ingest structured logs
+func NewJSON(level string) *Logger {
+	l := NewJSONWithWriter(level, os.Stdout)
+	l.SetErrorWriter(os.Stderr)
+	return l
+}
+
+// NewJSONWithWriter creates a JSON logger that writes every level to out
+func NewJSONWithWriter(level string, out io.Writer) *Logger {
+	l := NewWithWriter(level, out)
+	l.json = true
+	return l
+}
+
+// WithCallerSkip returns a logger that reports the caller n frames further up
+// the stack, so helpers that wrap the logger can attribute lines to their own
+// callers. It shares the writers and level of l.
+func (l *Logger) WithCallerSkip(n int) *Logger {
+	child := l.derive()
+	child.callerSkip += n
+	return child
+}
+
+// derive copies l for a child logger. The level and JSON write lock stay
+// shared, but each child gets its own log.Loggers so SetErrorWriter on the
+// child leaves the parent alone.
+func (l *Logger) derive() *Logger {
+	child := *l
+	child.infoLogger = cloneLogger(l.infoLogger)
+	child.warnLogger = cloneLogger(l.warnLogger)
+	child.errorLogger = cloneLogger(l.errorLogger)
+	child.debugLogger = cloneLogger(l.debugLogger)
+	child.traceLogger = cloneLogger(l.traceLogger)
+	return &child
+}
+
+func cloneLogger(lg *log.Logger) *log.Logger {
+	return log.New(lg.Writer(), lg.Prefix(), lg.Flags())
+}
+
+// With returns a child logger that adds fields to every line it writes, e.g.
+//
+//	fetcherLog := log.With(map[string]any{"component": "fetcher"})
+//
+// The child shares the level and writers of l, though SetErrorWriter on the
+// child only affects the child. Fields override any inherited
+// field with the same key, and fields passed to the *w methods override both.
+// Keys from a single call are added in sorted order.
+func (l *Logger) With(fields map[string]any) *Logger {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	child := l.derive()
+	child.fields = append([]interface{}(nil), l.fields...)
+	for _, key := range keys {
+		child.fields = setField(child.fields, key, fields[key])
+	}
+	return child
+}
+
+// setField replaces the value for key in kv, or appends the pair if absent
+func setField(kv []interface{}, key string, value interface{}) []interface{} {
+	for i := 0; i < len(kv); i += 2 {
+		if k, _ := fieldAt(kv, i); k == key {
+			kv[i+1] = value
+			return kv
+		}
+	}
+	return append(kv, key, value)
+}
+
+// withFields returns the logger's fields followed by kv, dropping logger
+// fields that kv overrides
+func (l *Logger) withFields(kv []interface{}) []interface{} {
+	if len(l.fields) == 0 {
+		return kv
+	}
+
+	merged := make([]interface{}, 0, len(l.fields)+len(kv))
+	for i := 0; i < len(l.fields); i += 2 {
+		key, value := fieldAt(l.fields, i)
+		if !hasKey(kv, key) {
+			merged = append(merged, key, value)
+		}
+	}
+	return append(merged, kv...)
+}
+
+// hasKey reports whether key appears as a key in kv
+func hasKey(kv []interface{}, key string) bool {
+	for i := 0; i < len(kv); i += 2 {
+		if k, _ := fieldAt(kv, i); k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// SetErrorWriter sends ERROR lines to w instead of the main writer. It must
+// be called before the logger is used concurrently.
+func (l *Logger) SetErrorWriter(w io.Writer) {
+	l.errOut = w
+	l.errorLogger.SetOutput(w)
+}
+
+// SetLevel changes the minimum level logged, e.g. to raise verbosity while
+// the service is running. It is safe to call concurrently with logging and
+// applies to l and every logger derived from it with With or WithCallerSkip.
+// Level names are case-insensitive; unknown names select INFO.
+func (l *Logger) SetLevel(level string) {
+	l.level.Store(int32(parseLevel(level)))
+}
+
+// GetLevel returns the minimum level currently logged
+func (l *Logger) GetLevel() Level {
+	return Level(l.level.Load())
+}
 
-	switch level {
+func parseLevel(level string) Level {
+	switch strings.ToLower(level) {
+	case "trace":
+		return TRACE
 	case "debug":
-		l.level = DEBUG
+		return DEBUG
+	case "warn":
+		return WARN
 	case "error":
-		l.level = ERROR
+		return ERROR
 	default:
-		l.level = INFO
+		return INFO
 	}
-
-	return l
 }
 
 func (l *Logger) log(level Level, logger *log.Logger, format string, v ...interface{}) {
-	if level >= l.level {
-		logger.Output(3, fmt.Sprintf(format, v...))
+	if level < l.GetLevel() {
+		return
+	}
+
+	msg := fmt.Sprintf(format, v...)
+	if l.json {
+		l.writeJSON(level, msg, l.fields)
+		return
+	}
+	logger.Output(callDepth+l.callerSkip, formatText(msg, l.fields))
+}
+
+// logw logs msg with key/value fields (must be called from a public method
+// so the caller's file and line are reported in text mode)
+func (l *Logger) logw(level Level, logger *log.Logger, msg string, kv []interface{}) {
+	if level < l.GetLevel() {
+		return
+	}
+
+	kv = l.withFields(kv)
+	if l.json {
+		l.writeJSON(level, msg, kv)
+		return
+	}
+	logger.Output(callDepth+l.callerSkip, formatText(msg, kv))
+}
+
+// formatText appends key/value fields to msg as " key=value" pairs
+func formatText(msg string, kv []interface{}) string {
+	if len(kv) == 0 {
+		return msg
+	}
+
+	var b bytes.Buffer
+	b.WriteString(msg)
+	for i := 0; i < len(kv); i += 2 {
+		key, value := fieldAt(kv, i)
+		fmt.Fprintf(&b, " %s=%v", key, value)
+	}
+	return b.String()
+}
+
+// writeJSON writes a single log line. Keys keep their order: ts, level, msg,
+// then fields as given.
+func (l *Logger) writeJSON(level Level, msg string, kv []interface{}) {
+	var b bytes.Buffer
+	b.WriteString(`{"ts":`)
+	writeJSONValue(&b, time.Now().UTC().Format(time.RFC3339Nano))
+	b.WriteString(`,"level":`)
+	writeJSONValue(&b, level.String())
+	b.WriteString(`,"msg":`)
+	writeJSONValue(&b, msg)
+	for i := 0; i < len(kv); i += 2 {
+		key, value := fieldAt(kv, i)
+		b.WriteByte(',')
+		writeJSONValue(&b, key)
+		b.WriteByte(':')
+		writeJSONValue(&b, value)
+	}
+	b.WriteString("}\n")
+
+	out := l.out
+	if level == ERROR {
+		out = l.errOut
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out.Write(b.Bytes())
+}
+
+// fieldAt returns the key/value pair starting at kv[i]. Non-string keys are
+// formatted with %v and a trailing key without a value gets a nil value.
+func fieldAt(kv []interface{}, i int) (string, interface{}) {
+	key, ok := kv[i].(string)
+	if !ok {
+		key = fmt.Sprint(kv[i])
+	}
+	if i+1 >= len(kv) {
+		return key, nil
 	}
+	return key, kv[i+1]
+}
+
+// writeJSONValue encodes v, falling back to its %v string form when it can't
+// be marshaled, so a bad field never drops the whole line
+func writeJSONValue(b *bytes.Buffer, v interface{}) {
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("%v", v))
+	}
+	b.Write(data)
 }
 
 func (l *Logger) Info(format string, v ...interface{}) {
 	l.log(INFO, l.infoLogger, format, v...)
 }
 
+func (l *Logger) Warn(format string, v ...interface{}) {
+	l.log(WARN, l.warnLogger, format, v...)
+}
+
 func (l *Logger) Error(format string, v ...interface{}) {
 	l.log(ERROR, l.errorLogger, format, v...)
 }
 
 func (l *Logger) Debug(format string, v ...interface{}) {
 	l.log(DEBUG, l.debugLogger, format, v...)
-}
\ No newline at end of file
+}
+
+// Trace logs at TRACE, below DEBUG, for high-volume detail such as individual
+// rate limiter decisions
+func (l *Logger) Trace(format string, v ...interface{}) {
+	l.log(TRACE, l.traceLogger, format, v...)
+}
+
+// Infow logs msg with alternating key/value fields, e.g.
+//
+//	log.Infow("poll complete", "events", 120, "latency_ms", 85)
+func (l *Logger) Infow(msg string, kv ...interface{}) {
+	l.logw(INFO, l.infoLogger, msg, kv)
+}
+
+// Warnw logs msg at WARN with alternating key/value fields
+func (l *Logger) Warnw(msg string, kv ...interface{}) {
+	l.logw(WARN, l.warnLogger, msg, kv)
+}
+
+// Errorw logs msg at ERROR with alternating key/value fields
+func (l *Logger) Errorw(msg string, kv ...interface{}) {
+	l.logw(ERROR, l.errorLogger, msg, kv)
+}
+
+// Debugw logs msg at DEBUG with alternating key/value fields
+func (l *Logger) Debugw(msg string, kv ...interface{}) {
+	l.logw(DEBUG, l.debugLogger, msg, kv)
+}
+
+// Tracew logs msg at TRACE with alternating key/value fields
+func (l *Logger) Tracew(msg string, kv ...interface{}) {
+	l.logw(TRACE, l.traceLogger, msg, kv)
+}
@@ -0,0 +1,271 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// decodeLines parses each line of out as a JSON object
+func decodeLines(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("line %q is not valid JSON: %v", line, err)
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestJSONLinesAreValid(t *testing.T) {
+	var out bytes.Buffer
+	l := NewJSONWithWriter("INFO", &out)
+
+	l.Info("polled %d aircraft", 120)
+	l.Infow("poll complete", "events", 120, "source", `opensky "live"`, "err", errors.New("partial"))
+	l.Errorw("unencodable field", "fn", func() {})
+
+	lines := decodeLines(t, &out)
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), out.String())
+	}
+
+	first := lines[0]
+	if first["level"] != "INFO" || first["msg"] != "polled 120 aircraft" {
+		t.Errorf("first line = %v, want INFO \"polled 120 aircraft\"", first)
+	}
+	if ts, _ := first["ts"].(string); ts == "" {
+		t.Error("first line has no ts")
+	} else if _, err := time.Parse(time.RFC3339Nano, ts); err != nil {
+		t.Errorf("ts %q is not RFC 3339: %v", ts, err)
+	}
+
+	fields := lines[1]
+	if fields["events"] != float64(120) || fields["source"] != `opensky "live"` || fields["err"] != "partial" {
+		t.Errorf("second line fields = %v", fields)
+	}
+	if lines[2]["level"] != "ERROR" || lines[2]["fn"] == nil {
+		t.Errorf("third line = %v, want ERROR with a fallback fn value", lines[2])
+	}
+	if strings.Contains(out.String(), "logger_test.go") {
+		t.Error("JSON output carries a text-mode file:line prefix")
+	}
+}
+
+func TestJSONLevelFiltering(t *testing.T) {
+	var out bytes.Buffer
+	l := NewJSONWithWriter("WARN", &out)
+
+	l.Trace("trace")
+	l.Debug("debug")
+	l.Info("info")
+	l.Infow("infow")
+	l.Warn("warn")
+	l.Warnw("warnw")
+	l.Error("error")
+
+	var got []string
+	for _, line := range decodeLines(t, &out) {
+		got = append(got, line["msg"].(string))
+	}
+	if want := "warn,warnw,error"; strings.Join(got, ",") != want {
+		t.Errorf("logged %v, want %s", got, want)
+	}
+}
+
+func TestNewWithWriterCapturesOutput(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewWithWriter("INFO", &out)
+
+	l.Debug("hidden %d", 1)
+	l.Info("polled %d aircraft", 120)
+	l.Warnw("slow poll", "latency_ms", 850)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), out.String())
+	}
+	if !strings.HasPrefix(lines[0], "[INFO] ") || !strings.HasSuffix(lines[0], ": polled 120 aircraft") {
+		t.Errorf("info line = %q, want [INFO] prefix and the message", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "[WARN] ") || !strings.HasSuffix(lines[1], ": slow poll latency_ms=850") {
+		t.Errorf("warn line = %q, want [WARN] prefix, message and fields", lines[1])
+	}
+	if strings.Contains(out.String(), "hidden") {
+		t.Error("DEBUG line written at INFO level")
+	}
+
+	// ERROR lines can go to their own writer
+	l.SetErrorWriter(&errOut)
+	out.Reset()
+	l.Error("fetch failed")
+	if out.Len() != 0 {
+		t.Errorf("error line written to the main writer: %q", out.String())
+	}
+	if !strings.HasPrefix(errOut.String(), "[ERROR] ") || !strings.Contains(errOut.String(), "fetch failed") {
+		t.Errorf("error writer got %q", errOut.String())
+	}
+}
+
+// here returns the file:line of its caller as log.Lshortfile prints it
+func here() string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s:%d", file[strings.LastIndex(file, "/")+1:], line)
+}
+
+// logHelper wraps the logger the way application helpers do
+func logHelper(l *Logger, msg string) {
+	l.WithCallerSkip(1).Info(msg)
+}
+
+func TestCallerIsDirectCallSite(t *testing.T) {
+	var out bytes.Buffer
+	l := NewWithWriter("INFO", &out)
+	child := l.With(map[string]any{"component": "test"})
+
+	tests := []struct {
+		name string
+		log  func() string
+	}{
+		{"printf", func() string { l.Info("direct"); return here() }},
+		{"key/value", func() string { l.Infow("direct"); return here() }},
+		{"child", func() string { child.Info("direct"); return here() }},
+		{"wrapped with caller skip", func() string { logHelper(l, "wrapped"); return here() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out.Reset()
+			want := tt.log()
+			if !strings.Contains(out.String(), " "+want+": ") {
+				t.Errorf("logged %q, want caller %s", out.String(), want)
+			}
+		})
+	}
+}
+
+func TestWithAddsInheritedFields(t *testing.T) {
+	var out bytes.Buffer
+	l := NewJSONWithWriter("INFO", &out)
+	fetcher := l.With(map[string]any{"component": "fetcher", "region": "eu"})
+	poller := fetcher.With(map[string]any{"region": "us", "poll": 3})
+
+	l.Info("parent")
+	fetcher.Info("child")
+	poller.Infow("grandchild", "poll", 4)
+
+	lines := decodeLines(t, &out)
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	if _, ok := lines[0]["component"]; ok {
+		t.Errorf("parent line %v carries a child's field", lines[0])
+	}
+	if lines[1]["component"] != "fetcher" || lines[1]["region"] != "eu" {
+		t.Errorf("child line = %v, want component=fetcher region=eu", lines[1])
+	}
+
+	// Inherited fields stay, overridden ones take the newest value
+	if got := lines[2]; got["component"] != "fetcher" || got["region"] != "us" || got["poll"] != float64(4) {
+		t.Errorf("grandchild line = %v, want component=fetcher region=us poll=4", got)
+	}
+	if n := strings.Count(out.String(), `"region"`); n != 2 {
+		t.Errorf("region appears %d times, want once per child line", n)
+	}
+}
+
+func TestWithSharesLevelButNotErrorWriter(t *testing.T) {
+	var out, childErr bytes.Buffer
+	l := NewWithWriter("INFO", &out)
+	child := l.With(map[string]any{"component": "fetcher"})
+
+	l.SetLevel("ERROR")
+	child.Info("filtered")
+	if out.Len() != 0 {
+		t.Errorf("child logged %q after the parent raised the level", out.String())
+	}
+
+	child.SetErrorWriter(&childErr)
+	l.Error("parent error")
+	child.Error("child error")
+	if !strings.Contains(out.String(), "parent error") || strings.Contains(out.String(), "child error") {
+		t.Errorf("parent writer got %q, want only the parent's error", out.String())
+	}
+	if !strings.Contains(childErr.String(), "child error component=fetcher") || strings.Contains(childErr.String(), "parent error") {
+		t.Errorf("child error writer got %q, want only the child's error", childErr.String())
+	}
+}
+
+func TestSetLevelAtRuntime(t *testing.T) {
+	var out bytes.Buffer
+	l := NewJSONWithWriter("INFO", &out)
+
+	logAll := func() string {
+		out.Reset()
+		l.Trace("trace")
+		l.Debug("debug")
+		l.Info("info")
+		l.Warn("warn")
+		l.Error("error")
+
+		var got []string
+		for _, line := range decodeLines(t, &out) {
+			got = append(got, line["msg"].(string))
+		}
+		return strings.Join(got, ",")
+	}
+
+	tests := []struct {
+		level string
+		want  Level
+		logs  string
+	}{
+		{"INFO", INFO, "info,warn,error"},
+		{"trace", TRACE, "trace,debug,info,warn,error"},
+		{"Debug", DEBUG, "debug,info,warn,error"},
+		{"ERROR", ERROR, "error"},
+		{"bogus", INFO, "info,warn,error"},
+	}
+	for _, tt := range tests {
+		l.SetLevel(tt.level)
+		if got := l.GetLevel(); got != tt.want {
+			t.Errorf("SetLevel(%q): GetLevel() = %v, want %v", tt.level, got, tt.want)
+		}
+		if got := logAll(); got != tt.logs {
+			t.Errorf("at %s logged %q, want %q", tt.level, got, tt.logs)
+		}
+	}
+}
+
+func TestSetLevelConcurrentWithLogging(t *testing.T) {
+	l := NewJSONWithWriter("INFO", io.Discard)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				l.Trace("trace %d", j)
+				l.Info("info %d", j)
+			}
+		}()
+	}
+	for _, level := range []string{"TRACE", "ERROR", "DEBUG", "INFO"} {
+		l.SetLevel(level)
+	}
+	wg.Wait()
+}